@@ -4,50 +4,119 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"gs/libs"
+	"io/fs"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
 type DBService interface {
-	Add(alias string, path string) error
+	Add(alias string, path string, opts libs.AddOptions) (libs.AddResult, error)
 }
 
 type FileService interface {
 	GetCurrentPath() (string, error)
-	GetParentFolderName(path string) string
+	GetFolderName(path string) string
 	CheckIfPathExists(path string) (bool, error)
+	FindRepoRoot(path string) (string, error)
+	DetectGitRepository(path string) (libs.GitRepository, error)
+	NormalizePath(path string, resolveSymlinks bool) (string, error)
+	RepoIdentity(path string) (libs.RepoIdentity, error)
+}
+
+type PathNormalizer interface {
+	NormalizePath(path string, resolveSymlinks bool) (string, error)
 }
 
 func NewAddCmd(dbService DBService, fileService FileService) *cobra.Command {
-	return &cobra.Command{
+	var opts libs.AddOptions
+	var allowNonGit, noResolveSymlinks bool
+
+	addCmd := &cobra.Command{
 		Use:   "add [alias] [path]",
 		Short: "Add a Git project to gitswitch with an alias",
 		Long: `Add a Git project to gitswitch so you can quickly switch to it later using 'gs <alias>'.
 
 Usage Scenarios:
-  1. gs add                → Adds the current directory using its folder name as alias (if it's a Git repo)
+  1. gs add                → Adds the root of the Git repo you are in, using its folder name as alias
   2. gs add <alias>        → Adds the current directory with a custom alias
   3. gs add <alias> <path> → Adds the specified path with the given alias
 
-In all cases, the path is saved and can be accessed later with 'gs <alias>'.`,
+In all cases, the path is saved and can be accessed later with 'gs <alias>'.
+Paths are stored in canonical form: "~" and environment variables are
+expanded, the path is made absolute and symlinks are resolved. Pass
+--no-resolve-symlinks to keep symlinks as they are.
+
+The path must be inside a Git repository (a regular, bare, worktree or
+submodule checkout, or the repository named by GIT_DIR). Pass --allow-non-git
+to register any other directory; 'gs doctor' then accepts it as non-git.
+
+The remote URL and first commit of the repository are stored too, so that
+'gs relocate' can find it again if it moves.
+
+An existing alias is never overwritten silently. Use --force to overwrite it,
+or --suffix to store the path as <alias>-2, <alias>-3, ... instead.`,
 		Args: cobra.RangeArgs(0, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			alias, path, err := determineAliasAndPath(args, fileService)
+			alias, path, err := determineAliasAndPath(args, fileService, !noResolveSymlinks)
 			if err != nil {
 				return err
 			}
 
-			if err := dbService.Add(alias, path); err != nil {
+			if allowNonGit {
+				// Remembered so that 'gs doctor' accepts the directory
+				_, err := fileService.DetectGitRepository(path)
+				opts.NonGit = errors.Is(err, libs.ErrNotGitRepository)
+			} else if err := ensureGitRepository(path, fileService); err != nil {
+				return err
+			}
+
+			// Best effort: without an identity only 'gs relocate' loses out
+			if identity, err := fileService.RepoIdentity(path); err == nil {
+				opts.Identity = identity
+			}
+
+			result, err := dbService.Add(alias, path, opts)
+			if errors.Is(err, libs.ErrAliasExists) {
+				return fmt.Errorf("%w (use --force to overwrite or --suffix to add it as %s-N)", err, alias)
+			}
+			if err != nil {
 				return fmt.Errorf("failed to add %s with alias %s", path, alias)
 			}
 
+			if result.Alias != alias {
+				fmt.Fprintf(cmd.ErrOrStderr(), "added %s as %s\n", path, result.Alias)
+			}
+			if len(result.ExistingAliases) > 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "%s is also registered as %s\n", path, strings.Join(result.ExistingAliases, ", "))
+			}
+
 			return nil
 		},
 	}
+
+	addCmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "overwrite an existing alias")
+	addCmd.Flags().BoolVar(&opts.Suffix, "suffix", false, "add as <alias>-N when the alias is already taken")
+	addCmd.Flags().BoolVar(&allowNonGit, "allow-non-git", false, "add the path even if it is not inside a Git repository")
+	addCmd.Flags().BoolVar(&noResolveSymlinks, "no-resolve-symlinks", false, "store symlinked paths without resolving them")
+	addCmd.MarkFlagsMutuallyExclusive("force", "suffix")
+	return addCmd
 }
 
-func determineAliasAndPath(args []string, fileService FileService) (string, string, error) {
-	currentPath, err := getPath(args, fileService)
+func ensureGitRepository(path string, fileService FileService) error {
+	_, err := fileService.DetectGitRepository(path)
+	if errors.Is(err, libs.ErrNotGitRepository) {
+		return fmt.Errorf("refusing to add %s: %w (use --allow-non-git to add it anyway)", path, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check whether %s is a Git repository", path)
+	}
+	return nil
+}
+
+func determineAliasAndPath(args []string, fileService FileService, resolveSymlinks bool) (string, string, error) {
+	currentPath, err := getPath(args, fileService, resolveSymlinks)
 	if err != nil {
 		return "", "", err
 	}
@@ -60,28 +129,56 @@ func determineAliasAndPath(args []string, fileService FileService) (string, stri
 	return alias, currentPath, nil
 }
 
-func getPath(args []string, fileService FileService) (string, error) {
+func getPath(args []string, fileService FileService, resolveSymlinks bool) (string, error) {
 	switch len(args) {
-	case 0, 1:
+	case 0:
+		currentPath, err := fileService.GetCurrentPath()
+		if err != nil {
+			return "", errors.New("failed to get current path")
+		}
+		repoRoot, err := fileService.FindRepoRoot(currentPath)
+		if errors.Is(err, libs.ErrNotGitRepository) {
+			return normalizePath(currentPath, fileService, resolveSymlinks)
+		}
+		if err != nil {
+			return "", errors.New("failed to find repository root")
+		}
+		return normalizePath(repoRoot, fileService, resolveSymlinks)
+	case 1:
 		currentPath, err := fileService.GetCurrentPath()
 		if err != nil {
 			return "", errors.New("failed to get current path")
 		}
-		return currentPath, nil
+		return normalizePath(currentPath, fileService, resolveSymlinks)
 	case 2:
-		isPathExists, err := fileService.CheckIfPathExists(args[1])
+		path, err := normalizePath(args[1], fileService, resolveSymlinks)
+		if err != nil {
+			return "", err
+		}
+		isPathExists, err := fileService.CheckIfPathExists(path)
 		if err != nil || !isPathExists {
 			return "", errors.New("path does not exist")
 		}
-		return args[1], nil
+		return path, nil
 	}
 	return "", errors.New("invalid number of arguments")
 }
 
+func normalizePath(path string, normalizer PathNormalizer, resolveSymlinks bool) (string, error) {
+	normalized, err := normalizer.NormalizePath(path, resolveSymlinks)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", errors.New("path does not exist")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to normalize path %s", path)
+	}
+	return normalized, nil
+}
+
 func getAlias(args []string, fileService FileService, currentPath string) (string, error) {
 	switch len(args) {
 	case 0:
-		return fileService.GetParentFolderName(currentPath), nil
+		return fileService.GetFolderName(currentPath), nil
 	case 1, 2:
 		return args[0], nil
 	}
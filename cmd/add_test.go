@@ -1,9 +1,12 @@
 package cmd_test
 
 import (
+	"bytes"
 	"fmt"
 	"gs/cmd"
+	"gs/libs"
 	mocks "gs/mocks/cmd"
+	"io/fs"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,18 +22,27 @@ type MockCall[T any] struct {
 
 func TestAddCmd(t *testing.T) {
 	currentPath := "currentPath"
-	parentFolderName := "parentFolderName"
+	repoRoot := "repoRoot"
+	repoName := "repoName"
+	folderName := "folderName"
 	pathValue := "pathValue"
 	aliasValue := "aliasValue"
 
 	tests := []struct {
-		name                    string
-		args                    []string
-		expectedError           string
-		mockGetCurrentPath      MockCall[string]
-		mockGetParentFolderName MockCall[string]
-		mockCheckIfPathExists   MockCall[bool]
-		mockAdd                 MockCall[error]
+		name                  string
+		args                  []string
+		expectedError         string
+		expectedStderr        string
+		addOptions            libs.AddOptions
+		mockGetCurrentPath    MockCall[string]
+		mockGetFolderName     MockCall[string]
+		mockFindRepoRoot      MockCall[string]
+		mockCheckIfPathExists MockCall[bool]
+		mockDetectGitRepo     MockCall[libs.GitRepository]
+		mockNormalizePath     MockCall[string]
+		mockRepoIdentity      MockCall[libs.RepoIdentity]
+		resolveSymlinks       bool
+		mockAdd               MockCall[libs.AddResult]
 	}{
 		{
 			name: "successful no args",
@@ -39,14 +51,53 @@ func TestAddCmd(t *testing.T) {
 				Times:    1,
 				Response: currentPath,
 			},
-			mockGetParentFolderName: MockCall[string]{
+			mockFindRepoRoot: MockCall[string]{
 				args:     []string{currentPath},
 				Times:    1,
-				Response: parentFolderName,
+				Response: repoRoot,
 			},
-			mockAdd: MockCall[error]{
-				args:  []string{parentFolderName, currentPath},
+			mockGetFolderName: MockCall[string]{
+				args:     []string{repoRoot},
+				Times:    1,
+				Response: repoName,
+			},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{repoRoot},
+				Times: 1,
+			},
+			mockAdd: MockCall[libs.AddResult]{
+				args:     []string{repoName, repoRoot},
+				Times:    1,
+				Response: libs.AddResult{Alias: repoName},
+			},
+		},
+		{
+			name: "successful no args outside a repository with allow-non-git",
+			args: []string{"--allow-non-git"},
+			mockGetCurrentPath: MockCall[string]{
+				Times:    1,
+				Response: currentPath,
+			},
+			mockFindRepoRoot: MockCall[string]{
+				args:  []string{currentPath},
 				Times: 1,
+				Error: libs.ErrNotGitRepository,
+			},
+			mockGetFolderName: MockCall[string]{
+				args:     []string{currentPath},
+				Times:    1,
+				Response: folderName,
+			},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{currentPath},
+				Times: 1,
+				Error: fmt.Errorf("%w: no .git found", libs.ErrNotGitRepository),
+			},
+			addOptions: libs.AddOptions{NonGit: true},
+			mockAdd: MockCall[libs.AddResult]{
+				args:     []string{folderName, currentPath},
+				Times:    1,
+				Response: libs.AddResult{Alias: folderName},
 			},
 		},
 		{
@@ -58,6 +109,20 @@ func TestAddCmd(t *testing.T) {
 			},
 			expectedError: "failed to get current path",
 		},
+		{
+			name: "failed no args due to fail to find repository root",
+			args: []string{},
+			mockGetCurrentPath: MockCall[string]{
+				Times:    1,
+				Response: currentPath,
+			},
+			mockFindRepoRoot: MockCall[string]{
+				args:  []string{currentPath},
+				Times: 1,
+				Error: assert.AnError,
+			},
+			expectedError: "failed to find repository root",
+		},
 		{
 			name: "failed no args due to fail to add to database",
 			args: []string{},
@@ -65,17 +130,65 @@ func TestAddCmd(t *testing.T) {
 				Times:    1,
 				Response: currentPath,
 			},
-			mockGetParentFolderName: MockCall[string]{
+			mockFindRepoRoot: MockCall[string]{
+				args:     []string{currentPath},
+				Times:    1,
+				Response: repoRoot,
+			},
+			mockGetFolderName: MockCall[string]{
+				args:     []string{repoRoot},
+				Times:    1,
+				Response: repoName,
+			},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{repoRoot},
+				Times: 1,
+			},
+			mockAdd: MockCall[libs.AddResult]{
+				args:  []string{repoName, repoRoot},
+				Times: 1,
+				Error: assert.AnError,
+			},
+			expectedError: fmt.Sprintf("failed to add %s with alias %s", repoRoot, repoName),
+		},
+		{
+			name: "failed no args outside a repository",
+			args: []string{},
+			mockGetCurrentPath: MockCall[string]{
+				Times:    1,
+				Response: currentPath,
+			},
+			mockFindRepoRoot: MockCall[string]{
+				args:  []string{currentPath},
+				Times: 1,
+				Error: libs.ErrNotGitRepository,
+			},
+			mockGetFolderName: MockCall[string]{
 				args:     []string{currentPath},
 				Times:    1,
-				Response: parentFolderName,
+				Response: folderName,
 			},
-			mockAdd: MockCall[error]{
-				args:  []string{parentFolderName, currentPath},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{currentPath},
+				Times: 1,
+				Error: fmt.Errorf("%w: no .git found in %s or any parent directory", libs.ErrNotGitRepository, currentPath),
+			},
+			expectedError: fmt.Sprintf("refusing to add %s: not inside a Git repository: no .git found in %s or any parent directory (use --allow-non-git to add it anyway)", currentPath, currentPath),
+		},
+		{
+			name: "failed with alias and path arg due to fail to detect repository",
+			args: []string{aliasValue, pathValue},
+			mockCheckIfPathExists: MockCall[bool]{
+				args:     []string{pathValue},
+				Times:    1,
+				Response: true,
+			},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{pathValue},
 				Times: 1,
 				Error: assert.AnError,
 			},
-			expectedError: fmt.Sprintf("failed to add %s with alias %s", currentPath, parentFolderName),
+			expectedError: fmt.Sprintf("failed to check whether %s is a Git repository", pathValue),
 		},
 		{
 			name: "successful with alias arg",
@@ -84,10 +197,15 @@ func TestAddCmd(t *testing.T) {
 				Times:    1,
 				Response: currentPath,
 			},
-			mockAdd: MockCall[error]{
-				args:  []string{aliasValue, currentPath},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{currentPath},
 				Times: 1,
 			},
+			mockAdd: MockCall[libs.AddResult]{
+				args:     []string{aliasValue, currentPath},
+				Times:    1,
+				Response: libs.AddResult{Alias: aliasValue},
+			},
 		},
 		{
 			name: "failed with alias arg due to fail to get current path",
@@ -105,7 +223,11 @@ func TestAddCmd(t *testing.T) {
 				Times:    1,
 				Response: currentPath,
 			},
-			mockAdd: MockCall[error]{
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{currentPath},
+				Times: 1,
+			},
+			mockAdd: MockCall[libs.AddResult]{
 				args:  []string{aliasValue, currentPath},
 				Times: 1,
 				Error: assert.AnError,
@@ -120,10 +242,15 @@ func TestAddCmd(t *testing.T) {
 				Times:    1,
 				Response: true,
 			},
-			mockAdd: MockCall[error]{
-				args:  []string{aliasValue, pathValue},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{pathValue},
 				Times: 1,
 			},
+			mockAdd: MockCall[libs.AddResult]{
+				args:     []string{aliasValue, pathValue},
+				Times:    1,
+				Response: libs.AddResult{Alias: aliasValue},
+			},
 		},
 		{
 			name: "failed with alias and path arg due to fail to check if path exists",
@@ -143,13 +270,216 @@ func TestAddCmd(t *testing.T) {
 				Times:    1,
 				Response: true,
 			},
-			mockAdd: MockCall[error]{
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{pathValue},
+				Times: 1,
+			},
+			mockAdd: MockCall[libs.AddResult]{
 				args:  []string{aliasValue, pathValue},
 				Times: 1,
 				Error: assert.AnError,
 			},
 			expectedError: fmt.Sprintf("failed to add %s with alias %s", pathValue, aliasValue),
 		},
+		{
+			name: "failed with alias and path arg due to existing alias",
+			args: []string{aliasValue, pathValue},
+			mockCheckIfPathExists: MockCall[bool]{
+				args:     []string{pathValue},
+				Times:    1,
+				Response: true,
+			},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{pathValue},
+				Times: 1,
+			},
+			mockAdd: MockCall[libs.AddResult]{
+				args:  []string{aliasValue, pathValue},
+				Times: 1,
+				Error: fmt.Errorf("%w: %s points at %s", libs.ErrAliasExists, aliasValue, currentPath),
+			},
+			expectedError: fmt.Sprintf("alias already exists: %s points at %s (use --force to overwrite or --suffix to add it as %s-N)", aliasValue, currentPath, aliasValue),
+		},
+		{
+			name: "successful with alias and path arg and force",
+			args: []string{aliasValue, pathValue, "--force"},
+			mockCheckIfPathExists: MockCall[bool]{
+				args:     []string{pathValue},
+				Times:    1,
+				Response: true,
+			},
+			addOptions: libs.AddOptions{Force: true},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{pathValue},
+				Times: 1,
+			},
+			mockAdd: MockCall[libs.AddResult]{
+				args:     []string{aliasValue, pathValue},
+				Times:    1,
+				Response: libs.AddResult{Alias: aliasValue},
+			},
+		},
+		{
+			name: "successful with alias and path arg and suffix",
+			args: []string{aliasValue, pathValue, "--suffix"},
+			mockCheckIfPathExists: MockCall[bool]{
+				args:     []string{pathValue},
+				Times:    1,
+				Response: true,
+			},
+			addOptions: libs.AddOptions{Suffix: true},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{pathValue},
+				Times: 1,
+			},
+			mockAdd: MockCall[libs.AddResult]{
+				args:     []string{aliasValue, pathValue},
+				Times:    1,
+				Response: libs.AddResult{Alias: aliasValue + "-2"},
+			},
+			expectedStderr: fmt.Sprintf("added %s as %s-2\n", pathValue, aliasValue),
+		},
+		{
+			name: "successful with alias and path arg already registered under another alias",
+			args: []string{aliasValue, pathValue},
+			mockCheckIfPathExists: MockCall[bool]{
+				args:     []string{pathValue},
+				Times:    1,
+				Response: true,
+			},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{pathValue},
+				Times: 1,
+			},
+			mockAdd: MockCall[libs.AddResult]{
+				args:     []string{aliasValue, pathValue},
+				Times:    1,
+				Response: libs.AddResult{Alias: aliasValue, ExistingAliases: []string{"other", "another"}},
+			},
+			expectedStderr: fmt.Sprintf("%s is also registered as other, another\n", pathValue),
+		},
+		{
+			name: "successful with alias and path arg normalizes the path",
+			args: []string{aliasValue, "~/pathValue"},
+			mockNormalizePath: MockCall[string]{
+				args:     []string{"~/pathValue"},
+				Times:    1,
+				Response: pathValue,
+			},
+			resolveSymlinks: true,
+			mockCheckIfPathExists: MockCall[bool]{
+				args:     []string{pathValue},
+				Times:    1,
+				Response: true,
+			},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{pathValue},
+				Times: 1,
+			},
+			mockAdd: MockCall[libs.AddResult]{
+				args:     []string{aliasValue, pathValue},
+				Times:    1,
+				Response: libs.AddResult{Alias: aliasValue},
+			},
+		},
+		{
+			name: "successful with alias and path arg keeping symlinks",
+			args: []string{aliasValue, "linkValue", "--no-resolve-symlinks"},
+			mockNormalizePath: MockCall[string]{
+				args:     []string{"linkValue"},
+				Times:    1,
+				Response: pathValue,
+			},
+			resolveSymlinks: false,
+			mockCheckIfPathExists: MockCall[bool]{
+				args:     []string{pathValue},
+				Times:    1,
+				Response: true,
+			},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{pathValue},
+				Times: 1,
+			},
+			mockAdd: MockCall[libs.AddResult]{
+				args:     []string{aliasValue, pathValue},
+				Times:    1,
+				Response: libs.AddResult{Alias: aliasValue},
+			},
+		},
+		{
+			name: "failed with alias and path arg due to missing symlink target",
+			args: []string{aliasValue, "linkValue"},
+			mockNormalizePath: MockCall[string]{
+				args:  []string{"linkValue"},
+				Times: 1,
+				Error: fs.ErrNotExist,
+			},
+			resolveSymlinks: true,
+			expectedError:   "path does not exist",
+		},
+		{
+			name: "failed with alias and path arg due to fail to normalize path",
+			args: []string{aliasValue, "linkValue"},
+			mockNormalizePath: MockCall[string]{
+				args:  []string{"linkValue"},
+				Times: 1,
+				Error: assert.AnError,
+			},
+			resolveSymlinks: true,
+			expectedError:   "failed to normalize path linkValue",
+		},
+		{
+			name: "successful with alias and path arg storing the repository identity",
+			args: []string{aliasValue, pathValue},
+			mockCheckIfPathExists: MockCall[bool]{
+				args:     []string{pathValue},
+				Times:    1,
+				Response: true,
+			},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{pathValue},
+				Times: 1,
+			},
+			mockRepoIdentity: MockCall[libs.RepoIdentity]{
+				args:     []string{pathValue},
+				Times:    1,
+				Response: libs.RepoIdentity{Remote: "git@example.com:org/repo.git", RootCommit: "abc123"},
+			},
+			addOptions: libs.AddOptions{Identity: libs.RepoIdentity{Remote: "git@example.com:org/repo.git", RootCommit: "abc123"}},
+			mockAdd: MockCall[libs.AddResult]{
+				args:     []string{aliasValue, pathValue},
+				Times:    1,
+				Response: libs.AddResult{Alias: aliasValue},
+			},
+		},
+		{
+			name: "successful with alias and path arg when the identity cannot be read",
+			args: []string{aliasValue, pathValue},
+			mockCheckIfPathExists: MockCall[bool]{
+				args:     []string{pathValue},
+				Times:    1,
+				Response: true,
+			},
+			mockDetectGitRepo: MockCall[libs.GitRepository]{
+				args:  []string{pathValue},
+				Times: 1,
+			},
+			mockRepoIdentity: MockCall[libs.RepoIdentity]{
+				args:  []string{pathValue},
+				Times: 1,
+				Error: assert.AnError,
+			},
+			mockAdd: MockCall[libs.AddResult]{
+				args:     []string{aliasValue, pathValue},
+				Times:    1,
+				Response: libs.AddResult{Alias: aliasValue},
+			},
+		},
+		{
+			name:          "failed with force and suffix together",
+			args:          []string{aliasValue, pathValue, "--force", "--suffix"},
+			expectedError: "if any flags in the group [force suffix] are set none of the others can be; [force suffix] were all set",
+		},
 	}
 
 	for _, tt := range tests {
@@ -166,27 +496,51 @@ func TestAddCmd(t *testing.T) {
 				mockFileService.EXPECT().GetCurrentPath().Return(tt.mockGetCurrentPath.Response, tt.mockGetCurrentPath.Error).Times(tt.mockGetCurrentPath.Times)
 			}
 
-			if tt.mockGetParentFolderName.Times > 0 && len(tt.mockGetParentFolderName.args) > 0 {
-				mockFileService.EXPECT().GetParentFolderName(tt.mockGetParentFolderName.args[0]).Return(tt.mockGetParentFolderName.Response).Times(tt.mockGetParentFolderName.Times)
+			if tt.mockGetFolderName.Times > 0 && len(tt.mockGetFolderName.args) > 0 {
+				mockFileService.EXPECT().GetFolderName(tt.mockGetFolderName.args[0]).Return(tt.mockGetFolderName.Response).Times(tt.mockGetFolderName.Times)
+			}
+
+			if tt.mockFindRepoRoot.Times > 0 && len(tt.mockFindRepoRoot.args) > 0 {
+				mockFileService.EXPECT().FindRepoRoot(tt.mockFindRepoRoot.args[0]).Return(tt.mockFindRepoRoot.Response, tt.mockFindRepoRoot.Error).Times(tt.mockFindRepoRoot.Times)
+			}
+
+			if tt.mockNormalizePath.Times > 0 && len(tt.mockNormalizePath.args) > 0 {
+				mockFileService.EXPECT().NormalizePath(tt.mockNormalizePath.args[0], tt.resolveSymlinks).Return(tt.mockNormalizePath.Response, tt.mockNormalizePath.Error).Times(tt.mockNormalizePath.Times)
 			}
+			// Paths that a case does not normalise explicitly are already canonical
+			mockFileService.EXPECT().NormalizePath(gomock.Any(), true).DoAndReturn(func(path string, resolveSymlinks bool) (string, error) {
+				return path, nil
+			}).AnyTimes()
 
 			if tt.mockCheckIfPathExists.Times > 0 && len(tt.mockCheckIfPathExists.args) > 0 {
 				mockFileService.EXPECT().CheckIfPathExists(tt.mockCheckIfPathExists.args[0]).Return(tt.mockCheckIfPathExists.Response, tt.mockCheckIfPathExists.Error).Times(tt.mockCheckIfPathExists.Times)
 			}
 
+			if tt.mockDetectGitRepo.Times > 0 && len(tt.mockDetectGitRepo.args) > 0 {
+				mockFileService.EXPECT().DetectGitRepository(tt.mockDetectGitRepo.args[0]).Return(tt.mockDetectGitRepo.Response, tt.mockDetectGitRepo.Error).Times(tt.mockDetectGitRepo.Times)
+			}
+
+			if tt.mockRepoIdentity.Times > 0 && len(tt.mockRepoIdentity.args) > 0 {
+				mockFileService.EXPECT().RepoIdentity(tt.mockRepoIdentity.args[0]).Return(tt.mockRepoIdentity.Response, tt.mockRepoIdentity.Error).Times(tt.mockRepoIdentity.Times)
+			}
+			// Cases that do not care about the identity add repositories without one
+			mockFileService.EXPECT().RepoIdentity(gomock.Any()).Return(libs.RepoIdentity{}, nil).AnyTimes()
+
 			if tt.mockAdd.Times > 0 && len(tt.mockAdd.args) >= 2 {
-				mockDBService.EXPECT().Add(tt.mockAdd.args[0], tt.mockAdd.args[1]).Return(tt.mockAdd.Error).Times(tt.mockAdd.Times)
+				mockDBService.EXPECT().Add(tt.mockAdd.args[0], tt.mockAdd.args[1], tt.addOptions).Return(tt.mockAdd.Response, tt.mockAdd.Error).Times(tt.mockAdd.Times)
 			}
 
+			var stderr bytes.Buffer
+			cmd.SetErr(&stderr)
 			cmd.SetArgs(tt.args)
 			err := cmd.Execute()
 
 			if tt.expectedError == "" {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStderr, stderr.String())
 			} else {
 				assert.EqualError(t, err, tt.expectedError)
 			}
 		})
 	}
 }
-
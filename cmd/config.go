@@ -0,0 +1,236 @@
+//go:generate mockgen -destination=../mocks/cmd/config.go -package=mocks -source=config.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"gs/libs"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type ConfigLocator interface {
+	File() string
+	SearchPaths() []string
+}
+
+type ConfigReader interface {
+	Keys() []string
+	Get(key string) (string, error)
+	UnknownKeys() ([]string, error)
+}
+
+type ConfigWriter interface {
+	WritePath() string
+	Set(key, value string) error
+	Edit(stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+type ConfigManager interface {
+	ConfigLocator
+	ConfigReader
+	ConfigWriter
+}
+
+// ConfigFlagName returns the global flag that overrides a config key, e.g.
+// --pick-finder for pick.finder unless the key names another flag.
+func ConfigFlagName(key libs.ConfigKey) string {
+	if key.Flag != "" {
+		return key.Flag
+	}
+	return strings.NewReplacer(".", "-", "_", "-").Replace(key.Name)
+}
+
+// AddConfigFlags defines a flag on flags for every key in libs.ConfigKeys.
+// main parses them before the commands run, since the database depends on
+// the config, and the root command defines them again so that cobra accepts
+// them and lists them in the help.
+func AddConfigFlags(flags *pflag.FlagSet) {
+	for _, key := range libs.ConfigKeys {
+		if key.List {
+			flags.StringSlice(ConfigFlagName(key), nil, "override "+key.Name+": "+key.Description)
+		} else {
+			flags.String(ConfigFlagName(key), "", "override "+key.Name+": "+key.Description)
+		}
+	}
+}
+
+func NewConfigCmd(manager ConfigManager) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect and change the gitswitch configuration",
+		Long: `Inspect and change the gitswitch configuration.
+
+gs reads the first config file it finds, in this order:
+
+  $GS_CONFIG
+  $XDG_CONFIG_HOME/gs/config.yaml (~/.config/gs/config.yaml when unset)
+  ~/.gs/config.yaml
+  config.yaml in the current directory
+
+Without a config file gs runs on built-in defaults, and 'gs config set' and
+'gs config edit' create the first file of that list. Any key can be
+overridden with a GS_ environment variable or a global flag, for example
+GS_PICK_FINDER=fzf or --pick-finder fzf for pick.finder, and --db for
+db_path. Flags take precedence over environment variables, which take
+precedence over the file. List values such as scan.ignore are written
+comma-separated.`,
+	}
+
+	configCmd.AddCommand(newConfigPathCmd(manager))
+	configCmd.AddCommand(newConfigGetCmd(manager))
+	configCmd.AddCommand(newConfigSetCmd(manager))
+	configCmd.AddCommand(newConfigListCmd(manager))
+	configCmd.AddCommand(newConfigEditCmd(manager))
+	return configCmd
+}
+
+func newConfigPathCmd(locator ConfigLocator) *cobra.Command {
+	return &cobra.Command{
+		Use:   "path",
+		Short: "Print the path of the config file in use",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			file := locator.File()
+			if file == "" {
+				return fmt.Errorf("no config file found, using built-in defaults (searched %s)",
+					strings.Join(locator.SearchPaths(), ", "))
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), file)
+			return nil
+		},
+	}
+}
+
+func newConfigGetCmd(reader ConfigReader) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the value in effect for a key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			value, err := reader.Get(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), value)
+			return nil
+		},
+	}
+}
+
+func newConfigSetCmd(writer ConfigWriter) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Write a key to the config file",
+		Long: `Write a key to the config file, keeping its comments. The value is checked
+first, so an invalid value leaves the file unchanged. Write list values
+comma-separated, for example 'gs config set scan.ignore ".*,node_modules"'.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if err := writer.Set(args[0], args[1]); err != nil {
+				if errors.Is(err, libs.ErrUnknownConfigKey) {
+					return err
+				}
+				return fmt.Errorf("failed to set %s in %s: %w", args[0], writer.WritePath(), err)
+			}
+			return nil
+		},
+	}
+}
+
+func newConfigListCmd(reader ConfigReader) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "Print every key with the value in effect",
+		Long: `Print every key with the value in effect as key=value lines, and warn about
+keys in the config file that gs does not know.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			for _, key := range reader.Keys() {
+				value, err := reader.Get(key)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s=%s\n", key, value)
+			}
+			return warnUnknownKeys(cmd, reader)
+		},
+	}
+}
+
+func newConfigEditCmd(manager ConfigManager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Open the config file in $VISUAL or $EDITOR",
+		Long: `Open a copy of the config file in $VISUAL or $EDITOR, or vi when neither is
+set. The copy is checked once the editor exits and only replaces the file,
+creating it if there is none, when it is valid. Otherwise gs offers to open
+the editor again.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if err := manager.Edit(cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+			return warnUnknownKeys(cmd, manager)
+		},
+	}
+}
+
+// NewConfigRepairCommand returns the root command main runs when the config
+// cannot be loaded. The config commands and 'gs init' still work, so that
+// the file can be fixed with gs itself; anything else reports loadErr.
+func NewConfigRepairCommand(manager ConfigManager, loadErr error) *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:                "gs [alias]",
+		Short:              "gitswitch: quick and easy Git project switching",
+		Args:               cobra.ArbitraryArgs,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+		SilenceUsage:       true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fmt.Errorf("%w\nfix it with 'gs config edit' or 'gs config set'", loadErr)
+		},
+	}
+
+	AddConfigFlags(rootCmd.PersistentFlags())
+	rootCmd.AddCommand(NewInitCmd())
+	rootCmd.AddCommand(NewConfigCmd(manager))
+	return rootCmd
+}
+
+// warnUnknownKeys prints a warning on stderr for every key in the config
+// file that gs does not know, with the key it was probably meant to be.
+func warnUnknownKeys(cmd *cobra.Command, reader ConfigReader) error {
+	unknown, err := reader.UnknownKeys()
+	if err != nil {
+		return err
+	}
+	for _, key := range unknown {
+		fmt.Fprintln(cmd.ErrOrStderr(), UnknownConfigKeyWarning(key))
+	}
+	return nil
+}
+
+// ReportsUnknownConfigKeys reports whether cmd warns about unknown keys in the
+// config file itself, after it has run. 'gs config edit' has to, since the
+// keys can change while it runs, and 'gs config list' lists them on purpose.
+func ReportsUnknownConfigKeys(cmd *cobra.Command) bool {
+	return topLevelName(cmd) == "config" && contains([]string{"list", "edit"}, cmd.Name())
+}
+
+// UnknownConfigKeyWarning describes a key in the config file that gs does
+// not know.
+func UnknownConfigKeyWarning(key string) string {
+	if suggestion := libs.SuggestConfigKey(key); suggestion != "" {
+		return fmt.Sprintf("warning: unknown config key %q, did you mean %q?", key, suggestion)
+	}
+	return fmt.Sprintf("warning: unknown config key %q", key)
+}
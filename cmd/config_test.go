@@ -0,0 +1,298 @@
+package cmd_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestConfigCmd(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		setupMock      func(*mocks.MockConfigManager)
+		expectedOutput string
+		expectedStderr string
+		expectedError  string
+	}{
+		{
+			name: "successful path of loaded file",
+			args: []string{"path"},
+			setupMock: func(m *mocks.MockConfigManager) {
+				m.EXPECT().File().Return("/home/me/.config/gs/config.yaml")
+			},
+			expectedOutput: "/home/me/.config/gs/config.yaml\n",
+		},
+		{
+			name: "failed path without config file",
+			args: []string{"path"},
+			setupMock: func(m *mocks.MockConfigManager) {
+				m.EXPECT().File().Return("")
+				m.EXPECT().SearchPaths().Return([]string{"/home/me/.config/gs/config.yaml", "/home/me/.gs/config.yaml"})
+			},
+			expectedError: "no config file found, using built-in defaults (searched /home/me/.config/gs/config.yaml, /home/me/.gs/config.yaml)",
+		},
+		{
+			name:          "failed path due to extra argument",
+			args:          []string{"path", "extra"},
+			expectedError: `unknown command "extra" for "config path"`,
+		},
+		{
+			name: "successful get",
+			args: []string{"get", "pick.finder"},
+			setupMock: func(m *mocks.MockConfigManager) {
+				m.EXPECT().Get("pick.finder").Return("fzf", nil)
+			},
+			expectedOutput: "fzf\n",
+		},
+		{
+			name: "failed get of misspelled key",
+			args: []string{"get", "pick.findr"},
+			setupMock: func(m *mocks.MockConfigManager) {
+				m.EXPECT().Get("pick.findr").Return("", fmt.Errorf(`%w "pick.findr", did you mean "pick.finder"?`, libs.ErrUnknownConfigKey))
+			},
+			expectedError: `unknown config key "pick.findr", did you mean "pick.finder"?`,
+		},
+		{
+			name: "successful set",
+			args: []string{"set", "scan.ignore", ".*,vendor"},
+			setupMock: func(m *mocks.MockConfigManager) {
+				m.EXPECT().Set("scan.ignore", ".*,vendor").Return(nil)
+			},
+		},
+		{
+			name: "failed set of unknown key",
+			args: []string{"set", "colour", "red"},
+			setupMock: func(m *mocks.MockConfigManager) {
+				m.EXPECT().Set("colour", "red").Return(fmt.Errorf(`%w "colour"`, libs.ErrUnknownConfigKey))
+			},
+			expectedError: `unknown config key "colour"`,
+		},
+		{
+			name: "failed set of invalid value",
+			args: []string{"set", "kv_bucket_name", ""},
+			setupMock: func(m *mocks.MockConfigManager) {
+				m.EXPECT().Set("kv_bucket_name", "").Return(assert.AnError)
+				m.EXPECT().WritePath().Return("/home/me/.gs/config.yaml")
+			},
+			expectedError: "failed to set kv_bucket_name in /home/me/.gs/config.yaml: " + assert.AnError.Error(),
+		},
+		{
+			name:          "failed set without value",
+			args:          []string{"set", "pick.finder"},
+			expectedError: "accepts 2 arg(s), received 1",
+		},
+		{
+			name: "successful list with unknown keys",
+			args: []string{"list"},
+			setupMock: func(m *mocks.MockConfigManager) {
+				m.EXPECT().Keys().Return([]string{"app_name", "scan.ignore"})
+				m.EXPECT().Get("app_name").Return("gs", nil)
+				m.EXPECT().Get("scan.ignore").Return(".*,node_modules", nil)
+				m.EXPECT().UnknownKeys().Return([]string{"kv_bucket", "theme"}, nil)
+			},
+			expectedOutput: "app_name=gs\nscan.ignore=.*,node_modules\n",
+			expectedStderr: "warning: unknown config key \"kv_bucket\", did you mean \"kv_bucket_name\"?\n" +
+				"warning: unknown config key \"theme\"\n",
+		},
+		{
+			name: "failed list due to unreadable file",
+			args: []string{"ls"},
+			setupMock: func(m *mocks.MockConfigManager) {
+				m.EXPECT().Keys().Return(nil)
+				m.EXPECT().UnknownKeys().Return(nil, assert.AnError)
+			},
+			expectedError: assert.AnError.Error(),
+		},
+		{
+			name: "successful edit",
+			args: []string{"edit"},
+			setupMock: func(m *mocks.MockConfigManager) {
+				m.EXPECT().Edit(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().UnknownKeys().Return([]string{"pick.finderr"}, nil)
+			},
+			expectedStderr: "warning: unknown config key \"pick.finderr\", did you mean \"pick.finder\"?\n",
+		},
+		{
+			name: "failed edit due to invalid file",
+			args: []string{"edit"},
+			setupMock: func(m *mocks.MockConfigManager) {
+				m.EXPECT().Edit(gomock.Any(), gomock.Any(), gomock.Any()).Return(assert.AnError)
+			},
+			expectedError: assert.AnError.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockManager := mocks.NewMockConfigManager(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(mockManager)
+			}
+			configCmd := cmd.NewConfigCmd(mockManager)
+
+			var out, errOut bytes.Buffer
+			configCmd.SetOut(&out)
+			configCmd.SetErr(&errOut)
+			configCmd.SetArgs(tt.args)
+			err := configCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+				assert.Equal(t, tt.expectedStderr, errOut.String())
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestReportsUnknownConfigKeys(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{args: []string{"config", "list"}, want: true},
+		{args: []string{"config", "ls"}, want: true},
+		{args: []string{"--profile", "work", "config", "edit"}, want: true},
+		{args: []string{"config", "path"}, want: false},
+		{args: []string{"list"}, want: false},
+		{args: []string{"myproj"}, want: false},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	rootCmd := cmd.NewRootCommand(mocks.NewMockRootDBService(ctrl), mocks.NewMockRootFileService(ctrl), mocks.NewMockMigrator(ctrl), mocks.NewMockProjectPicker(ctrl), mocks.NewMockProjectRunner(ctrl), mocks.NewMockStatusReader(ctrl), mocks.NewMockConfigManager(ctrl))
+	for _, tt := range tests {
+		target, _, err := rootCmd.Find(tt.args)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, cmd.ReportsUnknownConfigKeys(target), strings.Join(tt.args, " "))
+	}
+}
+
+func TestAddConfigFlags(t *testing.T) {
+	flags := pflag.NewFlagSet("gs", pflag.ContinueOnError)
+	cmd.AddConfigFlags(flags)
+
+	assert.NoError(t, flags.Parse([]string{"--kv-bucket-name", "work", "--db", "~/gs.db", "--scan-ignore", ".*,dist", "--pick-preview-fzf", "cat {2}"}))
+	for _, key := range libs.ConfigKeys {
+		assert.NotNil(t, flags.Lookup(cmd.ConfigFlagName(key)), key.Name)
+	}
+	bucket, _ := flags.GetString("kv-bucket-name")
+	assert.Equal(t, "work", bucket)
+	dbPath, _ := flags.GetString("db")
+	assert.Equal(t, "~/gs.db", dbPath)
+	ignore, _ := flags.GetStringSlice("scan-ignore")
+	assert.Equal(t, []string{".*", "dist"}, ignore)
+	preview, _ := flags.GetString("pick-preview-fzf")
+	assert.Equal(t, "cat {2}", preview)
+}
+
+func TestConfigRepairCommand(t *testing.T) {
+	loadErr := errors.New("/home/me/.config/gs/config.yaml: kv_bucket_name must not be empty")
+
+	tests := []struct {
+		name           string
+		args           []string
+		setupMock      func(*mocks.MockConfigManager)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name: "config commands run",
+			args: []string{"--profile", "work", "config", "path"},
+			setupMock: func(m *mocks.MockConfigManager) {
+				m.EXPECT().File().Return("/home/me/.config/gs/config.yaml")
+			},
+			expectedOutput: "/home/me/.config/gs/config.yaml\n",
+		},
+		{
+			name: "config edit runs",
+			args: []string{"config", "edit"},
+			setupMock: func(m *mocks.MockConfigManager) {
+				m.EXPECT().Edit(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().UnknownKeys().Return(nil, nil)
+			},
+		},
+		{
+			name:          "switching reports the config error",
+			args:          []string{"api"},
+			expectedError: loadErr.Error() + "\nfix it with 'gs config edit' or 'gs config set'",
+		},
+		{
+			name:          "other commands report the config error",
+			args:          []string{"list", "--tag", "backend"},
+			expectedError: loadErr.Error() + "\nfix it with 'gs config edit' or 'gs config set'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockManager := mocks.NewMockConfigManager(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(mockManager)
+			}
+			rootCmd := cmd.NewConfigRepairCommand(mockManager, loadErr)
+
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args)
+			err := rootCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+
+	t.Run("init runs", func(t *testing.T) {
+		rootCmd := cmd.NewConfigRepairCommand(mocks.NewMockConfigManager(gomock.NewController(t)), loadErr)
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"init", "bash"})
+		assert.NoError(t, rootCmd.Execute())
+		assert.Contains(t, out.String(), "gs()")
+	})
+}
+
+func TestRootCmdConfigWithoutDatabase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No migration and no profile check: the database is released before
+	// the editor starts
+	mockDBService := mocks.NewMockRootDBService(ctrl)
+	mockManager := mocks.NewMockConfigManager(ctrl)
+	gomock.InOrder(
+		mockDBService.EXPECT().Release().Return(nil),
+		mockManager.EXPECT().Edit(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+	)
+	mockManager.EXPECT().UnknownKeys().Return(nil, nil)
+	rootCmd := cmd.NewRootCommand(mockDBService, mocks.NewMockRootFileService(ctrl), mocks.NewMockMigrator(ctrl), mocks.NewMockProjectPicker(ctrl), mocks.NewMockProjectRunner(ctrl), mocks.NewMockStatusReader(ctrl), mockManager)
+
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"config", "edit"})
+	assert.NoError(t, rootCmd.Execute())
+}
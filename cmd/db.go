@@ -0,0 +1,115 @@
+//go:generate mockgen -destination=../mocks/cmd/db.go -package=mocks -source=db.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"gs/libs"
+
+	"github.com/spf13/cobra"
+)
+
+type Migrator interface {
+	Version() (int, error)
+	Pending() ([]libs.Migration, error)
+	Migrate() (libs.MigrationResult, error)
+}
+
+func NewDBCmd(migrator Migrator) *cobra.Command {
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Inspect and upgrade the gitswitch database",
+		Long: `Inspect and upgrade the gitswitch database.
+
+gs upgrades the database automatically before running any other command,
+after writing a backup copy next to the database file. The db commands never
+trigger that automatic upgrade, so pending migrations can be reviewed first.`,
+	}
+
+	dbCmd.AddCommand(newDBVersionCmd(migrator))
+	dbCmd.AddCommand(newDBMigrateCmd(migrator))
+	return dbCmd
+}
+
+func newDBVersionCmd(migrator Migrator) *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print the schema version of the database",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			version, err := migrator.Version()
+			if err != nil {
+				return errors.New("failed to read schema version")
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "schema version %d (latest %d)\n", version, libs.SchemaVersion)
+			return nil
+		},
+	}
+}
+
+func newDBMigrateCmd(migrator Migrator) *cobra.Command {
+	var dryRun bool
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade the database to the latest schema version",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+
+			if dryRun {
+				pending, err := migrator.Pending()
+				if err != nil {
+					return fmt.Errorf("failed to list pending migrations: %w", err)
+				}
+				if len(pending) == 0 {
+					fmt.Fprintf(out, "database is up to date (schema version %d)\n", libs.SchemaVersion)
+					return nil
+				}
+				for _, migration := range pending {
+					fmt.Fprintf(out, "would apply %d: %s\n", migration.Version, migration.Description)
+				}
+				return nil
+			}
+
+			result, err := migrator.Migrate()
+			if err != nil {
+				return fmt.Errorf("failed to migrate database: %w", err)
+			}
+			if len(result.Applied) == 0 {
+				fmt.Fprintf(out, "database is up to date (schema version %d)\n", result.To)
+				return nil
+			}
+			fmt.Fprintf(out, "backed up database to %s\n", result.BackupPath)
+			for _, migration := range result.Applied {
+				fmt.Fprintf(out, "applied %d: %s\n", migration.Version, migration.Description)
+			}
+			return nil
+		},
+	}
+
+	migrateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "list pending migrations without applying them")
+	return migrateCmd
+}
+
+// autoMigrate brings the database up to date before a command touches it.
+// Notices go to stderr so that shell wrappers only ever see a path on stdout.
+func autoMigrate(cmd *cobra.Command, migrator Migrator) error {
+	result, err := migrator.Migrate()
+	if err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	if len(result.Applied) > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "upgraded database from schema version %d to %d (backup at %s)\n", result.From, result.To, result.BackupPath)
+	}
+	return nil
+}
+
+func isDBCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Name() == "db" && c.Parent() == c.Root() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package cmd_test
+
+import (
+	"bytes"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDBCmd(t *testing.T) {
+	pending := []libs.Migration{
+		{Version: 1, Description: "canonicalise stored paths"},
+		{Version: 2, Description: "store values as versioned records"},
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		setupMock      func(*mocks.MockMigrator)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name: "successful version",
+			args: []string{"version"},
+			setupMock: func(m *mocks.MockMigrator) {
+				m.EXPECT().Version().Return(1, nil)
+			},
+			expectedOutput: "schema version 1 (latest 2)\n",
+		},
+		{
+			name: "failed version due to database error",
+			args: []string{"version"},
+			setupMock: func(m *mocks.MockMigrator) {
+				m.EXPECT().Version().Return(0, assert.AnError)
+			},
+			expectedError: "failed to read schema version",
+		},
+		{
+			name: "successful migrate dry run with pending migrations",
+			args: []string{"migrate", "--dry-run"},
+			setupMock: func(m *mocks.MockMigrator) {
+				m.EXPECT().Pending().Return(pending, nil)
+			},
+			expectedOutput: "would apply 1: canonicalise stored paths\nwould apply 2: store values as versioned records\n",
+		},
+		{
+			name: "successful migrate dry run when up to date",
+			args: []string{"migrate", "--dry-run"},
+			setupMock: func(m *mocks.MockMigrator) {
+				m.EXPECT().Pending().Return(nil, nil)
+			},
+			expectedOutput: "database is up to date (schema version 2)\n",
+		},
+		{
+			name: "successful migrate",
+			args: []string{"migrate"},
+			setupMock: func(m *mocks.MockMigrator) {
+				m.EXPECT().Migrate().Return(libs.MigrationResult{From: 0, To: 2, Applied: pending, BackupPath: "/db.bak"}, nil)
+			},
+			expectedOutput: "backed up database to /db.bak\napplied 1: canonicalise stored paths\napplied 2: store values as versioned records\n",
+		},
+		{
+			name: "successful migrate when up to date",
+			args: []string{"migrate"},
+			setupMock: func(m *mocks.MockMigrator) {
+				m.EXPECT().Migrate().Return(libs.MigrationResult{From: 2, To: 2}, nil)
+			},
+			expectedOutput: "database is up to date (schema version 2)\n",
+		},
+		{
+			name: "failed migrate",
+			args: []string{"migrate"},
+			setupMock: func(m *mocks.MockMigrator) {
+				m.EXPECT().Migrate().Return(libs.MigrationResult{}, assert.AnError)
+			},
+			expectedError: "failed to migrate database: " + assert.AnError.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMigrator := mocks.NewMockMigrator(ctrl)
+			tt.setupMock(mockMigrator)
+			dbCmd := cmd.NewDBCmd(mockMigrator)
+
+			var out bytes.Buffer
+			dbCmd.SetOut(&out)
+			dbCmd.SetErr(&bytes.Buffer{})
+			dbCmd.SetArgs(tt.args)
+			err := dbCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
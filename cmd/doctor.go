@@ -0,0 +1,151 @@
+//go:generate mockgen -destination=../mocks/cmd/doctor.go -package=mocks -source=doctor.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"gs/libs"
+	"io/fs"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// ExitCodeUnhealthy is returned by doctor and prune --dry-run when some
+// entries need attention, so that they can serve as a health check.
+const ExitCodeUnhealthy = 4
+
+type HealthChecker interface {
+	CheckIfPathExists(path string) (bool, error)
+	DetectGitRepository(path string) (libs.GitRepository, error)
+}
+
+// health classifies a stored entry.
+type health string
+
+// needsAttention reports whether doctor counts h as a problem.
+func (h health) needsAttention() bool {
+	return h != healthOK && h != healthNonGit
+}
+
+const (
+	healthOK               health = "ok"
+	healthMissing          health = "missing"
+	healthNotARepo         health = "not-a-repo"
+	healthNonGit           health = "non-git"
+	healthPermissionDenied health = "permission-denied"
+	healthError            health = "error"
+)
+
+type entryHealth struct {
+	entry  libs.Entry
+	health health
+	err    error
+}
+
+func NewDoctorCmd(lister AliasLister, checker HealthChecker) *cobra.Command {
+	var quiet bool
+
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that every stored path still exists and is a Git repository",
+		Long: `Check every stored alias and classify its path as:
+
+  ok                → the path exists and is inside a Git repository
+  missing           → the path no longer exists
+  not-a-repo        → the path exists but is not inside a Git repository
+  non-git           → the path exists and was added with --allow-non-git
+  permission-denied → the path cannot be inspected
+  error             → the path could not be checked for another reason
+
+gs doctor exits with code 0 when every entry is ok or non-git and with code 4
+when any is not, which makes it usable as a health check, for example from
+cron with --quiet. 'gs prune' removes the entries whose path is missing.
+
+Aliases added with --allow-non-git before gs recorded it show up as
+not-a-repo; run 'gs add <alias> <path> --allow-non-git' again with the stored
+alias and path to mark them.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			checked, err := checkEntries(lister, checker)
+			if err != nil {
+				return err
+			}
+
+			unhealthy := 0
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			if !quiet {
+				fmt.Fprintln(tw, "ALIAS\tSTATUS\tPATH")
+			}
+			for _, c := range checked {
+				if c.health.needsAttention() {
+					unhealthy++
+				} else if quiet {
+					continue
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", c.entry.Alias, c.health, c.entry.Path)
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			for _, c := range checked {
+				if c.health == healthPermissionDenied || c.health == healthError {
+					fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", c.entry.Alias, c.err)
+				}
+			}
+
+			if unhealthy > 0 {
+				return &ExitError{
+					Code: ExitCodeUnhealthy,
+					Err:  fmt.Errorf("%d of %d entries need attention", unhealthy, len(checked)),
+				}
+			}
+			return nil
+		},
+	}
+
+	doctorCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "only list entries that need attention")
+	return doctorCmd
+}
+
+// checkEntries classifies every stored entry, in alias order.
+func checkEntries(lister AliasLister, checker HealthChecker) ([]entryHealth, error) {
+	entries, err := lister.List()
+	if err != nil {
+		return nil, errors.New("failed to list aliases")
+	}
+
+	checked := make([]entryHealth, 0, len(entries))
+	for _, entry := range entries {
+		h, err := checkHealth(checker, entry)
+		checked = append(checked, entryHealth{entry: entry, health: h, err: err})
+	}
+	return checked, nil
+}
+
+func checkHealth(checker HealthChecker, entry libs.Entry) (health, error) {
+	exists, err := checker.CheckIfPathExists(entry.Path)
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		return healthPermissionDenied, err
+	case err != nil:
+		return healthError, err
+	case !exists:
+		return healthMissing, nil
+	case entry.NonGit:
+		return healthNonGit, nil
+	}
+
+	_, err = checker.DetectGitRepository(entry.Path)
+	switch {
+	case err == nil:
+		return healthOK, nil
+	case errors.Is(err, libs.ErrNotGitRepository):
+		return healthNotARepo, err
+	case errors.Is(err, fs.ErrPermission):
+		return healthPermissionDenied, err
+	default:
+		return healthError, err
+	}
+}
@@ -0,0 +1,148 @@
+package cmd_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// healthEntries has one entry for every way doctor classifies a path.
+var healthEntries = []libs.Entry{
+	{Alias: "api-gw", Record: libs.Record{Path: "/src/api-gw"}},
+	{Alias: "billing", Record: libs.Record{Path: "/src/billing"}},
+	{Alias: "notes", Record: libs.Record{Path: "/src/notes"}},
+	{Alias: "secret", Record: libs.Record{Path: "/src/secret"}},
+}
+
+// scratch was added with --allow-non-git.
+var scratch = libs.Entry{Alias: "scratch", Record: libs.Record{Path: "/tmp/scratch", NonGit: true}}
+
+func expectHealth(checker *mocks.MockHealthChecker) {
+	checker.EXPECT().CheckIfPathExists("/src/api-gw").Return(true, nil)
+	checker.EXPECT().DetectGitRepository("/src/api-gw").Return(libs.GitRepository{Root: "/src/api-gw"}, nil)
+	checker.EXPECT().CheckIfPathExists("/src/billing").Return(false, nil)
+	checker.EXPECT().CheckIfPathExists("/src/notes").Return(true, nil)
+	checker.EXPECT().DetectGitRepository("/src/notes").Return(libs.GitRepository{}, fmt.Errorf("%w: no .git found", libs.ErrNotGitRepository))
+	checker.EXPECT().CheckIfPathExists("/src/secret").Return(false, &fs.PathError{Op: "stat", Path: "/src/secret", Err: fs.ErrPermission})
+}
+
+func TestDoctorCmd(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		setupMock        func(*mocks.MockAliasLister, *mocks.MockHealthChecker)
+		expectedOutput   string
+		expectedStderr   string
+		expectedError    string
+		expectedExitCode int
+	}{
+		{
+			name: "successful check of healthy entries",
+			setupMock: func(lister *mocks.MockAliasLister, checker *mocks.MockHealthChecker) {
+				lister.EXPECT().List().Return(healthEntries[:1], nil)
+				checker.EXPECT().CheckIfPathExists("/src/api-gw").Return(true, nil)
+				checker.EXPECT().DetectGitRepository("/src/api-gw").Return(libs.GitRepository{Root: "/src/api-gw"}, nil)
+			},
+			expectedOutput: "ALIAS   STATUS  PATH\n" +
+				"api-gw  ok      /src/api-gw\n",
+		},
+		{
+			name: "successful quiet check of healthy entries",
+			args: []string{"--quiet"},
+			setupMock: func(lister *mocks.MockAliasLister, checker *mocks.MockHealthChecker) {
+				lister.EXPECT().List().Return(healthEntries[:1], nil)
+				checker.EXPECT().CheckIfPathExists("/src/api-gw").Return(true, nil)
+				checker.EXPECT().DetectGitRepository("/src/api-gw").Return(libs.GitRepository{Root: "/src/api-gw"}, nil)
+			},
+		},
+		{
+			name: "unhealthy entries",
+			setupMock: func(lister *mocks.MockAliasLister, checker *mocks.MockHealthChecker) {
+				lister.EXPECT().List().Return(healthEntries, nil)
+				expectHealth(checker)
+			},
+			expectedOutput: "ALIAS    STATUS             PATH\n" +
+				"api-gw   ok                 /src/api-gw\n" +
+				"billing  missing            /src/billing\n" +
+				"notes    not-a-repo         /src/notes\n" +
+				"secret   permission-denied  /src/secret\n",
+			expectedStderr:   "secret: stat /src/secret: permission denied\n",
+			expectedError:    "3 of 4 entries need attention",
+			expectedExitCode: cmd.ExitCodeUnhealthy,
+		},
+		{
+			name: "unhealthy entries quietly",
+			args: []string{"-q"},
+			setupMock: func(lister *mocks.MockAliasLister, checker *mocks.MockHealthChecker) {
+				lister.EXPECT().List().Return(healthEntries, nil)
+				expectHealth(checker)
+			},
+			expectedOutput: "billing  missing            /src/billing\n" +
+				"notes    not-a-repo         /src/notes\n" +
+				"secret   permission-denied  /src/secret\n",
+			expectedStderr:   "secret: stat /src/secret: permission denied\n",
+			expectedError:    "3 of 4 entries need attention",
+			expectedExitCode: cmd.ExitCodeUnhealthy,
+		},
+		{
+			name: "directories added with --allow-non-git need no attention",
+			setupMock: func(lister *mocks.MockAliasLister, checker *mocks.MockHealthChecker) {
+				lister.EXPECT().List().Return([]libs.Entry{healthEntries[0], scratch}, nil)
+				checker.EXPECT().CheckIfPathExists("/src/api-gw").Return(true, nil)
+				checker.EXPECT().DetectGitRepository("/src/api-gw").Return(libs.GitRepository{Root: "/src/api-gw"}, nil)
+				checker.EXPECT().CheckIfPathExists("/tmp/scratch").Return(true, nil)
+			},
+			expectedOutput: "ALIAS    STATUS   PATH\n" +
+				"api-gw   ok       /src/api-gw\n" +
+				"scratch  non-git  /tmp/scratch\n",
+		},
+		{
+			name: "failed check due to database error",
+			setupMock: func(lister *mocks.MockAliasLister, checker *mocks.MockHealthChecker) {
+				lister.EXPECT().List().Return(nil, assert.AnError)
+			},
+			expectedError: "failed to list aliases",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLister := mocks.NewMockAliasLister(ctrl)
+			mockChecker := mocks.NewMockHealthChecker(ctrl)
+			tt.setupMock(mockLister, mockChecker)
+			doctorCmd := cmd.NewDoctorCmd(mockLister, mockChecker)
+
+			var out, stderr bytes.Buffer
+			doctorCmd.SetOut(&out)
+			doctorCmd.SetErr(&stderr)
+			doctorCmd.SetArgs(tt.args)
+			err := doctorCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+			if tt.expectedExitCode != 0 {
+				var exitErr *cmd.ExitError
+				assert.True(t, errors.As(err, &exitErr))
+				assert.Equal(t, tt.expectedExitCode, exitErr.Code)
+			}
+			assert.Equal(t, tt.expectedOutput, out.String())
+			if tt.expectedStderr != "" {
+				assert.Contains(t, stderr.String(), tt.expectedStderr)
+			}
+		})
+	}
+}
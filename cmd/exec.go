@@ -0,0 +1,161 @@
+//go:generate mockgen -destination=../mocks/cmd/exec.go -package=mocks -source=exec.go
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gs/libs"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type AliasIterator interface {
+	ForEach(fn func(entry libs.Entry) error) error
+}
+
+type ProjectSelector interface {
+	AliasIterator
+	DBReleaser
+}
+
+type ProjectRunner interface {
+	Run(ctx context.Context, projects []libs.Entry, command []string, opts libs.ExecOptions) []libs.ExecResult
+}
+
+func NewExecCmd(selector ProjectSelector, runner ProjectRunner) *cobra.Command {
+	var tags []string
+	var parallel int
+	var failFast, keepGoing, noSummary bool
+
+	execCmd := &cobra.Command{
+		Use:   "exec [alias]... -- <command> [arg]...",
+		Short: "Run a command in several projects",
+		Long: `Run a command in the directory of each selected project, for example
+'gs exec --tag backend -- git pull --ff-only'.
+
+Projects are selected by alias, by --tag or both; with neither, the command
+runs in every project. It is run directly, not through a shell; use
+'-- sh -c "..."' for pipes and globbing.
+
+Output lines are prefixed with the alias and never mix within a line, even
+with --parallel above 1. A summary of every project's exit status follows.
+By default all projects run even if some fail (--keep-going); --fail-fast
+stops starting projects and kills running ones after the first failure.
+gs exits with code 1 if the command failed in any project.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if cmd.ArgsLenAtDash() < 0 || cmd.ArgsLenAtDash() == len(args) {
+				return errors.New("missing command, use: gs exec [alias]... -- <command> [arg]...")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			aliases, command := args[:cmd.ArgsLenAtDash()], args[cmd.ArgsLenAtDash():]
+
+			projects, err := selectProjects(selector, uniqueStrings(aliases), tags)
+			if err != nil {
+				return err
+			}
+			if len(projects) == 0 {
+				return errors.New("no projects selected")
+			}
+			// The commands may run gs themselves, which needs the database
+			if err := selector.Release(); err != nil {
+				return errors.New("failed to close the database")
+			}
+
+			results := runner.Run(cmd.Context(), projects, command, libs.ExecOptions{
+				Parallel: parallel,
+				FailFast: failFast,
+				Stdout:   cmd.OutOrStdout(),
+				Stderr:   cmd.ErrOrStderr(),
+			})
+
+			if !noSummary {
+				if err := writeExecSummary(cmd.OutOrStdout(), results); err != nil {
+					return err
+				}
+			}
+
+			failed := 0
+			for _, result := range results {
+				if result.Status != libs.ExecOK {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return &ExitError{
+					Code: ExitCodeError,
+					Err:  fmt.Errorf("command did not succeed in %d of %d projects", failed, len(results)),
+				}
+			}
+			return nil
+		},
+	}
+
+	execCmd.Flags().StringArrayVarP(&tags, "tag", "t", nil, "run in projects with this tag (repeatable)")
+	execCmd.Flags().IntVarP(&parallel, "parallel", "j", 4, "number of projects to run at once")
+	execCmd.Flags().BoolVar(&failFast, "fail-fast", false, "stop after the first project that fails")
+	execCmd.Flags().BoolVar(&keepGoing, "keep-going", false, "run in every project even if some fail (default)")
+	execCmd.Flags().BoolVar(&noSummary, "no-summary", false, "do not print the summary table")
+	execCmd.MarkFlagsMutuallyExclusive("fail-fast", "keep-going")
+	return execCmd
+}
+
+// selectProjects returns the entries named by aliases and those with every
+// one of tags, in alias order. With neither, it returns every entry.
+func selectProjects(iterator AliasIterator, aliases, tags []string) ([]libs.Entry, error) {
+	wanted := make(map[string]bool, len(aliases))
+	for _, alias := range aliases {
+		wanted[alias] = true
+	}
+
+	var projects []libs.Entry
+	err := iterator.ForEach(func(entry libs.Entry) error {
+		named := wanted[entry.Alias]
+		delete(wanted, entry.Alias)
+
+		switch {
+		case named:
+		case len(tags) > 0:
+			for _, tag := range tags {
+				if !entry.HasTag(tag) {
+					return nil
+				}
+			}
+		case len(aliases) > 0:
+			return nil
+		}
+		projects = append(projects, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.New("failed to list aliases")
+	}
+
+	for _, alias := range aliases {
+		if wanted[alias] {
+			return nil, aliasError(fmt.Errorf("%w: %s", libs.ErrAliasNotFound, alias), "")
+		}
+	}
+	return projects, nil
+}
+
+func writeExecSummary(w io.Writer, results []libs.ExecResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "ALIAS\tSTATUS\tEXIT\tTIME")
+	for _, result := range results {
+		exitCode, duration := "-", "-"
+		if result.Status == libs.ExecOK || result.Status == libs.ExecFailed {
+			exitCode = fmt.Sprint(result.ExitCode)
+			duration = result.Duration.Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.Alias, result.Status, exitCode, duration)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,186 @@
+package cmd_test
+
+import (
+	"bytes"
+	"errors"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExecCmd(t *testing.T) {
+	entries := []libs.Entry{
+		{Alias: "api-gw", Record: libs.Record{Path: "/src/api-gw", Tags: []string{"backend", "payments"}}},
+		{Alias: "billing", Record: libs.Record{Path: "/src/billing", Tags: []string{"backend"}}},
+		{Alias: "docs", Record: libs.Record{Path: "/src/docs"}},
+	}
+	forEach := func(fn func(libs.Entry) error) error {
+		for _, entry := range entries {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	ok := func(entry libs.Entry) libs.ExecResult {
+		return libs.ExecResult{Alias: entry.Alias, Path: entry.Path, Status: libs.ExecOK, Duration: 1200 * time.Millisecond}
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		setupMock        func(*mocks.MockProjectSelector, *mocks.MockProjectRunner)
+		expectedOutput   string
+		expectedError    string
+		expectedExitCode int
+	}{
+		{
+			name: "successful run in tagged projects",
+			args: []string{"--tag", "backend", "-j", "2", "--", "git", "pull", "--ff-only"},
+			setupMock: func(selector *mocks.MockProjectSelector, runner *mocks.MockProjectRunner) {
+				selector.EXPECT().ForEach(gomock.Any()).DoAndReturn(forEach)
+				// The database is released before the commands run
+				release := selector.EXPECT().Release().Return(nil)
+				runner.EXPECT().Run(gomock.Any(), entries[:2], []string{"git", "pull", "--ff-only"}, gomock.Any()).
+					After(release).
+					DoAndReturn(func(_ any, projects []libs.Entry, _ []string, opts libs.ExecOptions) []libs.ExecResult {
+						assert.Equal(t, 2, opts.Parallel)
+						assert.False(t, opts.FailFast)
+						return []libs.ExecResult{ok(projects[0]), ok(projects[1])}
+					})
+			},
+			expectedOutput: "\nALIAS    STATUS  EXIT  TIME\n" +
+				"api-gw   ok      0     1.2s\n" +
+				"billing  ok      0     1.2s\n",
+		},
+		{
+			name: "successful run in every project",
+			args: []string{"--no-summary", "--", "make"},
+			setupMock: func(selector *mocks.MockProjectSelector, runner *mocks.MockProjectRunner) {
+				selector.EXPECT().ForEach(gomock.Any()).DoAndReturn(forEach)
+				selector.EXPECT().Release().Return(nil)
+				runner.EXPECT().Run(gomock.Any(), entries, []string{"make"}, gomock.Any()).
+					Return([]libs.ExecResult{ok(entries[0]), ok(entries[1]), ok(entries[2])})
+			},
+		},
+		{
+			name: "successful run in named and tagged projects",
+			args: []string{"docs", "--tag", "payments", "--no-summary", "--", "make"},
+			setupMock: func(selector *mocks.MockProjectSelector, runner *mocks.MockProjectRunner) {
+				selector.EXPECT().ForEach(gomock.Any()).DoAndReturn(forEach)
+				selector.EXPECT().Release().Return(nil)
+				runner.EXPECT().Run(gomock.Any(), []libs.Entry{entries[0], entries[2]}, []string{"make"}, gomock.Any()).
+					Return([]libs.ExecResult{ok(entries[0]), ok(entries[2])})
+			},
+		},
+		{
+			name: "failed run in some projects",
+			args: []string{"--fail-fast", "api-gw", "billing", "docs", "--", "make", "test"},
+			setupMock: func(selector *mocks.MockProjectSelector, runner *mocks.MockProjectRunner) {
+				selector.EXPECT().ForEach(gomock.Any()).DoAndReturn(forEach)
+				selector.EXPECT().Release().Return(nil)
+				runner.EXPECT().Run(gomock.Any(), entries, []string{"make", "test"}, gomock.Any()).
+					DoAndReturn(func(_ any, projects []libs.Entry, _ []string, opts libs.ExecOptions) []libs.ExecResult {
+						assert.True(t, opts.FailFast)
+						return []libs.ExecResult{
+							ok(projects[0]),
+							{Alias: "billing", Status: libs.ExecFailed, ExitCode: 2, Duration: 300 * time.Millisecond},
+							{Alias: "docs", Status: libs.ExecSkipped},
+						}
+					})
+			},
+			expectedOutput: "\nALIAS    STATUS   EXIT  TIME\n" +
+				"api-gw   ok       0     1.2s\n" +
+				"billing  failed   2     300ms\n" +
+				"docs     skipped  -     -\n",
+			expectedError:    "command did not succeed in 2 of 3 projects",
+			expectedExitCode: cmd.ExitCodeError,
+		},
+		{
+			name: "failed run due to database that cannot be closed",
+			args: []string{"--", "make"},
+			setupMock: func(selector *mocks.MockProjectSelector, runner *mocks.MockProjectRunner) {
+				selector.EXPECT().ForEach(gomock.Any()).DoAndReturn(forEach)
+				selector.EXPECT().Release().Return(assert.AnError)
+			},
+			expectedError: "failed to close the database",
+		},
+		{
+			name: "failed run due to unknown alias",
+			args: []string{"api-gw", "ghost", "--", "make"},
+			setupMock: func(selector *mocks.MockProjectSelector, runner *mocks.MockProjectRunner) {
+				selector.EXPECT().ForEach(gomock.Any()).DoAndReturn(forEach)
+			},
+			expectedError:    "alias not found: ghost",
+			expectedExitCode: cmd.ExitCodeAliasNotFound,
+		},
+		{
+			name: "failed run due to no matching projects",
+			args: []string{"--tag", "frontend", "--", "make"},
+			setupMock: func(selector *mocks.MockProjectSelector, runner *mocks.MockProjectRunner) {
+				selector.EXPECT().ForEach(gomock.Any()).DoAndReturn(forEach)
+			},
+			expectedError: "no projects selected",
+		},
+		{
+			name: "failed run due to database error",
+			args: []string{"--", "make"},
+			setupMock: func(selector *mocks.MockProjectSelector, runner *mocks.MockProjectRunner) {
+				selector.EXPECT().ForEach(gomock.Any()).Return(assert.AnError)
+			},
+			expectedError: "failed to list aliases",
+		},
+		{
+			name:          "failed run due to missing command",
+			args:          []string{"api-gw"},
+			expectedError: "missing command, use: gs exec [alias]... -- <command> [arg]...",
+		},
+		{
+			name:          "failed run due to conflicting flags",
+			args:          []string{"--fail-fast", "--keep-going", "--", "make"},
+			expectedError: "if any flags in the group [fail-fast keep-going] are set none of the others can be; [fail-fast keep-going] were all set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSelector := mocks.NewMockProjectSelector(ctrl)
+			mockRunner := mocks.NewMockProjectRunner(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(mockSelector, mockRunner)
+			}
+			execCmd := cmd.NewExecCmd(mockSelector, mockRunner)
+
+			var out bytes.Buffer
+			execCmd.SetOut(&out)
+			execCmd.SetErr(&bytes.Buffer{})
+			execCmd.SetArgs(tt.args)
+			err := execCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+				return
+			}
+
+			if tt.expectedOutput != "" {
+				assert.Equal(t, tt.expectedOutput, out.String())
+			}
+
+			assert.EqualError(t, err, tt.expectedError)
+			if tt.expectedExitCode != 0 {
+				var exitErr *cmd.ExitError
+				assert.True(t, errors.As(err, &exitErr))
+				assert.Equal(t, tt.expectedExitCode, exitErr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+//go:embed shells
+var shellTemplates embed.FS
+
+var shellTemplateFiles = map[string]string{
+	"bash": "shells/gs.bash",
+	"zsh":  "shells/gs.zsh",
+	"fish": "shells/gs.fish",
+	"pwsh": "shells/gs.ps1",
+}
+
+type shellTemplateData struct {
+	Name     string
+	Commands []string
+	// SwitchFlags and ValueFlags are the global flags the wrapper skips to
+	// find the alias or subcommand, without and with a value.
+	SwitchFlags []string
+	ValueFlags  []string
+}
+
+func NewInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init <shell>",
+		Short: "Print the shell integration for bash, zsh, fish or pwsh",
+		Long: `Print a shell function that wraps gs so that 'gs <alias>', 'gs -' and the
+'gs' picker change the working directory.
+
+A program cannot change the directory of the shell that started it, so the
+wrapper runs gs, and on success cd's into the printed path. Subcommands such
+as 'gs add' are passed straight through. Global flags may come first, as in
+'gs --profile work <alias>'. Shell completion is set up as well.
+
+Usage:
+  bash: eval "$(gs init bash)"                        in ~/.bashrc
+  zsh:  eval "$(gs init zsh)"                         in ~/.zshrc
+  fish: gs init fish | source                         in ~/.config/fish/config.fish
+  pwsh: Invoke-Expression (& gs init pwsh | Out-String) in $PROFILE`,
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{"bash", "zsh", "fish", "pwsh"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file := shellTemplateFiles[args[0]]
+			tmpl, err := template.New(path.Base(file)).
+				Funcs(template.FuncMap{"join": strings.Join}).
+				ParseFS(shellTemplates, file)
+			if err != nil {
+				return fmt.Errorf("failed to load %s shell integration", args[0])
+			}
+
+			root := cmd.Root()
+			data := shellTemplateData{
+				Name:     root.Name(),
+				Commands: passthroughCommands(root),
+			}
+			data.SwitchFlags, data.ValueFlags = globalFlags(root)
+
+			return tmpl.Execute(cmd.OutOrStdout(), data)
+		},
+	}
+}
+
+// passthroughCommands lists every subcommand name and alias of root, which the
+// shell wrapper must hand to the binary untouched instead of treating as an alias.
+// Subcommands marked with switchAnnotation are left out so the wrapper cd's
+// into their output. Cobra only registers its hidden completion request
+// commands while they run, so they are added explicitly.
+func passthroughCommands(root *cobra.Command) []string {
+	names := []string{cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
+	for _, c := range root.Commands() {
+		if c.Annotations[switchAnnotation] != "" {
+			continue
+		}
+		names = append(names, c.Name())
+		names = append(names, c.Aliases...)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// globalFlags returns the long flags of root that may come before an alias
+// or subcommand, split into those without and with a value. --help is left
+// out so that the wrapper passes it through.
+func globalFlags(root *cobra.Command) (switches, values []string) {
+	visit := func(flag *pflag.Flag) {
+		switch {
+		case flag.Name == "help":
+		case flag.NoOptDefVal != "":
+			switches = append(switches, "--"+flag.Name)
+		default:
+			values = append(values, "--"+flag.Name)
+		}
+	}
+	root.PersistentFlags().VisitAll(visit)
+	root.LocalNonPersistentFlags().VisitAll(visit)
+	sort.Strings(switches)
+	sort.Strings(values)
+	return switches, values
+}
@@ -0,0 +1,204 @@
+package cmd_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func assertGolden(t *testing.T, goldenPath string, actual []byte) {
+	t.Helper()
+
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(goldenPath), 0755))
+		require.NoError(t, os.WriteFile(goldenPath, actual, 0644))
+	}
+
+	expected, err := os.ReadFile(goldenPath)
+	require.NoError(t, err, "run 'go test ./cmd -update' to create the golden file")
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestInitCmd(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		golden        string
+		expectedError string
+	}{
+		{
+			name:   "successful bash",
+			args:   []string{"init", "bash"},
+			golden: "bash.golden",
+		},
+		{
+			name:   "successful zsh",
+			args:   []string{"init", "zsh"},
+			golden: "zsh.golden",
+		},
+		{
+			name:   "successful fish",
+			args:   []string{"init", "fish"},
+			golden: "fish.golden",
+		},
+		{
+			name:   "successful pwsh",
+			args:   []string{"init", "pwsh"},
+			golden: "pwsh.golden",
+		},
+		{
+			name:          "failed due to unsupported shell",
+			args:          []string{"init", "tcsh"},
+			expectedError: `invalid argument "tcsh" for "gs init"`,
+		},
+		{
+			name:          "failed due to missing shell",
+			args:          []string{"init"},
+			expectedError: "accepts 1 arg(s), received 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// gs init only releases the database, see TestInitCmd_LockedDatabase
+			mockDBService := mocks.NewMockRootDBService(ctrl)
+			mockDBService.EXPECT().Release().Return(nil).AnyTimes()
+			rootCmd := cmd.NewRootCommand(mockDBService, mocks.NewMockRootFileService(ctrl), mocks.NewMockMigrator(ctrl), mocks.NewMockProjectPicker(ctrl), mocks.NewMockProjectRunner(ctrl), mocks.NewMockStatusReader(ctrl), mocks.NewMockConfigManager(ctrl))
+
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args)
+			err := rootCmd.Execute()
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assertGolden(t, filepath.Join("testdata", "init", tt.golden), out.Bytes())
+		})
+	}
+}
+
+// TestInitCmd_LockedDatabase makes sure that shell startup and completion of
+// commands that do not use the database work while another gs process holds
+// it, for example while 'gs remove' waits for confirmation.
+func TestInitCmd_LockedDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bbolt.db")
+	holder, err := libs.OpenBoltDB(path, "gs")
+	require.NoError(t, err)
+	require.NoError(t, holder.View(func(tx libs.Tx) error { return nil }))
+	defer holder.Close()
+
+	for _, args := range [][]string{
+		{"init", "bash"},
+		{cobra.ShellCompRequestCmd, "init", ""},
+		{"completion", "bash"},
+	} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			db, err := libs.OpenBoltDB(path, "gs", libs.WithLockTimeout(50*time.Millisecond))
+			require.NoError(t, err)
+			require.ErrorIs(t, db.View(func(tx libs.Tx) error { return nil }), libs.ErrDBLocked)
+
+			rootCmd := cmd.NewRootCommand(libs.NewDBService(db, "gs"), mocks.NewMockRootFileService(ctrl), mocks.NewMockMigrator(ctrl), mocks.NewMockProjectPicker(ctrl), mocks.NewMockProjectRunner(ctrl), mocks.NewMockStatusReader(ctrl), mocks.NewMockConfigManager(ctrl))
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(args)
+
+			require.NoError(t, rootCmd.Execute())
+			assert.NotEmpty(t, out.String())
+		})
+	}
+}
+
+// TestInitCmd_GlobalFlags runs the wrapper of every installed shell against a
+// fake gs binary and records where each call leaves the shell, so that
+// global flags before an alias still switch and before a subcommand still
+// pass through.
+func TestInitCmd_GlobalFlags(t *testing.T) {
+	calls := [][]string{
+		{"myproj"},
+		{"--profile", "work", "myproj"},
+		{"--db", "/x/gs.db", "--exact", "myproj"},
+		{"--profile=work", "myproj"},
+		{"--profile", "work", "list"},
+		{"--profile", "work", "--help"},
+		{"--profile", "work"},
+	}
+
+	shells := []struct {
+		shell   string
+		command []string
+		call    string
+	}{
+		{shell: "bash", command: []string{"bash", "--norc", "-c"}, call: `cd "$GS_TEST_ROOT"; gs %s; echo "pwd=${PWD#$GS_TEST_ROOT}"`},
+		{shell: "zsh", command: []string{"zsh", "-f", "-c"}, call: `cd "$GS_TEST_ROOT"; gs %s; echo "pwd=${PWD#$GS_TEST_ROOT}"`},
+		{shell: "fish", command: []string{"fish", "--no-config", "-c"}, call: `cd $GS_TEST_ROOT; gs %s; echo pwd=(string replace $GS_TEST_ROOT '' $PWD)`},
+		{shell: "pwsh", command: []string{"pwsh", "-NoProfile", "-Command"}, call: `Set-Location $env:GS_TEST_ROOT; gs %s; "pwd=" + $PWD.Path.Substring($env:GS_TEST_ROOT.Length)`},
+	}
+
+	for _, sh := range shells {
+		t.Run(sh.shell, func(t *testing.T) {
+			if _, err := exec.LookPath(sh.command[0]); err != nil {
+				t.Skipf("%s is not installed", sh.command[0])
+			}
+
+			// The fake gs prints the directory of the last argument, or what
+			// it was run with when that is a subcommand or a flag
+			root := t.TempDir()
+			bin := filepath.Join(root, "bin")
+			require.NoError(t, os.MkdirAll(filepath.Join(root, "myproj"), 0755))
+			require.NoError(t, os.MkdirAll(bin, 0755))
+			fake := "#!/bin/sh\n" +
+				"[ \"$1\" = completion ] && exit 0\n" +
+				"for arg; do last=$arg; done\n" +
+				"case \"$last\" in myproj) echo \"$GS_TEST_ROOT/myproj\" ;; --profile) echo \"$GS_TEST_ROOT\" ;; work) echo \"$GS_TEST_ROOT/myproj\" ;; *) echo \"ran: $*\" ;; esac\n"
+			require.NoError(t, os.WriteFile(filepath.Join(bin, "gs"), []byte(fake), 0755))
+
+			mockDBService := mocks.NewMockRootDBService(gomock.NewController(t))
+			mockDBService.EXPECT().Release().Return(nil)
+			rootCmd := cmd.NewRootCommand(mockDBService, nil, nil, nil, nil, nil, nil)
+			var wrapper bytes.Buffer
+			rootCmd.SetOut(&wrapper)
+			rootCmd.SetArgs([]string{"init", sh.shell})
+			require.NoError(t, rootCmd.Execute())
+
+			script := wrapper.String()
+			for _, call := range calls {
+				script += fmt.Sprintf("\necho '> gs %s'\n", strings.Join(call, " "))
+				script += fmt.Sprintf(sh.call, strings.Join(call, " ")) + "\n"
+			}
+
+			run := exec.Command(sh.command[0], append(sh.command[1:], script)...)
+			run.Env = append(os.Environ(), "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"), "GS_TEST_ROOT="+root)
+			out, err := run.CombinedOutput()
+			require.NoError(t, err, string(out))
+			assertGolden(t, filepath.Join("testdata", "init", "global_flags.golden"), out)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+//go:generate mockgen -destination=../mocks/cmd/list.go -package=mocks -source=list.go
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"gs/libs"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+type AliasLister interface {
+	List() ([]libs.Entry, error)
+}
+
+var listOutputFormats = []string{"table", "plain", "json", "yaml"}
+
+var listSortKeys = []string{"alias", "path", "last-used"}
+
+type listItem struct {
+	Alias    string   `json:"alias" yaml:"alias"`
+	Path     string   `json:"path" yaml:"path"`
+	LastUsed string   `json:"last_used,omitempty" yaml:"last_used,omitempty"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+func NewListCmd(lister AliasLister) *cobra.Command {
+	var output, sortBy string
+	var tags []string
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the Git projects stored in gitswitch",
+		Long: `List every alias stored in gitswitch together with its path.
+
+Output formats:
+  table → aligned columns for humans (default)
+  plain → one "alias<TAB>path" line per entry for shell scripts
+  json  → a JSON array of objects
+  yaml  → a YAML sequence of mappings
+
+--tag limits the list to aliases with the given tag. When repeated, aliases
+must have every one of the tags.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !contains(listOutputFormats, output) {
+				return fmt.Errorf("invalid output format %q, must be one of %v", output, listOutputFormats)
+			}
+			if !contains(listSortKeys, sortBy) {
+				return fmt.Errorf("invalid sort key %q, must be one of %v", sortBy, listSortKeys)
+			}
+
+			entries, err := lister.List()
+			if err != nil {
+				return errors.New("failed to list aliases")
+			}
+
+			entries = filterByTags(entries, tags)
+			sortEntries(entries, sortBy)
+			return writeEntries(cmd.OutOrStdout(), entries, output)
+		},
+	}
+
+	listCmd.Flags().StringVarP(&output, "output", "o", "table", "output format: table, plain, json or yaml")
+	listCmd.Flags().StringVarP(&sortBy, "sort", "s", "alias", "sort by alias, path or last-used")
+	listCmd.Flags().StringArrayVarP(&tags, "tag", "t", nil, "only list aliases with this tag (repeatable)")
+	return listCmd
+}
+
+// filterByTags keeps the entries that have every one of tags.
+func filterByTags(entries []libs.Entry, tags []string) []libs.Entry {
+	if len(tags) == 0 {
+		return entries
+	}
+	var filtered []libs.Entry
+	for _, entry := range entries {
+		tagged := true
+		for _, tag := range tags {
+			tagged = tagged && entry.HasTag(tag)
+		}
+		if tagged {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+func sortEntries(entries []libs.Entry, sortBy string) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch sortBy {
+		case "path":
+			if a.Path != b.Path {
+				return a.Path < b.Path
+			}
+		case "last-used":
+			// Most recently used first; never-used entries sink to the bottom
+			if !a.LastUsed.Equal(b.LastUsed) {
+				return a.LastUsed.After(b.LastUsed)
+			}
+		}
+		return a.Alias < b.Alias
+	})
+}
+
+func writeEntries(w io.Writer, entries []libs.Entry, output string) error {
+	items := make([]listItem, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, listItem{Alias: e.Alias, Path: e.Path, LastUsed: formatTime(e.LastUsed), Tags: e.Tags})
+	}
+
+	switch output {
+	case "plain":
+		for _, item := range items {
+			if _, err := fmt.Fprintf(w, "%s\t%s\n", item.Alias, item.Path); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(items)
+	case "yaml":
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(items); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ALIAS\tPATH\tLAST USED")
+		for _, item := range items {
+			lastUsed := item.LastUsed
+			if lastUsed == "" {
+				lastUsed = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", item.Alias, item.Path, lastUsed)
+		}
+		return tw.Flush()
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package cmd_test
+
+import (
+	"bytes"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListCmd(t *testing.T) {
+	entries := []libs.Entry{
+		{Alias: "web", Record: libs.Record{Path: "/src/a-web", LastUsed: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}},
+		{Alias: "api", Record: libs.Record{Path: "/src/z-api"}},
+		{Alias: "cli", Record: libs.Record{Path: "/src/m-cli", LastUsed: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}},
+	}
+
+	tagged := []libs.Entry{
+		{Alias: "api-gw", Record: libs.Record{Path: "/src/api-gw", Tags: []string{"payments", "platform"}}},
+		{Alias: "billing", Record: libs.Record{Path: "/src/billing", Tags: []string{"payments"}}},
+		{Alias: "docs", Record: libs.Record{Path: "/src/docs"}},
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		mockList       MockCall[[]libs.Entry]
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:     "successful table sorted by alias",
+			args:     []string{},
+			mockList: MockCall[[]libs.Entry]{Times: 1, Response: entries},
+			expectedOutput: "ALIAS  PATH        LAST USED\n" +
+				"api    /src/z-api  -\n" +
+				"cli    /src/m-cli  2025-06-01T00:00:00Z\n" +
+				"web    /src/a-web  2025-01-02T03:04:05Z\n",
+		},
+		{
+			name:     "successful plain sorted by path",
+			args:     []string{"--output", "plain", "--sort", "path"},
+			mockList: MockCall[[]libs.Entry]{Times: 1, Response: entries},
+			expectedOutput: "web\t/src/a-web\n" +
+				"cli\t/src/m-cli\n" +
+				"api\t/src/z-api\n",
+		},
+		{
+			name:     "successful plain sorted by last used",
+			args:     []string{"-o", "plain", "-s", "last-used"},
+			mockList: MockCall[[]libs.Entry]{Times: 1, Response: entries},
+			expectedOutput: "cli\t/src/m-cli\n" +
+				"web\t/src/a-web\n" +
+				"api\t/src/z-api\n",
+		},
+		{
+			name:     "successful json",
+			args:     []string{"-o", "json"},
+			mockList: MockCall[[]libs.Entry]{Times: 1, Response: entries[:2]},
+			expectedOutput: `[
+  {
+    "alias": "api",
+    "path": "/src/z-api"
+  },
+  {
+    "alias": "web",
+    "path": "/src/a-web",
+    "last_used": "2025-01-02T03:04:05Z"
+  }
+]
+`,
+		},
+		{
+			name:           "successful json with no entries",
+			args:           []string{"-o", "json"},
+			mockList:       MockCall[[]libs.Entry]{Times: 1},
+			expectedOutput: "[]\n",
+		},
+		{
+			name:     "successful yaml",
+			args:     []string{"-o", "yaml"},
+			mockList: MockCall[[]libs.Entry]{Times: 1, Response: entries[:2]},
+			expectedOutput: `- alias: api
+  path: /src/z-api
+- alias: web
+  path: /src/a-web
+  last_used: "2025-01-02T03:04:05Z"
+`,
+		},
+		{
+			name:     "successful plain filtered by tag",
+			args:     []string{"-o", "plain", "--tag", "payments"},
+			mockList: MockCall[[]libs.Entry]{Times: 1, Response: tagged},
+			expectedOutput: "api-gw\t/src/api-gw\n" +
+				"billing\t/src/billing\n",
+		},
+		{
+			name:           "successful plain filtered by every given tag",
+			args:           []string{"-o", "plain", "-t", "payments", "-t", "platform"},
+			mockList:       MockCall[[]libs.Entry]{Times: 1, Response: tagged},
+			expectedOutput: "api-gw\t/src/api-gw\n",
+		},
+		{
+			name:     "successful json with tags",
+			args:     []string{"-o", "json", "--tag", "platform"},
+			mockList: MockCall[[]libs.Entry]{Times: 1, Response: tagged},
+			expectedOutput: `[
+  {
+    "alias": "api-gw",
+    "path": "/src/api-gw",
+    "tags": [
+      "payments",
+      "platform"
+    ]
+  }
+]
+`,
+		},
+		{
+			name:           "successful json with unknown tag",
+			args:           []string{"-o", "json", "--tag", "nope"},
+			mockList:       MockCall[[]libs.Entry]{Times: 1, Response: tagged},
+			expectedOutput: "[]\n",
+		},
+		{
+			name:          "failed due to invalid output format",
+			args:          []string{"-o", "xml"},
+			expectedError: `invalid output format "xml", must be one of [table plain json yaml]`,
+		},
+		{
+			name:          "failed due to invalid sort key",
+			args:          []string{"-s", "size"},
+			expectedError: `invalid sort key "size", must be one of [alias path last-used]`,
+		},
+		{
+			name:          "failed due to fail to list from database",
+			args:          []string{},
+			mockList:      MockCall[[]libs.Entry]{Times: 1, Error: assert.AnError},
+			expectedError: "failed to list aliases",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLister := mocks.NewMockAliasLister(ctrl)
+			listCmd := cmd.NewListCmd(mockLister)
+
+			if tt.mockList.Times > 0 {
+				// Hand out a copy so sorting in one case cannot leak into another
+				response := append([]libs.Entry(nil), tt.mockList.Response...)
+				mockLister.EXPECT().List().Return(response, tt.mockList.Error).Times(tt.mockList.Times)
+			}
+
+			var out bytes.Buffer
+			listCmd.SetOut(&out)
+			listCmd.SetErr(&bytes.Buffer{})
+			listCmd.SetArgs(tt.args)
+			err := listCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
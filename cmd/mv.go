@@ -0,0 +1,60 @@
+//go:generate mockgen -destination=../mocks/cmd/mv.go -package=mocks -source=mv.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+type AliasMover interface {
+	AliasResolver
+	Move(alias, path string) error
+}
+
+func NewMvCmd(mover AliasMover, fileService FileService) *cobra.Command {
+	var dryRun, noResolveSymlinks bool
+
+	mvCmd := &cobra.Command{
+		Use:   "mv <alias> <path>",
+		Short: "Point an existing alias at a new path",
+		Long: `Point an existing alias at a new path, for example after moving a project on disk.
+
+The path is stored in canonical form, as with 'gs add'. Use 'gs rename' to
+change the alias itself.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			alias := args[0]
+
+			path, err := normalizePath(args[1], fileService, !noResolveSymlinks)
+			if err != nil {
+				return err
+			}
+
+			isPathExists, err := fileService.CheckIfPathExists(path)
+			if err != nil || !isPathExists {
+				return errors.New("path does not exist")
+			}
+
+			if dryRun {
+				oldPath, err := lookupAlias(mover, alias)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "would point %s at %s (was %s)\n", alias, path, oldPath)
+				return nil
+			}
+
+			if err := mover.Move(alias, path); err != nil {
+				return aliasError(err, fmt.Sprintf("failed to point %s at %s", alias, path))
+			}
+
+			return nil
+		},
+	}
+
+	mvCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the change without applying it")
+	mvCmd.Flags().BoolVar(&noResolveSymlinks, "no-resolve-symlinks", false, "store symlinked paths without resolving them")
+	return mvCmd
+}
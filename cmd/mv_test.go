@@ -0,0 +1,106 @@
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMvCmd(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		setupMock      func(*mocks.MockAliasMover, *mocks.MockFileService)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name: "successful move",
+			args: []string{"project", "/src/new"},
+			setupMock: func(m *mocks.MockAliasMover, f *mocks.MockFileService) {
+				f.EXPECT().NormalizePath("/src/new", true).Return("/src/new", nil)
+				f.EXPECT().CheckIfPathExists("/src/new").Return(true, nil)
+				m.EXPECT().Move("project", "/src/new").Return(nil)
+			},
+		},
+		{
+			name: "successful dry run",
+			args: []string{"project", "/src/new", "--dry-run"},
+			setupMock: func(m *mocks.MockAliasMover, f *mocks.MockFileService) {
+				f.EXPECT().NormalizePath("/src/new", true).Return("/src/new", nil)
+				f.EXPECT().CheckIfPathExists("/src/new").Return(true, nil)
+				m.EXPECT().Get("project").Return("/src/old", nil)
+			},
+			expectedOutput: "would point project at /src/new (was /src/old)\n",
+		},
+		{
+			name: "successful move of a relative path keeping symlinks",
+			args: []string{"project", "../new", "--no-resolve-symlinks"},
+			setupMock: func(m *mocks.MockAliasMover, f *mocks.MockFileService) {
+				f.EXPECT().NormalizePath("../new", false).Return("/src/new", nil)
+				f.EXPECT().CheckIfPathExists("/src/new").Return(true, nil)
+				m.EXPECT().Move("project", "/src/new").Return(nil)
+			},
+		},
+		{
+			name: "failed due to missing path",
+			args: []string{"project", "/src/new"},
+			setupMock: func(m *mocks.MockAliasMover, f *mocks.MockFileService) {
+				f.EXPECT().NormalizePath("/src/new", true).Return("/src/new", nil)
+				f.EXPECT().CheckIfPathExists("/src/new").Return(false, nil)
+			},
+			expectedError: "path does not exist",
+		},
+		{
+			name: "failed due to unknown alias",
+			args: []string{"project", "/src/new"},
+			setupMock: func(m *mocks.MockAliasMover, f *mocks.MockFileService) {
+				f.EXPECT().NormalizePath("/src/new", true).Return("/src/new", nil)
+				f.EXPECT().CheckIfPathExists("/src/new").Return(true, nil)
+				m.EXPECT().Move("project", "/src/new").Return(fmt.Errorf("%w: %s", libs.ErrAliasNotFound, "project"))
+			},
+			expectedError: "alias not found: project",
+		},
+		{
+			name: "failed due to database error",
+			args: []string{"project", "/src/new"},
+			setupMock: func(m *mocks.MockAliasMover, f *mocks.MockFileService) {
+				f.EXPECT().NormalizePath("/src/new", true).Return("/src/new", nil)
+				f.EXPECT().CheckIfPathExists("/src/new").Return(true, nil)
+				m.EXPECT().Move("project", "/src/new").Return(assert.AnError)
+			},
+			expectedError: "failed to point project at /src/new",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMover := mocks.NewMockAliasMover(ctrl)
+			mockFileService := mocks.NewMockFileService(ctrl)
+			tt.setupMock(mockMover, mockFileService)
+			mvCmd := cmd.NewMvCmd(mockMover, mockFileService)
+
+			var out bytes.Buffer
+			mvCmd.SetOut(&out)
+			mvCmd.SetErr(&bytes.Buffer{})
+			mvCmd.SetArgs(tt.args)
+			err := mvCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
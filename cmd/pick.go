@@ -0,0 +1,120 @@
+//go:generate mockgen -destination=../mocks/cmd/pick.go -package=mocks -source=pick.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"gs/libs"
+
+	"github.com/spf13/cobra"
+)
+
+// ExitCodeCancelled is returned when the picker is closed without a choice,
+// matching what shells report for Ctrl-C.
+const ExitCodeCancelled = 130
+
+// switchAnnotation marks subcommands that print a path to switch to, so the
+// shell wrapper cd's into their output instead of passing them through.
+const switchAnnotation = "gs/switch"
+
+type ProjectPicker interface {
+	Pick(items []libs.PickerItem) (libs.PickerItem, error)
+	PickWith(finder string, items []libs.PickerItem) (libs.PickerItem, error)
+}
+
+type BranchReader interface {
+	GitBranch(path string) (string, error)
+}
+
+// PickSource lists the aliases to offer and releases the database while the
+// user chooses.
+type PickSource interface {
+	AliasLister
+	DBReleaser
+}
+
+type AliasPicker interface {
+	PickSource
+	AliasSwitcher
+}
+
+func NewPickCmd(dbService AliasPicker, branches BranchReader, picker ProjectPicker) *cobra.Command {
+	var finder string
+
+	pickCmd := &cobra.Command{
+		Use:   "pick",
+		Short: "Choose a project with a fuzzy finder and switch to it",
+		Long: `Choose a project with a fuzzy finder and switch to it.
+
+The stored aliases are streamed to the finder as "alias<TAB>path" lines and
+the chosen line is read back. fzf, sk and peco are supported; any other
+command that reads lines on stdin and prints the chosen one works as well.
+Use --finder builtin for the built-in picker.
+
+The default finder is pick.finder in config.yaml and preview commands are set
+per finder under pick.preview, where {2} stands for the project path. The
+environment variables GS_PICK_FINDER and GS_PICK_PREVIEW_<FINDER> override
+them. If the finder is not installed, the built-in picker is used instead.`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{switchAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			alias, err := pickAlias(cmd, dbService, branches, picker, finder)
+			if err != nil {
+				return err
+			}
+			return switchToAlias(cmd, dbService, alias, true)
+		},
+	}
+
+	pickCmd.Flags().StringVar(&finder, "finder", "", "fuzzy finder to use: fzf, sk, peco, another command or builtin")
+	return pickCmd
+}
+
+// pickAlias offers every stored alias in finder, most recently used first,
+// and returns the alias that was chosen. An empty finder means the configured
+// one. If the finder is missing, the built-in picker is used instead.
+func pickAlias(cmd *cobra.Command, source PickSource, branches BranchReader, picker ProjectPicker, finder string) (string, error) {
+	entries, err := source.List()
+	if err != nil {
+		return "", errors.New("failed to list aliases")
+	}
+	if len(entries) == 0 {
+		return "", errors.New("no aliases stored yet, add one with 'gs add'")
+	}
+
+	sortEntries(entries, "last-used")
+	items := make([]libs.PickerItem, 0, len(entries))
+	for _, entry := range entries {
+		// A missing branch only leaves the column empty
+		branch, _ := branches.GitBranch(entry.Path)
+		items = append(items, libs.PickerItem{
+			Alias:    entry.Alias,
+			Path:     entry.Path,
+			Branch:   branch,
+			LastUsed: entry.LastUsed,
+			UseCount: entry.UseCount,
+		})
+	}
+
+	// Other gs calls must not wait for the user to choose
+	if err := source.Release(); err != nil {
+		return "", errors.New("failed to close the database")
+	}
+
+	item, err := picker.PickWith(finder, items)
+	if errors.Is(err, libs.ErrFinderNotFound) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%v, using the built-in picker\n", err)
+		item, err = picker.Pick(items)
+	}
+	if errors.Is(err, libs.ErrPickerCancelled) {
+		return "", &ExitError{Code: ExitCodeCancelled, Err: err}
+	}
+	if errors.Is(err, libs.ErrInvalidSelection) {
+		return "", err
+	}
+	if err != nil {
+		return "", errors.New("failed to run the project picker")
+	}
+	return item.Alias, nil
+}
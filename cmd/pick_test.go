@@ -0,0 +1,121 @@
+package cmd_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPickCmd(t *testing.T) {
+	entries := []libs.Entry{{Alias: "api", Record: libs.Record{Path: "/src/api"}}}
+	items := []libs.PickerItem{{Alias: "api", Path: "/src/api", Branch: "main"}}
+
+	tests := []struct {
+		name             string
+		args             []string
+		setupMock        func(*mocks.MockAliasPicker, *mocks.MockProjectPicker)
+		expectedOutput   string
+		expectedStderr   string
+		expectedError    string
+		expectedExitCode int
+	}{
+		{
+			name: "successful pick with the configured finder",
+			args: []string{},
+			setupMock: func(db *mocks.MockAliasPicker, picker *mocks.MockProjectPicker) {
+				picker.EXPECT().PickWith("", items).Return(items[0], nil)
+				db.EXPECT().Visit("api").Return("/src/api", nil)
+			},
+			expectedOutput: "/src/api\n",
+		},
+		{
+			name: "successful pick with a given finder",
+			args: []string{"--finder", "fzf"},
+			setupMock: func(db *mocks.MockAliasPicker, picker *mocks.MockProjectPicker) {
+				picker.EXPECT().PickWith("fzf", items).Return(items[0], nil)
+				db.EXPECT().Visit("api").Return("/src/api", nil)
+			},
+			expectedOutput: "/src/api\n",
+		},
+		{
+			name: "missing finder falls back to the built-in picker",
+			args: []string{"--finder", "sk"},
+			setupMock: func(db *mocks.MockAliasPicker, picker *mocks.MockProjectPicker) {
+				gomock.InOrder(
+					picker.EXPECT().PickWith("sk", items).Return(libs.PickerItem{}, fmt.Errorf("%w: sk", libs.ErrFinderNotFound)),
+					picker.EXPECT().Pick(items).Return(items[0], nil),
+				)
+				db.EXPECT().Visit("api").Return("/src/api", nil)
+			},
+			expectedOutput: "/src/api\n",
+			expectedStderr: "finder not found in PATH: sk, using the built-in picker\n",
+		},
+		{
+			name: "failed due to cancelled finder",
+			args: []string{"--finder", "fzf"},
+			setupMock: func(db *mocks.MockAliasPicker, picker *mocks.MockProjectPicker) {
+				picker.EXPECT().PickWith("fzf", items).Return(libs.PickerItem{}, libs.ErrPickerCancelled)
+			},
+			expectedError:    "no project selected",
+			expectedExitCode: cmd.ExitCodeCancelled,
+		},
+		{
+			name: "failed due to finder error",
+			args: []string{"--finder", "fzf"},
+			setupMock: func(db *mocks.MockAliasPicker, picker *mocks.MockProjectPicker) {
+				picker.EXPECT().PickWith("fzf", items).Return(libs.PickerItem{}, assert.AnError)
+			},
+			expectedError: "failed to run the project picker",
+		},
+		{
+			name:          "failed due to too many args",
+			args:          []string{"api"},
+			expectedError: `unknown command "api" for "pick"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDBService := mocks.NewMockAliasPicker(ctrl)
+			mockBranches := mocks.NewMockBranchReader(ctrl)
+			mockPicker := mocks.NewMockProjectPicker(ctrl)
+			if tt.setupMock != nil {
+				mockDBService.EXPECT().List().Return(entries, nil)
+				mockDBService.EXPECT().Release().Return(nil)
+				mockBranches.EXPECT().GitBranch("/src/api").Return("main", nil)
+				tt.setupMock(mockDBService, mockPicker)
+			}
+			pickCmd := cmd.NewPickCmd(mockDBService, mockBranches, mockPicker)
+
+			var out, stderr bytes.Buffer
+			pickCmd.SetOut(&out)
+			pickCmd.SetErr(&stderr)
+			pickCmd.SetArgs(tt.args)
+			err := pickCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+				assert.Equal(t, tt.expectedStderr, stderr.String())
+				return
+			}
+
+			assert.EqualError(t, err, tt.expectedError)
+			if tt.expectedExitCode != 0 {
+				var exitErr *cmd.ExitError
+				assert.True(t, errors.As(err, &exitErr))
+				assert.Equal(t, tt.expectedExitCode, exitErr.Code)
+			}
+		})
+	}
+}
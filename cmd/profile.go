@@ -0,0 +1,220 @@
+//go:generate mockgen -destination=../mocks/cmd/profile.go -package=mocks -source=profile.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"gs/libs"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+type ProfileChecker interface {
+	CheckProfile() error
+}
+
+type ProfileManager interface {
+	DBReleaser
+	Profile() string
+	ProfileSelection() libs.ProfileSelection
+	Profiles() ([]libs.ProfileInfo, error)
+	CreateProfile(name string) error
+	DeleteProfile(name string) error
+	CopyProfile(from, to string) error
+	RenameProfile(oldName, newName string) error
+}
+
+func NewProfileCmd(manager ProfileManager) *cobra.Command {
+	profileCmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Keep separate sets of projects in profiles",
+		Long: `Keep separate sets of projects, for example for work, personal and client
+projects, in profiles stored side by side in the database.
+
+Every command works on the aliases of one profile: the one given with
+--profile, else $GS_PROFILE, else the profile_rules entry for the deepest
+directory containing the current one, else the default_profile config key,
+else the "default" profile, which holds the aliases gs stored before it had
+profiles. In a shell with the 'gs init' wrapper, set GS_PROFILE to switch
+between the projects of another profile, or add rules to switch by directory:
+
+  gs config set profile_rules "~/work=work,~/oss=oss"
+
+Run 'gs profile which' to see which profile applies and why.`,
+		Args: cobra.NoArgs,
+	}
+
+	profileCmd.AddCommand(newProfileListCmd(manager))
+	profileCmd.AddCommand(newProfileWhichCmd(manager))
+	profileCmd.AddCommand(newProfileCreateCmd(manager))
+	profileCmd.AddCommand(newProfileDeleteCmd(manager))
+	profileCmd.AddCommand(newProfileCopyCmd(manager))
+	profileCmd.AddCommand(newProfileRenameCmd(manager))
+	return profileCmd
+}
+
+func newProfileListCmd(manager ProfileManager) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the profiles, marking the one in use",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profiles, err := manager.Profiles()
+			if err != nil {
+				return errors.New("failed to list profiles")
+			}
+
+			current := manager.Profile()
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "\tPROFILE\tALIASES")
+			for _, profile := range profiles {
+				marker := ""
+				if profile.Name == current {
+					marker = "*"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%d\n", marker, profile.Name, profile.Aliases)
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func newProfileWhichCmd(manager ProfileManager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "which",
+		Short: "Show the profile in use and what selected it",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			selection := manager.ProfileSelection()
+			var reason string
+			switch {
+			case selection.Override != "":
+				reason = "set by " + selection.Override
+			case selection.Rule != nil:
+				reason = fmt.Sprintf("profile_rules entry %s matches %s", selection.Rule, selection.Dir)
+			default:
+				reason = "default_profile, no profile_rules entry matches " + selection.Dir
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s (%s)\n", selection.Profile, reason)
+			return nil
+		},
+	}
+}
+
+func newProfileCreateCmd(manager ProfileManager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create an empty profile",
+		Long: `Create an empty profile. Profile names start with a letter or digit and
+contain only letters, digits, '.', '_' and '-'.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := manager.CreateProfile(args[0]); err != nil {
+				return profileError(err, fmt.Sprintf("failed to create profile %s", args[0]))
+			}
+			return nil
+		},
+	}
+}
+
+func newProfileDeleteCmd(manager ProfileManager) *cobra.Command {
+	var yes bool
+
+	deleteCmd := &cobra.Command{
+		Use:     "delete <name>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a profile and its aliases",
+		Long: `Delete a profile together with its aliases and visit history, after asking
+for confirmation unless --yes is given. The default profile cannot be deleted.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if name == libs.DefaultProfile {
+				return libs.ErrDefaultProfile
+			}
+			if !yes {
+				ok, err := confirm(cmd, manager, fmt.Sprintf("Delete profile %s and its aliases?", name))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("aborted")
+				}
+			}
+			if err := manager.DeleteProfile(name); err != nil {
+				return profileError(err, fmt.Sprintf("failed to delete profile %s", name))
+			}
+			return nil
+		},
+	}
+
+	deleteCmd.Flags().BoolVarP(&yes, "yes", "y", false, "do not ask for confirmation")
+	return deleteCmd
+}
+
+func newProfileCopyCmd(manager ProfileManager) *cobra.Command {
+	return &cobra.Command{
+		Use:     "copy <from> <to>",
+		Aliases: []string{"cp"},
+		Short:   "Create a profile with a copy of the aliases of another",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := manager.CopyProfile(args[0], args[1]); err != nil {
+				return profileError(err, fmt.Sprintf("failed to copy profile %s to %s", args[0], args[1]))
+			}
+			return nil
+		},
+	}
+}
+
+func newProfileRenameCmd(manager ProfileManager) *cobra.Command {
+	return &cobra.Command{
+		Use:     "rename <old> <new>",
+		Aliases: []string{"mv"},
+		Short:   "Rename a profile",
+		Long: `Rename a profile, keeping its aliases and visit history. The default profile
+cannot be renamed; copy it instead. A default_profile setting naming the old
+profile is not updated.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := manager.RenameProfile(args[0], args[1]); err != nil {
+				return profileError(err, fmt.Sprintf("failed to rename profile %s to %s", args[0], args[1]))
+			}
+			return nil
+		},
+	}
+}
+
+// profileError keeps the typed profile errors from libs visible and replaces
+// any other error by msg.
+func profileError(err error, msg string) error {
+	for _, target := range []error{libs.ErrProfileNotFound, libs.ErrProfileExists, libs.ErrInvalidProfile, libs.ErrDefaultProfile} {
+		if errors.Is(err, target) {
+			return err
+		}
+	}
+	return errors.New(msg)
+}
+
+// profileFreeCommands do not touch the aliases, so they run even when the
+// selected profile does not exist, for example to create it.
+var profileFreeCommands = append([]string{"db", "profile"}, dbFreeCommands...)
+
+// checkProfile makes sure the selected profile exists before a command that
+// works on aliases runs.
+func checkProfile(cmd *cobra.Command, checker ProfileChecker) error {
+	if contains(profileFreeCommands, topLevelName(cmd)) {
+		return nil
+	}
+
+	if err := checker.CheckProfile(); err != nil {
+		cmd.SilenceUsage = true
+		if errors.Is(err, libs.ErrProfileNotFound) {
+			return fmt.Errorf("%w, create it with 'gs profile create'", err)
+		}
+		return errors.New("failed to open profile")
+	}
+	return nil
+}
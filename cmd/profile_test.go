@@ -0,0 +1,274 @@
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProfileCmd(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		stdin          string
+		setupMock      func(*mocks.MockProfileManager)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name: "successful list marking the current profile",
+			args: []string{"list"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().Profiles().Return([]libs.ProfileInfo{{Name: "default", Aliases: 3}, {Name: "work", Aliases: 12}}, nil)
+				m.EXPECT().Profile().Return("work")
+			},
+			expectedOutput: "   PROFILE  ALIASES\n" +
+				"   default  3\n" +
+				"*  work     12\n",
+		},
+		{
+			name: "failed list due to database error",
+			args: []string{"ls"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().Profiles().Return(nil, assert.AnError)
+			},
+			expectedError: "failed to list profiles",
+		},
+		{
+			name: "successful which with matching rule",
+			args: []string{"which"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().ProfileSelection().Return(libs.ProfileSelection{
+					Profile: "work",
+					Rule:    &libs.ProfileRule{Dir: "/home/me/work", Profile: "work"},
+					Dir:     "/home/me/work/api",
+				})
+			},
+			expectedOutput: "work (profile_rules entry /home/me/work=work matches /home/me/work/api)\n",
+		},
+		{
+			name: "successful which with override",
+			args: []string{"which"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().ProfileSelection().Return(libs.ProfileSelection{Profile: "oss", Override: "$GS_PROFILE", Dir: "/home/me/work/api"})
+			},
+			expectedOutput: "oss (set by $GS_PROFILE)\n",
+		},
+		{
+			name: "successful which without matching rule",
+			args: []string{"which"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().ProfileSelection().Return(libs.ProfileSelection{Profile: "default", Dir: "/tmp"})
+			},
+			expectedOutput: "default (default_profile, no profile_rules entry matches /tmp)\n",
+		},
+		{
+			name: "successful create",
+			args: []string{"create", "client-a"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().CreateProfile("client-a").Return(nil)
+			},
+		},
+		{
+			name: "failed create of existing profile",
+			args: []string{"create", "work"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().CreateProfile("work").Return(fmt.Errorf("%w: work", libs.ErrProfileExists))
+			},
+			expectedError: "profile already exists: work",
+		},
+		{
+			name: "failed create due to invalid name",
+			args: []string{"create", "a/b"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().CreateProfile("a/b").Return(fmt.Errorf("%w: %q", libs.ErrInvalidProfile, "a/b"))
+			},
+			expectedError: `invalid profile name: "a/b"`,
+		},
+		{
+			name:  "successful delete after confirmation",
+			args:  []string{"delete", "work"},
+			stdin: "y\n",
+			setupMock: func(m *mocks.MockProfileManager) {
+				// The database is released while the question is open
+				release := m.EXPECT().Release().Return(nil)
+				m.EXPECT().DeleteProfile("work").Return(nil).After(release)
+			},
+		},
+		{
+			name: "successful delete with --yes",
+			args: []string{"rm", "work", "-y"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().DeleteProfile("work").Return(nil)
+			},
+		},
+		{
+			name:  "aborted delete",
+			args:  []string{"delete", "work"},
+			stdin: "n\n",
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().Release().Return(nil)
+			},
+			expectedError: "aborted",
+		},
+		{
+			name: "failed delete due to database that cannot be closed",
+			args: []string{"delete", "work"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().Release().Return(assert.AnError)
+			},
+			expectedError: "failed to close the database",
+		},
+		{
+			name:          "failed delete of the default profile",
+			args:          []string{"delete", "default", "--yes"},
+			expectedError: libs.ErrDefaultProfile.Error(),
+		},
+		{
+			name: "failed delete of missing profile",
+			args: []string{"delete", "old", "--yes"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().DeleteProfile("old").Return(fmt.Errorf("%w: old", libs.ErrProfileNotFound))
+			},
+			expectedError: "profile not found: old",
+		},
+		{
+			name: "successful copy",
+			args: []string{"copy", "default", "work"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().CopyProfile("default", "work").Return(nil)
+			},
+		},
+		{
+			name: "failed copy due to database error",
+			args: []string{"cp", "default", "work"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().CopyProfile("default", "work").Return(assert.AnError)
+			},
+			expectedError: "failed to copy profile default to work",
+		},
+		{
+			name: "successful rename",
+			args: []string{"rename", "client-a", "client-b"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().RenameProfile("client-a", "client-b").Return(nil)
+			},
+		},
+		{
+			name: "failed rename of the default profile",
+			args: []string{"mv", "default", "home"},
+			setupMock: func(m *mocks.MockProfileManager) {
+				m.EXPECT().RenameProfile("default", "home").Return(libs.ErrDefaultProfile)
+			},
+			expectedError: libs.ErrDefaultProfile.Error(),
+		},
+		{
+			name:          "failed rename without new name",
+			args:          []string{"rename", "work"},
+			expectedError: "accepts 2 arg(s), received 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockManager := mocks.NewMockProfileManager(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(mockManager)
+			}
+			profileCmd := cmd.NewProfileCmd(mockManager)
+
+			var out bytes.Buffer
+			profileCmd.SetOut(&out)
+			profileCmd.SetErr(&bytes.Buffer{})
+			profileCmd.SetIn(strings.NewReader(tt.stdin))
+			profileCmd.SetArgs(tt.args)
+			err := profileCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestRootCmdProfileCheck(t *testing.T) {
+	missing := fmt.Errorf("%w: work", libs.ErrProfileNotFound)
+
+	tests := []struct {
+		name          string
+		args          []string
+		setupMock     func(*mocks.MockRootDBService)
+		expectedError string
+	}{
+		{
+			name: "switching fails for a missing profile",
+			args: []string{"api"},
+			setupMock: func(db *mocks.MockRootDBService) {
+				db.EXPECT().CheckProfile().Return(missing)
+			},
+			expectedError: "profile not found: work, create it with 'gs profile create'",
+		},
+		{
+			name: "subcommands fail for a missing profile",
+			args: []string{"list"},
+			setupMock: func(db *mocks.MockRootDBService) {
+				db.EXPECT().CheckProfile().Return(missing)
+			},
+			expectedError: "profile not found: work, create it with 'gs profile create'",
+		},
+		{
+			name: "database errors are reported",
+			args: []string{"top"},
+			setupMock: func(db *mocks.MockRootDBService) {
+				db.EXPECT().CheckProfile().Return(assert.AnError)
+			},
+			expectedError: "failed to open profile",
+		},
+		{
+			name: "profile commands run without the profile",
+			args: []string{"profile", "create", "work"},
+			setupMock: func(db *mocks.MockRootDBService) {
+				db.EXPECT().CreateProfile("work").Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDBService := mocks.NewMockRootDBService(ctrl)
+			tt.setupMock(mockDBService)
+			mockMigrator := mocks.NewMockMigrator(ctrl)
+			mockMigrator.EXPECT().Migrate().Return(libs.MigrationResult{}, nil)
+			rootCmd := cmd.NewRootCommand(mockDBService, mocks.NewMockRootFileService(ctrl), mockMigrator, mocks.NewMockProjectPicker(ctrl), mocks.NewMockProjectRunner(ctrl), mocks.NewMockStatusReader(ctrl), mocks.NewMockConfigManager(ctrl))
+
+			var stderr bytes.Buffer
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&stderr)
+			rootCmd.SetArgs(tt.args)
+			err := rootCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.NotContains(t, stderr.String(), "Usage:")
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// confirm asks a yes/no question on stderr and reads the answer from stdin,
+// keeping stdout free for output that shell wrappers consume. It releases the
+// database first, so other gs calls work while the question is open.
+func confirm(cmd *cobra.Command, db DBReleaser, question string) (bool, error) {
+	if err := db.Release(); err != nil {
+		return false, errors.New("failed to close the database")
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
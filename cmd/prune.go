@@ -0,0 +1,91 @@
+//go:generate mockgen -destination=../mocks/cmd/prune.go -package=mocks -source=prune.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type AliasPruner interface {
+	AliasLister
+	DBReleaser
+	Remove(aliases ...string) error
+}
+
+func NewPruneCmd(pruner AliasPruner, checker HealthChecker) *cobra.Command {
+	var dryRun, yes, nonGit bool
+
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove aliases whose path no longer exists",
+		Long: `Remove every alias whose path no longer exists, as reported by 'gs doctor'.
+With --non-git, aliases whose path exists but is not inside a Git repository
+are removed too, except those added with --allow-non-git. Entries that cannot
+be inspected are never removed.
+
+The aliases are removed in a single transaction after asking for
+confirmation, unless --yes is given. With --dry-run nothing is removed and gs
+exits with code 4 if there is anything to prune, like 'gs doctor'.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			checked, err := checkEntries(pruner, checker)
+			if err != nil {
+				return err
+			}
+
+			var stale []entryHealth
+			for _, c := range checked {
+				if c.health == healthMissing || (nonGit && c.health == healthNotARepo) {
+					stale = append(stale, c)
+				}
+			}
+			out := cmd.OutOrStdout()
+			if len(stale) == 0 {
+				fmt.Fprintln(out, "nothing to prune")
+				return nil
+			}
+
+			aliases := make([]string, 0, len(stale))
+			for _, c := range stale {
+				aliases = append(aliases, c.entry.Alias)
+			}
+
+			if dryRun {
+				for _, c := range stale {
+					fmt.Fprintf(out, "would remove %s (%s): %s\n", c.entry.Alias, c.entry.Path, c.health)
+				}
+				return &ExitError{
+					Code: ExitCodeUnhealthy,
+					Err:  fmt.Errorf("%d of %d entries would be pruned", len(stale), len(checked)),
+				}
+			}
+
+			if !yes {
+				ok, err := confirm(cmd, pruner, fmt.Sprintf("Remove %d stale aliases (%s)?", len(aliases), strings.Join(aliases, ", ")))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("aborted")
+				}
+			}
+
+			if err := pruner.Remove(aliases...); err != nil {
+				return aliasError(err, fmt.Sprintf("failed to remove %s", strings.Join(aliases, ", ")))
+			}
+			for _, c := range stale {
+				fmt.Fprintf(out, "removed %s (%s)\n", c.entry.Alias, c.entry.Path)
+			}
+			return nil
+		},
+	}
+
+	pruneCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be removed without changing anything")
+	pruneCmd.Flags().BoolVarP(&yes, "yes", "y", false, "do not ask for confirmation")
+	pruneCmd.Flags().BoolVar(&nonGit, "non-git", false, "also remove aliases whose path is not in a Git repository")
+	return pruneCmd
+}
@@ -0,0 +1,129 @@
+package cmd_test
+
+import (
+	"bytes"
+	"errors"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPruneCmd(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		stdin            string
+		setupMock        func(*mocks.MockAliasPruner, *mocks.MockHealthChecker)
+		expectedOutput   string
+		expectedError    string
+		expectedExitCode int
+	}{
+		{
+			name:  "successful prune after confirmation",
+			stdin: "y\n",
+			setupMock: func(pruner *mocks.MockAliasPruner, checker *mocks.MockHealthChecker) {
+				pruner.EXPECT().List().Return(healthEntries, nil)
+				expectHealth(checker)
+				release := pruner.EXPECT().Release().Return(nil)
+				pruner.EXPECT().Remove("billing").Return(nil).After(release)
+			},
+			expectedOutput: "removed billing (/src/billing)\n",
+		},
+		{
+			name: "successful prune of non-git entries with --yes",
+			args: []string{"--non-git", "--yes"},
+			setupMock: func(pruner *mocks.MockAliasPruner, checker *mocks.MockHealthChecker) {
+				pruner.EXPECT().List().Return(healthEntries, nil)
+				expectHealth(checker)
+				pruner.EXPECT().Remove("billing", "notes").Return(nil)
+			},
+			expectedOutput: "removed billing (/src/billing)\nremoved notes (/src/notes)\n",
+		},
+		{
+			name: "successful prune with nothing to prune",
+			setupMock: func(pruner *mocks.MockAliasPruner, checker *mocks.MockHealthChecker) {
+				pruner.EXPECT().List().Return(healthEntries[:1], nil)
+				checker.EXPECT().CheckIfPathExists("/src/api-gw").Return(true, nil)
+				checker.EXPECT().DetectGitRepository("/src/api-gw").Return(libs.GitRepository{Root: "/src/api-gw"}, nil)
+			},
+			expectedOutput: "nothing to prune\n",
+		},
+		{
+			name: "directories added with --allow-non-git are kept",
+			args: []string{"--non-git", "--yes"},
+			setupMock: func(pruner *mocks.MockAliasPruner, checker *mocks.MockHealthChecker) {
+				pruner.EXPECT().List().Return([]libs.Entry{scratch}, nil)
+				checker.EXPECT().CheckIfPathExists("/tmp/scratch").Return(true, nil)
+			},
+			expectedOutput: "nothing to prune\n",
+		},
+		{
+			name: "dry run with stale entries",
+			args: []string{"--dry-run", "--non-git"},
+			setupMock: func(pruner *mocks.MockAliasPruner, checker *mocks.MockHealthChecker) {
+				pruner.EXPECT().List().Return(healthEntries, nil)
+				expectHealth(checker)
+			},
+			expectedOutput: "would remove billing (/src/billing): missing\n" +
+				"would remove notes (/src/notes): not-a-repo\n",
+			expectedError:    "2 of 4 entries would be pruned",
+			expectedExitCode: cmd.ExitCodeUnhealthy,
+		},
+		{
+			name:  "aborted prune when not confirmed",
+			stdin: "n\n",
+			setupMock: func(pruner *mocks.MockAliasPruner, checker *mocks.MockHealthChecker) {
+				pruner.EXPECT().List().Return(healthEntries, nil)
+				expectHealth(checker)
+				pruner.EXPECT().Release().Return(nil)
+			},
+			expectedError: "aborted",
+		},
+		{
+			name: "failed prune due to database error",
+			args: []string{"-y"},
+			setupMock: func(pruner *mocks.MockAliasPruner, checker *mocks.MockHealthChecker) {
+				pruner.EXPECT().List().Return(healthEntries, nil)
+				expectHealth(checker)
+				pruner.EXPECT().Remove("billing").Return(errors.New("database error"))
+			},
+			expectedError: "failed to remove billing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPruner := mocks.NewMockAliasPruner(ctrl)
+			mockChecker := mocks.NewMockHealthChecker(ctrl)
+			tt.setupMock(mockPruner, mockChecker)
+			pruneCmd := cmd.NewPruneCmd(mockPruner, mockChecker)
+
+			var out bytes.Buffer
+			pruneCmd.SetOut(&out)
+			pruneCmd.SetErr(&bytes.Buffer{})
+			pruneCmd.SetIn(strings.NewReader(tt.stdin))
+			pruneCmd.SetArgs(tt.args)
+			err := pruneCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+			if tt.expectedExitCode != 0 {
+				var exitErr *cmd.ExitError
+				assert.True(t, errors.As(err, &exitErr))
+				assert.Equal(t, tt.expectedExitCode, exitErr.Code)
+			}
+			assert.Equal(t, tt.expectedOutput, out.String())
+		})
+	}
+}
@@ -0,0 +1,256 @@
+//go:generate mockgen -destination=../mocks/cmd/relocate.go -package=mocks -source=relocate.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"gs/libs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type RepoFinder interface {
+	CheckIfPathExists(path string) (bool, error)
+	NormalizePath(path string, resolveSymlinks bool) (string, error)
+	FindRepositories(root string, maxDepth int) ([]string, error)
+	RepoIdentity(path string) (libs.RepoIdentity, error)
+}
+
+type AliasRelocator interface {
+	AliasLister
+	DBReleaser
+	Relocate(paths map[string]string) error
+}
+
+// relocation is a proposed new path for an alias whose path is gone.
+type relocation struct {
+	alias     string
+	oldPath   string
+	newPath   string
+	matchedBy string
+}
+
+// foundRepo is a repository found while searching, with its identity.
+type foundRepo struct {
+	path     string
+	identity libs.RepoIdentity
+}
+
+func NewRelocateCmd(relocator AliasRelocator, finder RepoFinder) *cobra.Command {
+	var searchRoots []string
+	var depth int
+	var dryRun, yes bool
+
+	relocateCmd := &cobra.Command{
+		Use:   "relocate --search <dir>...",
+		Short: "Find moved repositories and update their aliases",
+		Long: `Find the repositories of aliases whose path no longer exists and point the
+aliases at their new location.
+
+The directories given with --search are scanned for Git repositories, which
+are matched to the missing ones by remote URL and, failing that, by the hash
+of their first commit. When several repositories match, one with the same
+directory name as before is preferred; otherwise the alias is left alone.
+Aliases that would all move to the same repository are left alone as well.
+gs records the remote URL and first commit when a project is added. For
+aliases added before gs did so, run 'gs add <alias> <path>' again with the
+stored alias and path to record them.
+
+The proposed changes are shown and applied in a single transaction after
+asking for confirmation, unless --yes is given.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			entries, err := relocator.List()
+			if err != nil {
+				return errors.New("failed to list aliases")
+			}
+
+			registered := map[string]bool{}
+			var stale []libs.Entry
+			for _, entry := range entries {
+				registered[entry.Path] = true
+				exists, err := finder.CheckIfPathExists(entry.Path)
+				if err != nil || exists {
+					continue
+				}
+				if entry.Identity().IsZero() {
+					fmt.Fprintf(cmd.ErrOrStderr(), "cannot relocate %s: no remote or first commit stored, use 'gs mv %s <path>'\n", entry.Alias, entry.Alias)
+					continue
+				}
+				stale = append(stale, entry)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(stale) == 0 {
+				fmt.Fprintln(out, "nothing to relocate")
+				return nil
+			}
+
+			repos, err := searchRepositories(finder, searchRoots, depth, registered)
+			if err != nil {
+				return err
+			}
+
+			var relocations []relocation
+			for _, entry := range stale {
+				candidates, matchedBy := matchRepositories(entry, repos)
+				switch len(candidates) {
+				case 0:
+					fmt.Fprintf(cmd.ErrOrStderr(), "no match found for %s (%s)\n", entry.Alias, entry.Path)
+				case 1:
+					relocations = append(relocations, relocation{
+						alias:     entry.Alias,
+						oldPath:   entry.Path,
+						newPath:   candidates[0].path,
+						matchedBy: matchedBy,
+					})
+				default:
+					paths := make([]string, 0, len(candidates))
+					for _, c := range candidates {
+						paths = append(paths, c.path)
+					}
+					fmt.Fprintf(cmd.ErrOrStderr(), "several matches for %s, use 'gs mv %s <path>': %s\n",
+						entry.Alias, entry.Alias, strings.Join(paths, ", "))
+				}
+			}
+			relocations = dropConflicts(cmd, relocations)
+			if len(relocations) == 0 {
+				return errors.New("no moved repositories found")
+			}
+
+			verb := "move"
+			if dryRun {
+				verb = "would move"
+			}
+			for _, r := range relocations {
+				fmt.Fprintf(out, "%s %s from %s to %s (same %s)\n", verb, r.alias, r.oldPath, r.newPath, r.matchedBy)
+			}
+			if dryRun {
+				return nil
+			}
+
+			if !yes {
+				ok, err := confirm(cmd, relocator, fmt.Sprintf("Update %d aliases?", len(relocations)))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("aborted")
+				}
+			}
+
+			paths := make(map[string]string, len(relocations))
+			aliases := make([]string, 0, len(relocations))
+			for _, r := range relocations {
+				paths[r.alias] = r.newPath
+				aliases = append(aliases, r.alias)
+			}
+			if err := relocator.Relocate(paths); err != nil {
+				return aliasError(err, fmt.Sprintf("failed to update %s", strings.Join(aliases, ", ")))
+			}
+			return nil
+		},
+	}
+
+	relocateCmd.Flags().StringArrayVar(&searchRoots, "search", nil, "directory to search for moved repositories (repeatable)")
+	relocateCmd.Flags().IntVar(&depth, "depth", 4, "how many directory levels to search below each directory, 0 for no limit")
+	relocateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the changes without applying them")
+	relocateCmd.Flags().BoolVarP(&yes, "yes", "y", false, "do not ask for confirmation")
+	_ = relocateCmd.MarkFlagRequired("search")
+	return relocateCmd
+}
+
+// searchRepositories finds the repositories under roots that are not
+// registered yet and reads their identities. Repositories whose identity
+// cannot be read are left out.
+func searchRepositories(finder RepoFinder, roots []string, depth int, registered map[string]bool) ([]foundRepo, error) {
+	seen := map[string]bool{}
+	var repos []foundRepo
+	for _, root := range roots {
+		root, err := normalizePath(root, finder, true)
+		if err != nil {
+			return nil, err
+		}
+		paths, err := finder.FindRepositories(root, depth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s: %w", root, err)
+		}
+		for _, path := range paths {
+			if seen[path] || registered[path] {
+				continue
+			}
+			seen[path] = true
+			identity, err := finder.RepoIdentity(path)
+			if err != nil || identity.IsZero() {
+				continue
+			}
+			repos = append(repos, foundRepo{path: path, identity: identity})
+		}
+	}
+	return repos, nil
+}
+
+// dropConflicts leaves out relocations that would point several aliases at the
+// same repository, since at most one of them can have moved there.
+func dropConflicts(cmd *cobra.Command, relocations []relocation) []relocation {
+	aliases := map[string][]string{}
+	for _, r := range relocations {
+		aliases[r.newPath] = append(aliases[r.newPath], r.alias)
+	}
+
+	kept := relocations[:0]
+	reported := map[string]bool{}
+	for _, r := range relocations {
+		conflicting := aliases[r.newPath]
+		if len(conflicting) == 1 {
+			kept = append(kept, r)
+			continue
+		}
+		if !reported[r.newPath] {
+			reported[r.newPath] = true
+			fmt.Fprintf(cmd.ErrOrStderr(), "several aliases match %s, use 'gs mv <alias> %s': %s\n",
+				r.newPath, r.newPath, strings.Join(conflicting, ", "))
+		}
+	}
+	return kept
+}
+
+// matchRepositories returns the repositories that entry has most likely moved
+// to and what they were matched by.
+func matchRepositories(entry libs.Entry, repos []foundRepo) ([]foundRepo, string) {
+	var candidates []foundRepo
+	matchedBy := "remote"
+	if remote := libs.NormalizeRemoteURL(entry.Remote); remote != "" {
+		for _, repo := range repos {
+			if libs.NormalizeRemoteURL(repo.identity.Remote) == remote {
+				candidates = append(candidates, repo)
+			}
+		}
+	}
+	if len(candidates) == 0 && entry.RootCommit != "" {
+		matchedBy = "first commit"
+		for _, repo := range repos {
+			if repo.identity.RootCommit == entry.RootCommit {
+				candidates = append(candidates, repo)
+			}
+		}
+	}
+
+	if len(candidates) > 1 {
+		var sameName []foundRepo
+		for _, c := range candidates {
+			if filepath.Base(c.path) == filepath.Base(entry.Path) {
+				sameName = append(sameName, c)
+			}
+		}
+		if len(sameName) == 1 {
+			return sameName, matchedBy
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].path < candidates[j].path })
+	return candidates, matchedBy
+}
@@ -0,0 +1,187 @@
+package cmd_test
+
+import (
+	"bytes"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRelocateCmd(t *testing.T) {
+	apiGw := libs.Entry{Alias: "api-gw", Record: libs.Record{Path: "/src/api-gw", Remote: "git@example.com:org/api-gw.git", RootCommit: "aaa"}}
+	billing := libs.Entry{Alias: "billing", Record: libs.Record{Path: "/src/billing", RootCommit: "bbb"}}
+	docs := libs.Entry{Alias: "docs", Record: libs.Record{Path: "/src/docs", Remote: "https://example.com/org/docs"}}
+	legacy := libs.Entry{Alias: "legacy", Record: libs.Record{Path: "/src/legacy"}}
+	healthy := libs.Entry{Alias: "healthy", Record: libs.Record{Path: "/work/healthy"}}
+
+	// expectSearch makes every entry but healthy missing and finds the given
+	// repositories under /work, along with the registered healthy one.
+	expectSearch := func(finder *mocks.MockRepoFinder, entries []libs.Entry, found map[string]libs.RepoIdentity) {
+		for _, entry := range entries {
+			finder.EXPECT().CheckIfPathExists(entry.Path).Return(entry.Alias == "healthy", nil)
+		}
+		finder.EXPECT().NormalizePath("~/work", true).Return("/work", nil)
+		paths := []string{}
+		for _, entry := range entries {
+			if entry.Alias == "healthy" {
+				paths = append(paths, entry.Path)
+			}
+		}
+		for path := range found {
+			paths = append(paths, path)
+		}
+		finder.EXPECT().FindRepositories("/work", 4).Return(paths, nil)
+		for path, identity := range found {
+			finder.EXPECT().RepoIdentity(path).Return(identity, nil)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		stdin          string
+		setupMock      func(*mocks.MockAliasRelocator, *mocks.MockRepoFinder)
+		expectedOutput string
+		expectedStderr string
+		expectedError  string
+	}{
+		{
+			name:  "successful relocate by remote and first commit after confirmation",
+			args:  []string{"--search", "~/work"},
+			stdin: "y\n",
+			setupMock: func(relocator *mocks.MockAliasRelocator, finder *mocks.MockRepoFinder) {
+				entries := []libs.Entry{apiGw, billing, healthy}
+				relocator.EXPECT().List().Return(entries, nil)
+				expectSearch(finder, entries, map[string]libs.RepoIdentity{
+					"/work/org/gateway": {Remote: "https://example.com/org/api-gw", RootCommit: "aaa"},
+					"/work/billing":     {RootCommit: "bbb"},
+					"/work/other":       {Remote: "https://example.com/org/other", RootCommit: "ccc"},
+				})
+				release := relocator.EXPECT().Release().Return(nil)
+				relocator.EXPECT().Relocate(map[string]string{
+					"api-gw":  "/work/org/gateway",
+					"billing": "/work/billing",
+				}).Return(nil).After(release)
+			},
+			expectedOutput: "move api-gw from /src/api-gw to /work/org/gateway (same remote)\n" +
+				"move billing from /src/billing to /work/billing (same first commit)\n",
+		},
+		{
+			name: "dry run prefers the same directory name among several matches",
+			args: []string{"--search", "~/work", "--dry-run"},
+			setupMock: func(relocator *mocks.MockAliasRelocator, finder *mocks.MockRepoFinder) {
+				entries := []libs.Entry{docs}
+				relocator.EXPECT().List().Return(entries, nil)
+				expectSearch(finder, entries, map[string]libs.RepoIdentity{
+					"/work/docs":      {Remote: "git@example.com:org/docs.git"},
+					"/work/docs-copy": {Remote: "git@example.com:org/docs.git"},
+				})
+			},
+			expectedOutput: "would move docs from /src/docs to /work/docs (same remote)\n",
+		},
+		{
+			name: "ambiguous and unmatched entries are left alone",
+			args: []string{"--search", "~/work", "--yes"},
+			setupMock: func(relocator *mocks.MockAliasRelocator, finder *mocks.MockRepoFinder) {
+				entries := []libs.Entry{apiGw, billing, legacy}
+				relocator.EXPECT().List().Return(entries, nil)
+				expectSearch(finder, entries, map[string]libs.RepoIdentity{
+					"/work/a/gateway": {Remote: "git@example.com:org/api-gw.git"},
+					"/work/b/gateway": {Remote: "git@example.com:org/api-gw.git"},
+				})
+			},
+			expectedStderr: "cannot relocate legacy: no remote or first commit stored, use 'gs mv legacy <path>'\n" +
+				"several matches for api-gw, use 'gs mv api-gw <path>': /work/a/gateway, /work/b/gateway\n" +
+				"no match found for billing (/src/billing)\n",
+			expectedError: "no moved repositories found",
+		},
+		{
+			name: "aliases matching the same repository are left alone",
+			args: []string{"--search", "~/work", "--yes"},
+			setupMock: func(relocator *mocks.MockAliasRelocator, finder *mocks.MockRepoFinder) {
+				gateway := libs.Entry{Alias: "gateway", Record: libs.Record{Path: "/src/gateway", RootCommit: "aaa"}}
+				entries := []libs.Entry{apiGw, billing, gateway}
+				relocator.EXPECT().List().Return(entries, nil)
+				expectSearch(finder, entries, map[string]libs.RepoIdentity{
+					"/work/org/gateway": {Remote: "https://example.com/org/api-gw", RootCommit: "aaa"},
+					"/work/billing":     {RootCommit: "bbb"},
+				})
+				relocator.EXPECT().Relocate(map[string]string{"billing": "/work/billing"}).Return(nil)
+			},
+			expectedOutput: "move billing from /src/billing to /work/billing (same first commit)\n",
+			expectedStderr: "several aliases match /work/org/gateway, use 'gs mv <alias> /work/org/gateway': api-gw, gateway\n",
+		},
+		{
+			name: "nothing to relocate",
+			args: []string{"--search", "~/work"},
+			setupMock: func(relocator *mocks.MockAliasRelocator, finder *mocks.MockRepoFinder) {
+				relocator.EXPECT().List().Return([]libs.Entry{healthy}, nil)
+				finder.EXPECT().CheckIfPathExists("/work/healthy").Return(true, nil)
+			},
+			expectedOutput: "nothing to relocate\n",
+		},
+		{
+			name:  "aborted relocate when not confirmed",
+			args:  []string{"--search", "~/work"},
+			stdin: "n\n",
+			setupMock: func(relocator *mocks.MockAliasRelocator, finder *mocks.MockRepoFinder) {
+				entries := []libs.Entry{billing}
+				relocator.EXPECT().List().Return(entries, nil)
+				expectSearch(finder, entries, map[string]libs.RepoIdentity{"/work/billing": {RootCommit: "bbb"}})
+				relocator.EXPECT().Release().Return(nil)
+			},
+			expectedOutput: "move billing from /src/billing to /work/billing (same first commit)\n",
+			expectedError:  "aborted",
+		},
+		{
+			name: "failed relocate due to database error",
+			args: []string{"--search", "~/work"},
+			setupMock: func(relocator *mocks.MockAliasRelocator, finder *mocks.MockRepoFinder) {
+				relocator.EXPECT().List().Return(nil, assert.AnError)
+			},
+			expectedError: "failed to list aliases",
+		},
+		{
+			name:          "failed relocate without search directory",
+			expectedError: `required flag(s) "search" not set`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRelocator := mocks.NewMockAliasRelocator(ctrl)
+			mockFinder := mocks.NewMockRepoFinder(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(mockRelocator, mockFinder)
+			}
+			relocateCmd := cmd.NewRelocateCmd(mockRelocator, mockFinder)
+
+			var out, stderr bytes.Buffer
+			relocateCmd.SetOut(&out)
+			relocateCmd.SetErr(&stderr)
+			relocateCmd.SetIn(strings.NewReader(tt.stdin))
+			relocateCmd.SetArgs(tt.args)
+			err := relocateCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+			if tt.expectedOutput != "" || tt.expectedError == "" {
+				assert.Equal(t, tt.expectedOutput, out.String())
+			}
+			if tt.expectedStderr != "" {
+				assert.True(t, strings.HasPrefix(stderr.String(), tt.expectedStderr), stderr.String())
+			}
+		})
+	}
+}
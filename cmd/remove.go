@@ -0,0 +1,78 @@
+//go:generate mockgen -destination=../mocks/cmd/remove.go -package=mocks -source=remove.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type AliasRemover interface {
+	AliasResolver
+	DBReleaser
+	Remove(aliases ...string) error
+}
+
+func NewRemoveCmd(remover AliasRemover) *cobra.Command {
+	var dryRun, yes bool
+
+	removeCmd := &cobra.Command{
+		Use:     "remove <alias>...",
+		Aliases: []string{"rm"},
+		Short:   "Remove one or more aliases from gitswitch",
+		Long: `Remove one or more aliases from gitswitch. The project directories themselves are left untouched.
+
+All aliases are removed in a single transaction: if any of them does not
+exist, none are removed. Removing more than one alias asks for confirmation
+unless --yes is given.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			aliases := uniqueStrings(args)
+
+			if dryRun {
+				for _, alias := range aliases {
+					path, err := lookupAlias(remover, alias)
+					if err != nil {
+						return err
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "would remove %s (%s)\n", alias, path)
+				}
+				return nil
+			}
+
+			if len(aliases) > 1 && !yes {
+				ok, err := confirm(cmd, remover, fmt.Sprintf("Remove %d aliases (%s)?", len(aliases), strings.Join(aliases, ", ")))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("aborted")
+				}
+			}
+
+			if err := remover.Remove(aliases...); err != nil {
+				return aliasError(err, fmt.Sprintf("failed to remove %s", strings.Join(aliases, ", ")))
+			}
+
+			return nil
+		},
+	}
+
+	removeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be removed without changing anything")
+	removeCmd.Flags().BoolVarP(&yes, "yes", "y", false, "do not ask for confirmation")
+	return removeCmd
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,141 @@
+package cmd_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRemoveCmd(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		stdin            string
+		setupMock        func(*mocks.MockAliasRemover)
+		expectedOutput   string
+		expectedError    string
+		expectedExitCode int
+	}{
+		{
+			name: "successful remove of a single alias without prompt",
+			args: []string{"alpha"},
+			setupMock: func(m *mocks.MockAliasRemover) {
+				m.EXPECT().Remove("alpha").Return(nil)
+			},
+		},
+		{
+			name:  "successful remove of several aliases after confirmation",
+			args:  []string{"alpha", "beta", "alpha"},
+			stdin: "y\n",
+			setupMock: func(m *mocks.MockAliasRemover) {
+				// The database is released while the question is open
+				release := m.EXPECT().Release().Return(nil)
+				m.EXPECT().Remove("alpha", "beta").Return(nil).After(release)
+			},
+		},
+		{
+			name: "successful remove of several aliases with --yes",
+			args: []string{"alpha", "beta", "--yes"},
+			setupMock: func(m *mocks.MockAliasRemover) {
+				m.EXPECT().Remove("alpha", "beta").Return(nil)
+			},
+		},
+		{
+			name:  "aborted remove of several aliases when not confirmed",
+			args:  []string{"alpha", "beta"},
+			stdin: "n\n",
+			setupMock: func(m *mocks.MockAliasRemover) {
+				m.EXPECT().Release().Return(nil)
+			},
+			expectedError: "aborted",
+		},
+		{
+			name: "failed remove of several aliases due to database that cannot be closed",
+			args: []string{"alpha", "beta"},
+			setupMock: func(m *mocks.MockAliasRemover) {
+				m.EXPECT().Release().Return(assert.AnError)
+			},
+			expectedError: "failed to close the database",
+		},
+		{
+			name: "successful dry run",
+			args: []string{"alpha", "beta", "--dry-run"},
+			setupMock: func(m *mocks.MockAliasRemover) {
+				m.EXPECT().Get("alpha").Return("/src/alpha", nil)
+				m.EXPECT().Get("beta").Return("/src/beta", nil)
+			},
+			expectedOutput: "would remove alpha (/src/alpha)\nwould remove beta (/src/beta)\n",
+		},
+		{
+			name: "failed dry run due to unknown alias",
+			args: []string{"alpha", "--dry-run"},
+			setupMock: func(m *mocks.MockAliasRemover) {
+				m.EXPECT().Get("alpha").Return("", nil)
+			},
+			expectedError:    "alias alpha not found",
+			expectedExitCode: cmd.ExitCodeAliasNotFound,
+		},
+		{
+			name: "failed due to unknown alias",
+			args: []string{"alpha"},
+			setupMock: func(m *mocks.MockAliasRemover) {
+				m.EXPECT().Remove("alpha").Return(fmt.Errorf("%w: %s", libs.ErrAliasNotFound, "alpha"))
+			},
+			expectedError:    "alias not found: alpha",
+			expectedExitCode: cmd.ExitCodeAliasNotFound,
+		},
+		{
+			name: "failed due to database error",
+			args: []string{"alpha"},
+			setupMock: func(m *mocks.MockAliasRemover) {
+				m.EXPECT().Remove("alpha").Return(assert.AnError)
+			},
+			expectedError: "failed to remove alpha",
+		},
+		{
+			name:          "failed due to missing alias",
+			args:          []string{},
+			setupMock:     func(m *mocks.MockAliasRemover) {},
+			expectedError: "requires at least 1 arg(s), only received 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRemover := mocks.NewMockAliasRemover(ctrl)
+			tt.setupMock(mockRemover)
+			removeCmd := cmd.NewRemoveCmd(mockRemover)
+
+			var out bytes.Buffer
+			removeCmd.SetOut(&out)
+			removeCmd.SetErr(&bytes.Buffer{})
+			removeCmd.SetIn(strings.NewReader(tt.stdin))
+			removeCmd.SetArgs(tt.args)
+			err := removeCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+				return
+			}
+
+			assert.EqualError(t, err, tt.expectedError)
+			if tt.expectedExitCode != 0 {
+				var exitErr *cmd.ExitError
+				assert.True(t, errors.As(err, &exitErr))
+				assert.Equal(t, tt.expectedExitCode, exitErr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+//go:generate mockgen -destination=../mocks/cmd/rename.go -package=mocks -source=rename.go
+package cmd
+
+import (
+	"fmt"
+	"gs/libs"
+
+	"github.com/spf13/cobra"
+)
+
+type AliasRenamer interface {
+	AliasResolver
+	Rename(oldAlias, newAlias string) error
+}
+
+func NewRenameCmd(renamer AliasRenamer) *cobra.Command {
+	var dryRun bool
+
+	renameCmd := &cobra.Command{
+		Use:   "rename <old-alias> <new-alias>",
+		Short: "Rename an alias while keeping its path",
+		Long: `Rename an alias while keeping the path it points at.
+
+The rename happens in a single transaction and never overwrites an existing alias.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldAlias, newAlias := args[0], args[1]
+
+			if dryRun {
+				path, err := lookupAlias(renamer, oldAlias)
+				if err != nil {
+					return err
+				}
+				existing, err := renamer.Get(newAlias)
+				if err != nil {
+					return fmt.Errorf("failed to look up alias %s", newAlias)
+				}
+				if existing != "" {
+					return fmt.Errorf("%w: %s", libs.ErrAliasExists, newAlias)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "would rename %s to %s (%s)\n", oldAlias, newAlias, path)
+				return nil
+			}
+
+			if err := renamer.Rename(oldAlias, newAlias); err != nil {
+				return aliasError(err, fmt.Sprintf("failed to rename %s to %s", oldAlias, newAlias))
+			}
+
+			return nil
+		},
+	}
+
+	renameCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be renamed without changing anything")
+	return renameCmd
+}
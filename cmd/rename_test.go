@@ -0,0 +1,97 @@
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRenameCmd(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		setupMock      func(*mocks.MockAliasRenamer)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name: "successful rename",
+			args: []string{"old", "new"},
+			setupMock: func(m *mocks.MockAliasRenamer) {
+				m.EXPECT().Rename("old", "new").Return(nil)
+			},
+		},
+		{
+			name: "successful dry run",
+			args: []string{"old", "new", "--dry-run"},
+			setupMock: func(m *mocks.MockAliasRenamer) {
+				m.EXPECT().Get("old").Return("/src/project", nil)
+				m.EXPECT().Get("new").Return("", nil)
+			},
+			expectedOutput: "would rename old to new (/src/project)\n",
+		},
+		{
+			name: "failed dry run due to existing new alias",
+			args: []string{"old", "new", "--dry-run"},
+			setupMock: func(m *mocks.MockAliasRenamer) {
+				m.EXPECT().Get("old").Return("/src/project", nil)
+				m.EXPECT().Get("new").Return("/src/other", nil)
+			},
+			expectedError: "alias already exists: new",
+		},
+		{
+			name: "failed due to existing new alias",
+			args: []string{"old", "new"},
+			setupMock: func(m *mocks.MockAliasRenamer) {
+				m.EXPECT().Rename("old", "new").Return(fmt.Errorf("%w: %s", libs.ErrAliasExists, "new"))
+			},
+			expectedError: "alias already exists: new",
+		},
+		{
+			name: "failed due to unknown old alias",
+			args: []string{"old", "new"},
+			setupMock: func(m *mocks.MockAliasRenamer) {
+				m.EXPECT().Rename("old", "new").Return(fmt.Errorf("%w: %s", libs.ErrAliasNotFound, "old"))
+			},
+			expectedError: "alias not found: old",
+		},
+		{
+			name: "failed due to database error",
+			args: []string{"old", "new"},
+			setupMock: func(m *mocks.MockAliasRenamer) {
+				m.EXPECT().Rename("old", "new").Return(assert.AnError)
+			},
+			expectedError: "failed to rename old to new",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRenamer := mocks.NewMockAliasRenamer(ctrl)
+			tt.setupMock(mockRenamer)
+			renameCmd := cmd.NewRenameCmd(mockRenamer)
+
+			var out bytes.Buffer
+			renameCmd.SetOut(&out)
+			renameCmd.SetErr(&bytes.Buffer{})
+			renameCmd.SetArgs(tt.args)
+			err := renameCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
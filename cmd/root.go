@@ -1,18 +1,170 @@
+//go:generate mockgen -destination=../mocks/cmd/root.go -package=mocks -source=root.go
 package cmd
 
 import (
+	"errors"
 	"gs/libs"
 
 	"github.com/spf13/cobra"
 )
 
-func NewRootCommand(dbService *libs.DBService, fileService *libs.FileService) *cobra.Command {
+// Exit codes returned by gs so that shell wrappers can tell failures apart.
+const (
+	ExitCodeError          = 1
+	ExitCodeAliasNotFound  = 2
+	ExitCodeAmbiguousAlias = 3
+)
+
+// ExitError carries the process exit code a command wants main to use.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// aliasError keeps the typed alias errors from libs visible to the user and
+// maps a missing alias to ExitCodeAliasNotFound. Any other error is replaced
+// by msg.
+func aliasError(err error, msg string) error {
+	switch {
+	case errors.Is(err, libs.ErrAliasNotFound):
+		return &ExitError{Code: ExitCodeAliasNotFound, Err: err}
+	case errors.Is(err, libs.ErrAliasExists):
+		return err
+	}
+	return errors.New(msg)
+}
+
+// DBReleaser lets go of the database while a command waits on something
+// else, so that gs calls from other shells or child commands do not block.
+type DBReleaser interface {
+	Release() error
+}
+
+// dbFreeCommands do not use the database at all. They release it before
+// running, since some wait on the user, as 'gs config edit' does.
+var dbFreeCommands = []string{
+	"completion", "config", "help", "init",
+	cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd,
+}
+
+// topLevelName returns the name of the subcommand of the root that cmd is or
+// belongs to, or "" for the root itself.
+func topLevelName(cmd *cobra.Command) string {
+	if !cmd.HasParent() {
+		return ""
+	}
+	for cmd.Parent().HasParent() {
+		cmd = cmd.Parent()
+	}
+	return cmd.Name()
+}
+
+type RootDBService interface {
+	DBService
+	AliasResolver
+	AliasSwitcher
+	AliasLister
+	AliasRemover
+	AliasRenamer
+	AliasMover
+	AliasRanker
+	AliasTagger
+	AliasIterator
+	AliasRelocator
+	AliasBulkAdder
+	ProfileChecker
+	ProfileManager
+	DBReleaser
+}
+
+type RootFileService interface {
+	FileService
+	BranchReader
+	RepoFinder
+	RepoScanner
+}
+
+func NewRootCommand(dbService RootDBService, fileService RootFileService, migrator Migrator, picker ProjectPicker, runner ProjectRunner, status StatusReader, config ConfigManager) *cobra.Command {
+	var exact bool
+
 	rootCmd := &cobra.Command{
-		Use:   "gs",
+		Use:   "gs [alias]",
 		Short: "gitswitch: quick and easy Git project switching",
-		Long:  "gitswitch (gs) is a fast and simple CLI tool for switching between your Git projects.",
+		Long: `gitswitch (gs) is a fast and simple CLI tool for switching between your Git projects.
+
+Running 'gs' without arguments opens a full-screen picker over the stored
+aliases: type to filter, move with the arrow keys or Ctrl-N/Ctrl-P and press
+Enter to switch. Without a terminal it asks for a number instead. If an
+external finder is configured, 'gs' uses it like 'gs pick' does.
+
+Running 'gs <alias>' prints the path stored for the alias and records the visit
+for 'gs top'. 'gs -' goes back to the previously visited project.
+
+If no alias equals the argument, gs looks for a unique alias or project
+directory name starting with it, then one containing it, then a fuzzy match of
+its letters in order. The parent directories of the projects are only searched
+when none of those match. 'gs <tag>/<alias>' does the same among the projects
+with that tag. Pass --exact to only accept an alias exactly as stored. If
+nothing matches, gs exits with code 2; if several aliases match equally well,
+it lists them and exits with code 3.`,
+		Args: cobra.MaximumNArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if contains(dbFreeCommands, topLevelName(cmd)) {
+				if err := dbService.Release(); err != nil {
+					return errors.New("failed to close the database")
+				}
+				return nil
+			}
+			if isDBCommand(cmd) {
+				return nil
+			}
+			if err := autoMigrate(cmd, migrator); err != nil {
+				return err
+			}
+			return checkProfile(cmd, dbService)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if len(args) == 0 {
+				alias, err := pickAlias(cmd, dbService, fileService, picker, "")
+				if err != nil {
+					return err
+				}
+				return switchToAlias(cmd, dbService, alias, true)
+			}
+
+			return switchToAlias(cmd, dbService, args[0], exact)
+		},
 	}
 
+	AddConfigFlags(rootCmd.PersistentFlags())
+	rootCmd.Flags().BoolVar(&exact, "exact", false, "only switch to an alias that matches exactly")
+
 	rootCmd.AddCommand(NewAddCmd(dbService, fileService))
+	rootCmd.AddCommand(NewInitCmd())
+	rootCmd.AddCommand(NewListCmd(dbService))
+	rootCmd.AddCommand(NewRemoveCmd(dbService))
+	rootCmd.AddCommand(NewRenameCmd(dbService))
+	rootCmd.AddCommand(NewMvCmd(dbService, fileService))
+	rootCmd.AddCommand(NewDBCmd(migrator))
+	rootCmd.AddCommand(NewTopCmd(dbService))
+	rootCmd.AddCommand(NewPickCmd(dbService, fileService, picker))
+	rootCmd.AddCommand(NewTagCmd(dbService))
+	rootCmd.AddCommand(NewExecCmd(dbService, runner))
+	rootCmd.AddCommand(NewStatusCmd(dbService, status))
+	rootCmd.AddCommand(NewDoctorCmd(dbService, fileService))
+	rootCmd.AddCommand(NewPruneCmd(dbService, fileService))
+	rootCmd.AddCommand(NewRelocateCmd(dbService, fileService))
+	rootCmd.AddCommand(NewScanCmd(dbService, fileService))
+	rootCmd.AddCommand(NewConfigCmd(config))
+	rootCmd.AddCommand(NewProfileCmd(dbService))
 	return rootCmd
 }
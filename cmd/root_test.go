@@ -0,0 +1,456 @@
+package cmd_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRootCmd(t *testing.T) {
+	aliasValue := "aliasValue"
+	pathValue := "pathValue"
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedOutput   string
+		expectedError    string
+		expectedExitCode int
+		mockVisit        MockCall[string]
+		mockPrevious     MockCall[string]
+		mockMatch        MockCall[libs.Entry]
+	}{
+		{
+			name: "successful switch prints stored path",
+			args: []string{aliasValue},
+			mockVisit: MockCall[string]{
+				args:     []string{aliasValue},
+				Times:    1,
+				Response: pathValue,
+			},
+			expectedOutput: pathValue + "\n",
+		},
+		{
+			name: "failed switch due to unknown alias",
+			args: []string{aliasValue},
+			mockVisit: MockCall[string]{
+				args:  []string{aliasValue},
+				Times: 1,
+				Error: fmt.Errorf("%w: %s", libs.ErrAliasNotFound, aliasValue),
+			},
+			mockMatch: MockCall[libs.Entry]{
+				args:  []string{aliasValue},
+				Times: 1,
+				Error: fmt.Errorf("%w: %s", libs.ErrAliasNotFound, aliasValue),
+			},
+			expectedError:    "alias aliasValue not found",
+			expectedExitCode: cmd.ExitCodeAliasNotFound,
+		},
+		{
+			name: "failed switch due to database error",
+			args: []string{aliasValue},
+			mockVisit: MockCall[string]{
+				args:  []string{aliasValue},
+				Times: 1,
+				Error: assert.AnError,
+			},
+			expectedError: "failed to look up alias aliasValue",
+		},
+		{
+			name: "successful switch to previous project",
+			args: []string{"-"},
+			mockPrevious: MockCall[string]{
+				Times:    1,
+				Response: aliasValue,
+			},
+			mockVisit: MockCall[string]{
+				args:     []string{aliasValue},
+				Times:    1,
+				Response: pathValue,
+			},
+			expectedOutput: pathValue + "\n",
+		},
+		{
+			name: "failed switch to previous project when there is none",
+			args: []string{"-"},
+			mockPrevious: MockCall[string]{
+				Times: 1,
+			},
+			expectedError:    "no previous project",
+			expectedExitCode: cmd.ExitCodeAliasNotFound,
+		},
+		{
+			name: "failed switch to previous project due to database error",
+			args: []string{"-"},
+			mockPrevious: MockCall[string]{
+				Times: 1,
+				Error: assert.AnError,
+			},
+			expectedError: "failed to look up previous project",
+		},
+		{
+			name:          "failed switch due to too many args",
+			args:          []string{aliasValue, pathValue},
+			expectedError: "accepts at most 1 arg(s), received 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDBService := mocks.NewMockRootDBService(ctrl)
+			mockFileService := mocks.NewMockRootFileService(ctrl)
+			mockMigrator := mocks.NewMockMigrator(ctrl)
+			mockMigrator.EXPECT().Migrate().Return(libs.MigrationResult{}, nil).AnyTimes()
+			mockDBService.EXPECT().CheckProfile().Return(nil).AnyTimes()
+			rootCmd := cmd.NewRootCommand(mockDBService, mockFileService, mockMigrator, mocks.NewMockProjectPicker(ctrl), mocks.NewMockProjectRunner(ctrl), mocks.NewMockStatusReader(ctrl), mocks.NewMockConfigManager(ctrl))
+
+			if tt.mockPrevious.Times > 0 {
+				mockDBService.EXPECT().PreviousAlias().Return(tt.mockPrevious.Response, tt.mockPrevious.Error).Times(tt.mockPrevious.Times)
+			}
+
+			if tt.mockVisit.Times > 0 && len(tt.mockVisit.args) > 0 {
+				mockDBService.EXPECT().Visit(tt.mockVisit.args[0]).Return(tt.mockVisit.Response, tt.mockVisit.Error).Times(tt.mockVisit.Times)
+			}
+
+			if tt.mockMatch.Times > 0 && len(tt.mockMatch.args) > 0 {
+				mockDBService.EXPECT().Match(tt.mockMatch.args[0]).Return(tt.mockMatch.Response, tt.mockMatch.Error).Times(tt.mockMatch.Times)
+			}
+
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args)
+			err := rootCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+				return
+			}
+
+			assert.EqualError(t, err, tt.expectedError)
+			if tt.expectedExitCode != 0 {
+				var exitErr *cmd.ExitError
+				assert.True(t, errors.As(err, &exitErr))
+				assert.Equal(t, tt.expectedExitCode, exitErr.Code)
+			}
+		})
+	}
+}
+
+func TestRootCmdMatching(t *testing.T) {
+	notFound := func(alias string) error {
+		return fmt.Errorf("%w: %s", libs.ErrAliasNotFound, alias)
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		setupMock        func(*mocks.MockRootDBService)
+		expectedOutput   string
+		expectedError    string
+		expectedExitCode int
+	}{
+		{
+			name: "successful switch to the matched alias on a miss",
+			args: []string{"gw"},
+			setupMock: func(db *mocks.MockRootDBService) {
+				gomock.InOrder(
+					db.EXPECT().Visit("gw").Return("", notFound("gw")),
+					db.EXPECT().Match("gw").Return(libs.Entry{Alias: "gateway", Record: libs.Record{Path: "/src/gateway"}}, nil),
+					db.EXPECT().Visit("gateway").Return("/src/gateway", nil),
+				)
+			},
+			expectedOutput: "/src/gateway\n",
+		},
+		{
+			name: "failed switch due to ambiguous match",
+			args: []string{"api"},
+			setupMock: func(db *mocks.MockRootDBService) {
+				db.EXPECT().Visit("api").Return("", notFound("api"))
+				db.EXPECT().Match("api").Return(libs.Entry{}, &libs.AmbiguousAliasError{Query: "api", Candidates: []string{"api-gateway", "apis"}})
+			},
+			expectedError:    "alias api is ambiguous, candidates: api-gateway, apis",
+			expectedExitCode: cmd.ExitCodeAmbiguousAlias,
+		},
+		{
+			name: "failed switch due to database error while matching",
+			args: []string{"api"},
+			setupMock: func(db *mocks.MockRootDBService) {
+				db.EXPECT().Visit("api").Return("", notFound("api"))
+				db.EXPECT().Match("api").Return(libs.Entry{}, assert.AnError)
+			},
+			expectedError: "failed to look up alias api",
+		},
+		{
+			name: "exact flag disables matching",
+			args: []string{"--exact", "gw"},
+			setupMock: func(db *mocks.MockRootDBService) {
+				db.EXPECT().Visit("gw").Return("", notFound("gw"))
+			},
+			expectedError:    "alias gw not found",
+			expectedExitCode: cmd.ExitCodeAliasNotFound,
+		},
+		{
+			name: "previous project is never matched",
+			args: []string{"-"},
+			setupMock: func(db *mocks.MockRootDBService) {
+				db.EXPECT().PreviousAlias().Return("removed", nil)
+				db.EXPECT().Visit("removed").Return("", notFound("removed"))
+			},
+			expectedError:    "alias removed not found",
+			expectedExitCode: cmd.ExitCodeAliasNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDBService := mocks.NewMockRootDBService(ctrl)
+			mockMigrator := mocks.NewMockMigrator(ctrl)
+			mockMigrator.EXPECT().Migrate().Return(libs.MigrationResult{}, nil).AnyTimes()
+			mockDBService.EXPECT().CheckProfile().Return(nil).AnyTimes()
+			tt.setupMock(mockDBService)
+			rootCmd := cmd.NewRootCommand(mockDBService, mocks.NewMockRootFileService(ctrl), mockMigrator, mocks.NewMockProjectPicker(ctrl), mocks.NewMockProjectRunner(ctrl), mocks.NewMockStatusReader(ctrl), mocks.NewMockConfigManager(ctrl))
+
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args)
+			err := rootCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+				return
+			}
+
+			assert.EqualError(t, err, tt.expectedError)
+			if tt.expectedExitCode != 0 {
+				var exitErr *cmd.ExitError
+				assert.True(t, errors.As(err, &exitErr))
+				assert.Equal(t, tt.expectedExitCode, exitErr.Code)
+			}
+		})
+	}
+}
+
+func TestRootCmdPicker(t *testing.T) {
+	lastUsed := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	entries := []libs.Entry{
+		{Alias: "api", Record: libs.Record{Path: "/src/api"}},
+		{Alias: "web", Record: libs.Record{Path: "/src/web", LastUsed: lastUsed, UseCount: 3}},
+	}
+	// Most recently used first, branches read from each project
+	items := []libs.PickerItem{
+		{Alias: "web", Path: "/src/web", Branch: "develop", LastUsed: lastUsed, UseCount: 3},
+		{Alias: "api", Path: "/src/api"},
+	}
+
+	tests := []struct {
+		name             string
+		setupMock        func(*mocks.MockRootDBService, *mocks.MockRootFileService, *mocks.MockProjectPicker)
+		expectedOutput   string
+		expectedError    string
+		expectedExitCode int
+	}{
+		{
+			name: "successful switch to the picked project",
+			setupMock: func(db *mocks.MockRootDBService, fs *mocks.MockRootFileService, picker *mocks.MockProjectPicker) {
+				db.EXPECT().List().Return(entries, nil)
+				fs.EXPECT().GitBranch("/src/web").Return("develop", nil)
+				fs.EXPECT().GitBranch("/src/api").Return("", libs.ErrNotGitRepository)
+				// The database is released while the user chooses
+				release := db.EXPECT().Release().Return(nil)
+				picker.EXPECT().PickWith("", items).After(release).Return(items[1], nil)
+				db.EXPECT().Visit("api").Return("/src/api", nil)
+			},
+			expectedOutput: "/src/api\n",
+		},
+		{
+			name: "failed due to cancelled picker",
+			setupMock: func(db *mocks.MockRootDBService, fs *mocks.MockRootFileService, picker *mocks.MockProjectPicker) {
+				db.EXPECT().List().Return(entries, nil)
+				fs.EXPECT().GitBranch(gomock.Any()).Return("", nil).Times(2)
+				db.EXPECT().Release().Return(nil)
+				picker.EXPECT().PickWith("", gomock.Any()).Return(libs.PickerItem{}, libs.ErrPickerCancelled)
+			},
+			expectedError:    "no project selected",
+			expectedExitCode: cmd.ExitCodeCancelled,
+		},
+		{
+			name: "failed due to database that cannot be closed",
+			setupMock: func(db *mocks.MockRootDBService, fs *mocks.MockRootFileService, picker *mocks.MockProjectPicker) {
+				db.EXPECT().List().Return(entries, nil)
+				fs.EXPECT().GitBranch(gomock.Any()).Return("", nil).Times(2)
+				db.EXPECT().Release().Return(assert.AnError)
+			},
+			expectedError: "failed to close the database",
+		},
+		{
+			name: "failed due to picker error",
+			setupMock: func(db *mocks.MockRootDBService, fs *mocks.MockRootFileService, picker *mocks.MockProjectPicker) {
+				db.EXPECT().List().Return(entries, nil)
+				fs.EXPECT().GitBranch(gomock.Any()).Return("", nil).Times(2)
+				db.EXPECT().Release().Return(nil)
+				picker.EXPECT().PickWith("", gomock.Any()).Return(libs.PickerItem{}, assert.AnError)
+			},
+			expectedError: "failed to run the project picker",
+		},
+		{
+			name: "failed due to invalid numbered selection",
+			setupMock: func(db *mocks.MockRootDBService, fs *mocks.MockRootFileService, picker *mocks.MockProjectPicker) {
+				db.EXPECT().List().Return(entries, nil)
+				fs.EXPECT().GitBranch(gomock.Any()).Return("", nil).Times(2)
+				db.EXPECT().Release().Return(nil)
+				picker.EXPECT().PickWith("", gomock.Any()).Return(libs.PickerItem{}, fmt.Errorf("%w %q", libs.ErrInvalidSelection, "3"))
+			},
+			expectedError: `invalid selection "3"`,
+		},
+		{
+			name: "failed due to no stored aliases",
+			setupMock: func(db *mocks.MockRootDBService, fs *mocks.MockRootFileService, picker *mocks.MockProjectPicker) {
+				db.EXPECT().List().Return(nil, nil)
+			},
+			expectedError: "no aliases stored yet, add one with 'gs add'",
+		},
+		{
+			name: "failed due to database error",
+			setupMock: func(db *mocks.MockRootDBService, fs *mocks.MockRootFileService, picker *mocks.MockProjectPicker) {
+				db.EXPECT().List().Return(nil, assert.AnError)
+			},
+			expectedError: "failed to list aliases",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDBService := mocks.NewMockRootDBService(ctrl)
+			mockFileService := mocks.NewMockRootFileService(ctrl)
+			mockPicker := mocks.NewMockProjectPicker(ctrl)
+			mockMigrator := mocks.NewMockMigrator(ctrl)
+			mockMigrator.EXPECT().Migrate().Return(libs.MigrationResult{}, nil).AnyTimes()
+			mockDBService.EXPECT().CheckProfile().Return(nil).AnyTimes()
+			tt.setupMock(mockDBService, mockFileService, mockPicker)
+			rootCmd := cmd.NewRootCommand(mockDBService, mockFileService, mockMigrator, mockPicker, mocks.NewMockProjectRunner(ctrl), mocks.NewMockStatusReader(ctrl), mocks.NewMockConfigManager(ctrl))
+
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs([]string{})
+			err := rootCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+				return
+			}
+
+			assert.EqualError(t, err, tt.expectedError)
+			if tt.expectedExitCode != 0 {
+				var exitErr *cmd.ExitError
+				assert.True(t, errors.As(err, &exitErr))
+				assert.Equal(t, tt.expectedExitCode, exitErr.Code)
+			}
+		})
+	}
+}
+
+func TestRootCmdAutoMigrate(t *testing.T) {
+	applied := libs.MigrationResult{
+		From:       0,
+		To:         libs.SchemaVersion,
+		Applied:    []libs.Migration{{Version: 1}, {Version: 2}},
+		BackupPath: "/home/user/.gs/bbolt.db.v0.bak",
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		setupMock      func(*mocks.MockRootDBService, *mocks.MockMigrator)
+		expectedOutput string
+		expectedStderr string
+		expectedError  string
+	}{
+		{
+			name: "migrates before switching and reports on stderr",
+			args: []string{"alias"},
+			setupMock: func(db *mocks.MockRootDBService, m *mocks.MockMigrator) {
+				gomock.InOrder(
+					m.EXPECT().Migrate().Return(applied, nil),
+					db.EXPECT().Visit("alias").Return("/src/project", nil),
+				)
+			},
+			expectedOutput: "/src/project\n",
+			expectedStderr: "upgraded database from schema version 0 to 2 (backup at /home/user/.gs/bbolt.db.v0.bak)\n",
+		},
+		{
+			name: "stays quiet when nothing was migrated",
+			args: []string{"alias"},
+			setupMock: func(db *mocks.MockRootDBService, m *mocks.MockMigrator) {
+				m.EXPECT().Migrate().Return(libs.MigrationResult{From: 2, To: 2}, nil)
+				db.EXPECT().Visit("alias").Return("/src/project", nil)
+			},
+			expectedOutput: "/src/project\n",
+		},
+		{
+			name: "fails when the migration fails",
+			args: []string{"alias"},
+			setupMock: func(db *mocks.MockRootDBService, m *mocks.MockMigrator) {
+				m.EXPECT().Migrate().Return(libs.MigrationResult{}, assert.AnError)
+			},
+			expectedError: "failed to migrate database: " + assert.AnError.Error(),
+		},
+		{
+			name: "db commands skip the automatic migration",
+			args: []string{"db", "version"},
+			setupMock: func(db *mocks.MockRootDBService, m *mocks.MockMigrator) {
+				m.EXPECT().Version().Return(0, nil)
+			},
+			expectedOutput: "schema version 0 (latest 2)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDBService := mocks.NewMockRootDBService(ctrl)
+			mockMigrator := mocks.NewMockMigrator(ctrl)
+			mockDBService.EXPECT().CheckProfile().Return(nil).AnyTimes()
+			tt.setupMock(mockDBService, mockMigrator)
+			rootCmd := cmd.NewRootCommand(mockDBService, mocks.NewMockRootFileService(ctrl), mockMigrator, mocks.NewMockProjectPicker(ctrl), mocks.NewMockProjectRunner(ctrl), mocks.NewMockStatusReader(ctrl), mocks.NewMockConfigManager(ctrl))
+
+			var out, stderr bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(&stderr)
+			rootCmd.SetArgs(tt.args)
+			err := rootCmd.Execute()
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedOutput, out.String())
+			assert.Equal(t, tt.expectedStderr, stderr.String())
+		})
+	}
+}
@@ -0,0 +1,143 @@
+//go:generate mockgen -destination=../mocks/cmd/scan.go -package=mocks -source=scan.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"gs/libs"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+type RepoScanner interface {
+	NormalizePath(path string, resolveSymlinks bool) (string, error)
+	FindRepositories(root string, maxDepth int) ([]string, error)
+	RepoIdentity(path string) (libs.RepoIdentity, error)
+}
+
+type AliasBulkAdder interface {
+	AliasLister
+	DBReleaser
+	AddAll(entries []libs.Entry) error
+}
+
+func NewScanCmd(adder AliasBulkAdder, scanner RepoScanner) *cobra.Command {
+	var depth int
+	var dryRun, yes bool
+
+	scanCmd := &cobra.Command{
+		Use:   "scan <dir>...",
+		Short: "Add every Git repository under one or more directories",
+		Long: `Find the Git repositories under the given directories and add the ones that
+are not stored yet, for example 'gs scan ~/src --depth 4' on a new machine.
+
+Directories are searched concurrently, at most --depth levels deep, without
+descending into repositories or into directories matching the scan.ignore
+patterns from the config file (by default hidden directories, node_modules
+and vendor).
+
+Each repository is added under its directory name. When that name is
+already taken, or shared by several repositories found, the parent
+directories are prepended one at a time (org-repo, then src-org-repo), and a
+numeric suffix is the last resort. The result is shown and everything is
+added in a single transaction after asking for confirmation, unless --yes is
+given.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			entries, err := adder.List()
+			if err != nil {
+				return errors.New("failed to list aliases")
+			}
+			taken := make(map[string]bool, len(entries))
+			registered := make(map[string]string, len(entries))
+			for _, entry := range entries {
+				taken[entry.Alias] = true
+				registered[entry.Path] = entry.Alias
+			}
+
+			var found []string
+			seen := map[string]bool{}
+			skipped := 0
+			for _, dir := range args {
+				root, err := normalizePath(dir, scanner, true)
+				if err != nil {
+					return err
+				}
+				paths, err := scanner.FindRepositories(root, depth)
+				if err != nil {
+					return fmt.Errorf("failed to scan %s: %w", root, err)
+				}
+				for _, path := range paths {
+					switch {
+					case seen[path]:
+					case registered[path] != "":
+						skipped++
+					default:
+						found = append(found, path)
+					}
+					seen[path] = true
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			if len(found) == 0 {
+				fmt.Fprintf(out, "no new repositories found (%d already stored)\n", skipped)
+				return nil
+			}
+
+			aliases := libs.AssignAliases(found, taken)
+			newEntries := make([]libs.Entry, 0, len(found))
+			for _, path := range found {
+				entry := libs.Entry{Alias: aliases[path], Record: libs.Record{Path: path}}
+				// Best effort, as with 'gs add'
+				if identity, err := scanner.RepoIdentity(path); err == nil {
+					entry.Remote, entry.RootCommit = identity.Remote, identity.RootCommit
+				}
+				newEntries = append(newEntries, entry)
+			}
+
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "ALIAS\tPATH")
+			for _, entry := range newEntries {
+				fmt.Fprintf(tw, "%s\t%s\n", entry.Alias, entry.Path)
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "%d new repositories, %d already stored\n", len(newEntries), skipped)
+			if dryRun {
+				return nil
+			}
+
+			if !yes {
+				ok, err := confirm(cmd, adder, fmt.Sprintf("Add %d repositories?", len(newEntries)))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("aborted")
+				}
+			}
+
+			if err := adder.AddAll(newEntries); err != nil {
+				if errors.Is(err, libs.ErrAliasExists) {
+					return fmt.Errorf("%w, run 'gs scan' again", err)
+				}
+				names := make([]string, 0, len(newEntries))
+				for _, entry := range newEntries {
+					names = append(names, entry.Alias)
+				}
+				return fmt.Errorf("failed to add %s", strings.Join(names, ", "))
+			}
+			return nil
+		},
+	}
+
+	scanCmd.Flags().IntVar(&depth, "depth", 4, "how many directory levels to search below each directory, 0 for no limit")
+	scanCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be added without changing anything")
+	scanCmd.Flags().BoolVarP(&yes, "yes", "y", false, "do not ask for confirmation")
+	return scanCmd
+}
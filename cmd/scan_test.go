@@ -0,0 +1,166 @@
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestScanCmd(t *testing.T) {
+	stored := []libs.Entry{
+		{Alias: "api", Record: libs.Record{Path: "/home/me/old/api"}},
+		{Alias: "web", Record: libs.Record{Path: "/home/me/src/web"}},
+	}
+	found := []string{"/home/me/src/acme/api", "/home/me/src/other/api", "/home/me/src/web", "/home/me/src/tools"}
+
+	expectScan := func(adder *mocks.MockAliasBulkAdder, scanner *mocks.MockRepoScanner, depth int) {
+		adder.EXPECT().List().Return(stored, nil)
+		scanner.EXPECT().NormalizePath("~/src", true).Return("/home/me/src", nil)
+		scanner.EXPECT().FindRepositories("/home/me/src", depth).Return(found, nil)
+		scanner.EXPECT().RepoIdentity("/home/me/src/acme/api").Return(libs.RepoIdentity{Remote: "git@example.com:acme/api.git"}, nil)
+		scanner.EXPECT().RepoIdentity("/home/me/src/other/api").Return(libs.RepoIdentity{}, assert.AnError)
+		scanner.EXPECT().RepoIdentity("/home/me/src/tools").Return(libs.RepoIdentity{RootCommit: "abc123"}, nil)
+	}
+	preview := "ALIAS      PATH\n" +
+		"acme-api   /home/me/src/acme/api\n" +
+		"other-api  /home/me/src/other/api\n" +
+		"tools      /home/me/src/tools\n" +
+		"3 new repositories, 1 already stored\n"
+	newEntries := []libs.Entry{
+		{Alias: "acme-api", Record: libs.Record{Path: "/home/me/src/acme/api", Remote: "git@example.com:acme/api.git"}},
+		{Alias: "other-api", Record: libs.Record{Path: "/home/me/src/other/api"}},
+		{Alias: "tools", Record: libs.Record{Path: "/home/me/src/tools", RootCommit: "abc123"}},
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		stdin          string
+		setupMock      func(*mocks.MockAliasBulkAdder, *mocks.MockRepoScanner)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:  "successful scan after confirmation",
+			args:  []string{"~/src", "--depth", "2"},
+			stdin: "y\n",
+			setupMock: func(adder *mocks.MockAliasBulkAdder, scanner *mocks.MockRepoScanner) {
+				expectScan(adder, scanner, 2)
+				release := adder.EXPECT().Release().Return(nil)
+				adder.EXPECT().AddAll(newEntries).Return(nil).After(release)
+			},
+			expectedOutput: preview,
+		},
+		{
+			name: "successful dry run",
+			args: []string{"~/src", "--dry-run"},
+			setupMock: func(adder *mocks.MockAliasBulkAdder, scanner *mocks.MockRepoScanner) {
+				expectScan(adder, scanner, 4)
+			},
+			expectedOutput: preview,
+		},
+		{
+			name: "successful scan of overlapping directories with --yes",
+			args: []string{"~/src", "/home/me/src/tools", "-y"},
+			setupMock: func(adder *mocks.MockAliasBulkAdder, scanner *mocks.MockRepoScanner) {
+				expectScan(adder, scanner, 4)
+				scanner.EXPECT().NormalizePath("/home/me/src/tools", true).Return("/home/me/src/tools", nil)
+				scanner.EXPECT().FindRepositories("/home/me/src/tools", 4).Return([]string{"/home/me/src/tools"}, nil)
+				adder.EXPECT().AddAll(newEntries).Return(nil)
+			},
+			expectedOutput: preview,
+		},
+		{
+			name: "nothing new to add",
+			args: []string{"~/src"},
+			setupMock: func(adder *mocks.MockAliasBulkAdder, scanner *mocks.MockRepoScanner) {
+				adder.EXPECT().List().Return(stored, nil)
+				scanner.EXPECT().NormalizePath("~/src", true).Return("/home/me/src", nil)
+				scanner.EXPECT().FindRepositories("/home/me/src", 4).Return([]string{"/home/me/src/web"}, nil)
+			},
+			expectedOutput: "no new repositories found (1 already stored)\n",
+		},
+		{
+			name:  "aborted scan when not confirmed",
+			args:  []string{"~/src"},
+			stdin: "n\n",
+			setupMock: func(adder *mocks.MockAliasBulkAdder, scanner *mocks.MockRepoScanner) {
+				expectScan(adder, scanner, 4)
+				adder.EXPECT().Release().Return(nil)
+			},
+			expectedOutput: preview,
+			expectedError:  "aborted",
+		},
+		{
+			name: "failed scan due to an alias taken meanwhile",
+			args: []string{"~/src", "--yes"},
+			setupMock: func(adder *mocks.MockAliasBulkAdder, scanner *mocks.MockRepoScanner) {
+				expectScan(adder, scanner, 4)
+				adder.EXPECT().AddAll(newEntries).Return(fmt.Errorf("%w: tools", libs.ErrAliasExists))
+			},
+			expectedOutput: preview,
+			expectedError:  "alias already exists: tools, run 'gs scan' again",
+		},
+		{
+			name: "failed scan due to database error",
+			args: []string{"~/src", "--yes"},
+			setupMock: func(adder *mocks.MockAliasBulkAdder, scanner *mocks.MockRepoScanner) {
+				expectScan(adder, scanner, 4)
+				adder.EXPECT().AddAll(newEntries).Return(assert.AnError)
+			},
+			expectedOutput: preview,
+			expectedError:  "failed to add acme-api, other-api, tools",
+		},
+		{
+			name: "failed scan due to unreadable directory",
+			args: []string{"~/src"},
+			setupMock: func(adder *mocks.MockAliasBulkAdder, scanner *mocks.MockRepoScanner) {
+				adder.EXPECT().List().Return(stored, nil)
+				scanner.EXPECT().NormalizePath("~/src", true).Return("/home/me/src", nil)
+				scanner.EXPECT().FindRepositories("/home/me/src", 4).Return(nil, assert.AnError)
+			},
+			expectedError: "failed to scan /home/me/src: " + assert.AnError.Error(),
+		},
+		{
+			name:          "failed scan without directory",
+			expectedError: "requires at least 1 arg(s), only received 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAdder := mocks.NewMockAliasBulkAdder(ctrl)
+			mockScanner := mocks.NewMockRepoScanner(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(mockAdder, mockScanner)
+			}
+			scanCmd := cmd.NewScanCmd(mockAdder, mockScanner)
+
+			var out bytes.Buffer
+			scanCmd.SetOut(&out)
+			scanCmd.SetErr(&bytes.Buffer{})
+			scanCmd.SetIn(strings.NewReader(tt.stdin))
+			scanCmd.SetArgs(tt.args)
+			err := scanCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+			if tt.expectedOutput != "" || tt.expectedError == "" {
+				assert.Equal(t, tt.expectedOutput, out.String())
+			}
+		})
+	}
+}
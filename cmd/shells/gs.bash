@@ -0,0 +1,38 @@
+# gitswitch shell integration for bash.
+# Add the following line to ~/.bashrc:
+#
+#   eval "$({{.Name}} init bash)"
+
+{{.Name}}() {
+    # Skip the global flags, as in "gs --profile work <alias>", to find the
+    # alias or subcommand
+    local __gs_i=1
+    while [ "$__gs_i" -le "$#" ]; do
+        case "${!__gs_i}" in
+            {{join .SwitchFlags "|"}}{{range .ValueFlags}}|{{.}}=*{{end}})
+                __gs_i=$((__gs_i + 1))
+                ;;
+            {{join .ValueFlags "|"}})
+                __gs_i=$((__gs_i + 2))
+                ;;
+            *)
+                break
+                ;;
+        esac
+    done
+
+    case "${!__gs_i}" in
+        -?*|{{join .Commands "|"}})
+            command {{.Name}} "$@"
+            return
+            ;;
+    esac
+
+    local __gs_path
+    __gs_path="$(command {{.Name}} "$@")" || return
+    if [ -n "$__gs_path" ]; then
+        builtin cd -- "$__gs_path" || return
+    fi
+}
+
+source <(command {{.Name}} completion bash)
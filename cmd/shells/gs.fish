@@ -0,0 +1,39 @@
+# gitswitch shell integration for fish.
+# Add the following line to ~/.config/fish/config.fish:
+#
+#   {{.Name}} init fish | source
+
+function {{.Name}} --description 'gitswitch: quick and easy Git project switching'
+    # Skip the global flags, as in "gs --profile work <alias>", to find the
+    # alias or subcommand
+    set -l __gs_i 1
+    while test $__gs_i -le (count $argv)
+        switch $argv[$__gs_i]
+            case {{join .SwitchFlags " "}}{{range .ValueFlags}} '{{.}}=*'{{end}}
+                set __gs_i (math $__gs_i + 1)
+            case {{join .ValueFlags " "}}
+                set __gs_i (math $__gs_i + 2)
+            case '*'
+                break
+        end
+    end
+
+    # "gs" alone opens the picker and switches like "gs <alias>"
+    if test $__gs_i -le (count $argv)
+        switch $argv[$__gs_i]
+            case -
+                # "gs -" switches like "gs <alias>"
+            case '-*' {{join .Commands " "}}
+                command {{.Name}} $argv
+                return $status
+        end
+    end
+
+    set -l __gs_path (command {{.Name}} $argv)
+    or return $status
+    if test -n "$__gs_path"
+        builtin cd -- $__gs_path
+    end
+end
+
+command {{.Name}} completion fish | source
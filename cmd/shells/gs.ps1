@@ -0,0 +1,40 @@
+# gitswitch shell integration for PowerShell.
+# Add the following line to your $PROFILE:
+#
+#   Invoke-Expression (& {{.Name}} init pwsh | Out-String)
+
+Remove-Item -Path Alias:{{.Name}} -Force -ErrorAction SilentlyContinue
+
+function {{.Name}} {
+    $gsBinary = Get-Command -Name {{.Name}} -CommandType Application | Select-Object -First 1
+    $gsPassthrough = @({{range $i, $c := .Commands}}{{if $i}}, {{end}}'{{$c}}'{{end}})
+    $gsSwitchFlags = @({{range $i, $f := .SwitchFlags}}{{if $i}}, {{end}}'{{$f}}'{{end}})
+    $gsValueFlags = @({{range $i, $f := .ValueFlags}}{{if $i}}, {{end}}'{{$f}}'{{end}})
+
+    # Skip the global flags, as in "gs --profile work <alias>", to find the
+    # alias or subcommand
+    $gsIndex = 0
+    while ($gsIndex -lt $args.Count) {
+        $gsArg = "$($args[$gsIndex])"
+        if ($gsSwitchFlags -contains $gsArg -or ($gsValueFlags | Where-Object { $gsArg -like "$_=*" })) {
+            $gsIndex += 1
+        } elseif ($gsValueFlags -contains $gsArg) {
+            $gsIndex += 2
+        } else {
+            break
+        }
+    }
+    $gsFirst = if ($gsIndex -lt $args.Count) { "$($args[$gsIndex])" } else { '' }
+
+    if ($gsFirst -like '-?*' -or $gsPassthrough -contains $gsFirst) {
+        & $gsBinary @args
+        return
+    }
+
+    $gsPath = & $gsBinary @args
+    if ($LASTEXITCODE -eq 0 -and $gsPath) {
+        Set-Location -LiteralPath $gsPath
+    }
+}
+
+& (Get-Command -Name {{.Name}} -CommandType Application | Select-Object -First 1) completion powershell | Out-String | Invoke-Expression
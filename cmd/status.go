@@ -0,0 +1,167 @@
+//go:generate mockgen -destination=../mocks/cmd/status.go -package=mocks -source=status.go
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gs/libs"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type StatusReader interface {
+	Status(ctx context.Context, projects []libs.Entry, opts libs.StatusOptions) []libs.RepoStatus
+}
+
+// StatusSource lists the projects to read and releases the database while
+// git runs.
+type StatusSource interface {
+	AliasLister
+	DBReleaser
+}
+
+type statusItem struct {
+	Alias      string `json:"alias"`
+	Path       string `json:"path"`
+	State      string `json:"state"`
+	Branch     string `json:"branch,omitempty"`
+	Upstream   string `json:"upstream,omitempty"`
+	Ahead      int    `json:"ahead"`
+	Behind     int    `json:"behind"`
+	Dirty      int    `json:"dirty"`
+	Untracked  int    `json:"untracked"`
+	Stashes    int    `json:"stashes"`
+	LastCommit string `json:"last_commit,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func NewStatusCmd(source StatusSource, reader StatusReader) *cobra.Command {
+	var jsonOutput, dirtyOnly, behindOnly bool
+	var parallel int
+	var timeout time.Duration
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the Git status of every project",
+		Long: `Show the Git status of every stored project: the current branch, how far it
+is ahead of and behind its upstream, the number of changed and untracked
+files, the number of stashes and the age of the last commit.
+
+Projects are read concurrently and each one is given --timeout to answer.
+Projects whose directory is missing, that are not readable Git repositories
+or that time out are listed with their problem instead of a status, and are
+kept by --dirty-only and --behind-only since their state is unknown.
+
+The ahead and behind counts compare against the last fetched state of the
+upstream; run 'git fetch' (or 'gs exec -- git fetch') to refresh them.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := source.List()
+			if err != nil {
+				return errors.New("failed to list aliases")
+			}
+			// Reading many repositories can take a while
+			if err := source.Release(); err != nil {
+				return errors.New("failed to close the database")
+			}
+
+			statuses := reader.Status(cmd.Context(), entries, libs.StatusOptions{
+				Parallel: parallel,
+				Timeout:  timeout,
+			})
+			statuses = filterStatuses(statuses, dirtyOnly, behindOnly)
+
+			if jsonOutput {
+				return writeStatusJSON(cmd.OutOrStdout(), statuses)
+			}
+			return writeStatusTable(cmd.OutOrStdout(), statuses, time.Now())
+		},
+	}
+
+	statusCmd.Flags().BoolVar(&jsonOutput, "json", false, "print the statuses as a JSON array")
+	statusCmd.Flags().BoolVar(&dirtyOnly, "dirty-only", false, "only show projects with changed or untracked files")
+	statusCmd.Flags().BoolVar(&behindOnly, "behind-only", false, "only show projects behind their upstream")
+	statusCmd.Flags().IntVarP(&parallel, "parallel", "j", 8, "number of projects to read at once")
+	statusCmd.Flags().DurationVar(&timeout, "timeout", 5*time.Second, "time allowed for each project, 0 for no limit")
+	return statusCmd
+}
+
+// filterStatuses applies --dirty-only and --behind-only. Both must hold when
+// both are set.
+func filterStatuses(statuses []libs.RepoStatus, dirtyOnly, behindOnly bool) []libs.RepoStatus {
+	if !dirtyOnly && !behindOnly {
+		return statuses
+	}
+	var filtered []libs.RepoStatus
+	for _, status := range statuses {
+		keep := status.State != libs.RepoOK ||
+			((!dirtyOnly || status.Dirty+status.Untracked > 0) && (!behindOnly || status.Behind > 0))
+		if keep {
+			filtered = append(filtered, status)
+		}
+	}
+	return filtered
+}
+
+func writeStatusJSON(w io.Writer, statuses []libs.RepoStatus) error {
+	items := make([]statusItem, 0, len(statuses))
+	for _, status := range statuses {
+		item := statusItem{
+			Alias:      status.Alias,
+			Path:       status.Path,
+			State:      string(status.State),
+			Branch:     status.Branch,
+			Upstream:   status.Upstream,
+			Ahead:      status.Ahead,
+			Behind:     status.Behind,
+			Dirty:      status.Dirty,
+			Untracked:  status.Untracked,
+			Stashes:    status.Stashes,
+			LastCommit: formatTime(status.LastCommit),
+		}
+		if status.Err != nil {
+			item.Error = status.Err.Error()
+		}
+		items = append(items, item)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(items)
+}
+
+func writeStatusTable(w io.Writer, statuses []libs.RepoStatus, now time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ALIAS\tBRANCH\tUPSTREAM\tDIRTY\tUNTRACKED\tSTASH\tLAST COMMIT")
+	for _, status := range statuses {
+		if status.State != libs.RepoOK {
+			fmt.Fprintf(tw, "%s\t%s: %v\n", status.Alias, status.State, status.Err)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%d\t%s\n", status.Alias, status.Branch, formatUpstream(status),
+			status.Dirty, status.Untracked, status.Stashes, libs.FormatAge(now, status.LastCommit))
+	}
+	return tw.Flush()
+}
+
+// formatUpstream describes the distance to the upstream like git's own
+// '[ahead 1, behind 2]', shortened to fit a column.
+func formatUpstream(status libs.RepoStatus) string {
+	switch {
+	case status.Upstream == "":
+		return "-"
+	case status.Ahead == 0 && status.Behind == 0:
+		return "up to date"
+	case status.Behind == 0:
+		return fmt.Sprintf("ahead %d", status.Ahead)
+	case status.Ahead == 0:
+		return fmt.Sprintf("behind %d", status.Behind)
+	default:
+		return fmt.Sprintf("ahead %d, behind %d", status.Ahead, status.Behind)
+	}
+}
@@ -0,0 +1,166 @@
+package cmd_test
+
+import (
+	"bytes"
+	"errors"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStatusCmd(t *testing.T) {
+	entries := []libs.Entry{
+		{Alias: "api-gw", Record: libs.Record{Path: "/src/api-gw"}},
+		{Alias: "billing", Record: libs.Record{Path: "/src/billing"}},
+		{Alias: "docs", Record: libs.Record{Path: "/src/docs"}},
+		{Alias: "old", Record: libs.Record{Path: "/src/old"}},
+	}
+	lastCommit := time.Now().Add(-50 * time.Hour)
+	statuses := []libs.RepoStatus{
+		{Alias: "api-gw", Path: "/src/api-gw", State: libs.RepoOK, Branch: "main", Upstream: "origin/main",
+			Ahead: 1, Behind: 2, Dirty: 3, Stashes: 1, LastCommit: lastCommit},
+		{Alias: "billing", Path: "/src/billing", State: libs.RepoOK, Branch: "feature", Upstream: "origin/feature",
+			Behind: 4, LastCommit: lastCommit},
+		{Alias: "docs", Path: "/src/docs", State: libs.RepoOK, Branch: "main", Untracked: 2},
+		{Alias: "old", Path: "/src/old", State: libs.RepoMissing, Err: errors.New("/src/old does not exist")},
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		setupMock      func(*mocks.MockStatusSource, *mocks.MockStatusReader)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name: "successful status table",
+			args: []string{"-j", "2", "--timeout", "1s"},
+			setupMock: func(source *mocks.MockStatusSource, reader *mocks.MockStatusReader) {
+				source.EXPECT().List().Return(entries, nil)
+				release := source.EXPECT().Release().Return(nil)
+				reader.EXPECT().Status(gomock.Any(), entries, libs.StatusOptions{Parallel: 2, Timeout: time.Second}).Return(statuses).After(release)
+			},
+			expectedOutput: "ALIAS    BRANCH   UPSTREAM           DIRTY  UNTRACKED  STASH  LAST COMMIT\n" +
+				"api-gw   main     ahead 1, behind 2  3      0          1      2d ago\n" +
+				"billing  feature  behind 4           0      0          0      2d ago\n" +
+				"docs     main     -                  0      2          0      never\n" +
+				"old      missing: /src/old does not exist\n",
+		},
+		{
+			name: "successful status with dirty only",
+			args: []string{"--dirty-only"},
+			setupMock: func(source *mocks.MockStatusSource, reader *mocks.MockStatusReader) {
+				source.EXPECT().List().Return(entries, nil)
+				release := source.EXPECT().Release().Return(nil)
+				reader.EXPECT().Status(gomock.Any(), entries, libs.StatusOptions{Parallel: 8, Timeout: 5 * time.Second}).Return(statuses).After(release)
+			},
+			expectedOutput: "ALIAS   BRANCH  UPSTREAM           DIRTY  UNTRACKED  STASH  LAST COMMIT\n" +
+				"api-gw  main    ahead 1, behind 2  3      0          1      2d ago\n" +
+				"docs    main    -                  0      2          0      never\n" +
+				"old     missing: /src/old does not exist\n",
+		},
+		{
+			name: "successful status with dirty and behind only as JSON",
+			args: []string{"--dirty-only", "--behind-only", "--json"},
+			setupMock: func(source *mocks.MockStatusSource, reader *mocks.MockStatusReader) {
+				source.EXPECT().List().Return(entries[:3], nil)
+				release := source.EXPECT().Release().Return(nil)
+				reader.EXPECT().Status(gomock.Any(), entries[:3], gomock.Any()).Return(statuses[:3]).After(release)
+			},
+			expectedOutput: `[
+  {
+    "alias": "api-gw",
+    "path": "/src/api-gw",
+    "state": "ok",
+    "branch": "main",
+    "upstream": "origin/main",
+    "ahead": 1,
+    "behind": 2,
+    "dirty": 3,
+    "untracked": 0,
+    "stashes": 1,
+    "last_commit": "` + lastCommit.Format(time.RFC3339) + `"
+  }
+]
+`,
+		},
+		{
+			name: "successful status of a missing project as JSON",
+			args: []string{"--json"},
+			setupMock: func(source *mocks.MockStatusSource, reader *mocks.MockStatusReader) {
+				source.EXPECT().List().Return(entries[3:], nil)
+				release := source.EXPECT().Release().Return(nil)
+				reader.EXPECT().Status(gomock.Any(), entries[3:], gomock.Any()).Return(statuses[3:]).After(release)
+			},
+			expectedOutput: `[
+  {
+    "alias": "old",
+    "path": "/src/old",
+    "state": "missing",
+    "ahead": 0,
+    "behind": 0,
+    "dirty": 0,
+    "untracked": 0,
+    "stashes": 0,
+    "error": "/src/old does not exist"
+  }
+]
+`,
+		},
+		{
+			name: "successful status without projects as JSON",
+			args: []string{"--json"},
+			setupMock: func(source *mocks.MockStatusSource, reader *mocks.MockStatusReader) {
+				source.EXPECT().List().Return(nil, nil)
+				release := source.EXPECT().Release().Return(nil)
+				reader.EXPECT().Status(gomock.Any(), nil, gomock.Any()).Return(nil).After(release)
+			},
+			expectedOutput: "[]\n",
+		},
+		{
+			name: "failed status due to database that cannot be closed",
+			setupMock: func(source *mocks.MockStatusSource, reader *mocks.MockStatusReader) {
+				source.EXPECT().List().Return(entries, nil)
+				source.EXPECT().Release().Return(assert.AnError)
+			},
+			expectedError: "failed to close the database",
+		},
+		{
+			name: "failed status due to database error",
+			setupMock: func(source *mocks.MockStatusSource, reader *mocks.MockStatusReader) {
+				source.EXPECT().List().Return(nil, assert.AnError)
+			},
+			expectedError: "failed to list aliases",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSource := mocks.NewMockStatusSource(ctrl)
+			mockReader := mocks.NewMockStatusReader(ctrl)
+			tt.setupMock(mockSource, mockReader)
+			statusCmd := cmd.NewStatusCmd(mockSource, mockReader)
+
+			var out bytes.Buffer
+			statusCmd.SetOut(&out)
+			statusCmd.SetErr(&bytes.Buffer{})
+			statusCmd.SetArgs(tt.args)
+			err := statusCmd.Execute()
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedOutput, out.String())
+		})
+	}
+}
@@ -0,0 +1,98 @@
+//go:generate mockgen -destination=../mocks/cmd/switch.go -package=mocks -source=switch.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"gs/libs"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// previousAliasArg is the argument that switches back to the previous project.
+const previousAliasArg = "-"
+
+type AliasResolver interface {
+	Get(alias string) (string, error)
+}
+
+type AliasVisitor interface {
+	Visit(alias string) (string, error)
+	PreviousAlias() (string, error)
+}
+
+type AliasMatcher interface {
+	Match(query string) (libs.Entry, error)
+}
+
+type AliasSwitcher interface {
+	AliasVisitor
+	AliasMatcher
+}
+
+// switchToAlias prints the path of alias and records the visit. The alias "-"
+// stands for the previously visited project. Unless exact is set, an unknown
+// alias is resolved by prefix, substring or fuzzy matching.
+func switchToAlias(cmd *cobra.Command, switcher AliasSwitcher, alias string, exact bool) error {
+	if alias == previousAliasArg {
+		previous, err := switcher.PreviousAlias()
+		if err != nil {
+			return errors.New("failed to look up previous project")
+		}
+		if previous == "" {
+			return &ExitError{
+				Code: ExitCodeAliasNotFound,
+				Err:  errors.New("no previous project"),
+			}
+		}
+		alias = previous
+		exact = true
+	}
+
+	path, err := switcher.Visit(alias)
+	if errors.Is(err, libs.ErrAliasNotFound) && !exact {
+		var match libs.Entry
+		match, err = switcher.Match(alias)
+		if err == nil {
+			path, err = switcher.Visit(match.Alias)
+		}
+	}
+	var ambiguous *libs.AmbiguousAliasError
+	if errors.As(err, &ambiguous) {
+		return &ExitError{
+			Code: ExitCodeAmbiguousAlias,
+			Err:  fmt.Errorf("alias %s is ambiguous, candidates: %s", alias, strings.Join(ambiguous.Candidates, ", ")),
+		}
+	}
+	if errors.Is(err, libs.ErrAliasNotFound) {
+		return &ExitError{
+			Code: ExitCodeAliasNotFound,
+			Err:  fmt.Errorf("alias %s not found", alias),
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up alias %s", alias)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), path)
+	return nil
+}
+
+// lookupAlias returns the path stored for alias, or an ExitError carrying
+// ExitCodeAliasNotFound when nothing is stored under it.
+func lookupAlias(resolver AliasResolver, alias string) (string, error) {
+	path, err := resolver.Get(alias)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up alias %s", alias)
+	}
+
+	if path == "" {
+		return "", &ExitError{
+			Code: ExitCodeAliasNotFound,
+			Err:  fmt.Errorf("alias %s not found", alias),
+		}
+	}
+
+	return path, nil
+}
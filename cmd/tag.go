@@ -0,0 +1,117 @@
+//go:generate mockgen -destination=../mocks/cmd/tag.go -package=mocks -source=tag.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"gs/libs"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+type AliasTagger interface {
+	AliasLister
+	Tag(tag string, aliases ...string) error
+	Untag(tag string, aliases ...string) error
+}
+
+func NewTagCmd(tagger AliasTagger) *cobra.Command {
+	tagCmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Group projects with tags",
+		Long: `Group projects with tags, for example by team or product.
+
+A tagged project can be reached as 'gs <tag>/<alias>', where the alias part is
+matched among the projects with that tag only. 'gs list --tag <tag>' lists
+them.`,
+		Args: cobra.NoArgs,
+	}
+
+	tagCmd.AddCommand(newTagAddCmd(tagger))
+	tagCmd.AddCommand(newTagRemoveCmd(tagger))
+	tagCmd.AddCommand(newTagListCmd(tagger))
+	return tagCmd
+}
+
+func newTagAddCmd(tagger AliasTagger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <tag> <alias>...",
+		Short: "Add a tag to one or more aliases",
+		Long: `Add a tag to one or more aliases in a single transaction: if any of them
+does not exist, none are tagged. Tags cannot contain slashes or whitespace.`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tag, aliases := args[0], uniqueStrings(args[1:])
+			if err := tagger.Tag(tag, aliases...); err != nil {
+				return tagError(err, fmt.Sprintf("failed to tag %s with %s", strings.Join(aliases, ", "), tag))
+			}
+			return nil
+		},
+	}
+}
+
+func newTagRemoveCmd(tagger AliasTagger) *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <tag> [alias]...",
+		Aliases: []string{"rm"},
+		Short:   "Remove a tag from aliases",
+		Long: `Remove a tag from the given aliases in a single transaction, or from every
+alias that has it when no aliases are given.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tag, aliases := args[0], uniqueStrings(args[1:])
+			if err := tagger.Untag(tag, aliases...); err != nil {
+				return tagError(err, fmt.Sprintf("failed to remove tag %s", tag))
+			}
+			return nil
+		},
+	}
+}
+
+func newTagListCmd(tagger AliasTagger) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List tags and the aliases that have them",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := tagger.List()
+			if err != nil {
+				return errors.New("failed to list aliases")
+			}
+
+			tagged := map[string][]string{}
+			for _, entry := range entries {
+				for _, tag := range entry.Tags {
+					tagged[tag] = append(tagged[tag], entry.Alias)
+				}
+			}
+			tags := make([]string, 0, len(tagged))
+			for tag := range tagged {
+				tags = append(tags, tag)
+			}
+			sort.Strings(tags)
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "TAG\tALIASES")
+			for _, tag := range tags {
+				aliases := tagged[tag]
+				sort.Strings(aliases)
+				fmt.Fprintf(tw, "%s\t%s\n", tag, strings.Join(aliases, ", "))
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+// tagError keeps the typed tag errors from libs visible and otherwise
+// behaves like aliasError.
+func tagError(err error, msg string) error {
+	if errors.Is(err, libs.ErrInvalidTag) || errors.Is(err, libs.ErrTagNotFound) {
+		return err
+	}
+	return aliasError(err, msg)
+}
@@ -0,0 +1,139 @@
+package cmd_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTagCmd(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		setupMock        func(*mocks.MockAliasTagger)
+		expectedOutput   string
+		expectedError    string
+		expectedExitCode int
+	}{
+		{
+			name: "successful add to several aliases",
+			args: []string{"add", "payments", "api-gw", "billing", "api-gw"},
+			setupMock: func(tagger *mocks.MockAliasTagger) {
+				tagger.EXPECT().Tag("payments", "api-gw", "billing").Return(nil)
+			},
+		},
+		{
+			name: "failed add due to unknown alias",
+			args: []string{"add", "payments", "ghost"},
+			setupMock: func(tagger *mocks.MockAliasTagger) {
+				tagger.EXPECT().Tag("payments", "ghost").Return(fmt.Errorf("%w: ghost", libs.ErrAliasNotFound))
+			},
+			expectedError:    "alias not found: ghost",
+			expectedExitCode: cmd.ExitCodeAliasNotFound,
+		},
+		{
+			name: "failed add due to invalid tag",
+			args: []string{"add", "a/b", "api-gw"},
+			setupMock: func(tagger *mocks.MockAliasTagger) {
+				tagger.EXPECT().Tag("a/b", "api-gw").Return(fmt.Errorf("%w: %q", libs.ErrInvalidTag, "a/b"))
+			},
+			expectedError: `invalid tag: "a/b"`,
+		},
+		{
+			name: "failed add due to database error",
+			args: []string{"add", "payments", "api-gw"},
+			setupMock: func(tagger *mocks.MockAliasTagger) {
+				tagger.EXPECT().Tag("payments", "api-gw").Return(assert.AnError)
+			},
+			expectedError: "failed to tag api-gw with payments",
+		},
+		{
+			name:          "failed add due to missing alias",
+			args:          []string{"add", "payments"},
+			expectedError: "requires at least 2 arg(s), only received 1",
+		},
+		{
+			name: "successful remove from given aliases",
+			args: []string{"rm", "payments", "billing"},
+			setupMock: func(tagger *mocks.MockAliasTagger) {
+				tagger.EXPECT().Untag("payments", "billing").Return(nil)
+			},
+		},
+		{
+			name: "successful remove from every alias",
+			args: []string{"remove", "payments"},
+			setupMock: func(tagger *mocks.MockAliasTagger) {
+				tagger.EXPECT().Untag("payments").Return(nil)
+			},
+		},
+		{
+			name: "failed remove due to unknown tag",
+			args: []string{"remove", "payment"},
+			setupMock: func(tagger *mocks.MockAliasTagger) {
+				tagger.EXPECT().Untag("payment").Return(fmt.Errorf("%w: payment", libs.ErrTagNotFound))
+			},
+			expectedError: "tag not found: payment",
+		},
+		{
+			name: "successful list",
+			args: []string{"list"},
+			setupMock: func(tagger *mocks.MockAliasTagger) {
+				tagger.EXPECT().List().Return([]libs.Entry{
+					{Alias: "billing", Record: libs.Record{Tags: []string{"payments"}}},
+					{Alias: "api-gw", Record: libs.Record{Tags: []string{"payments", "platform"}}},
+					{Alias: "docs"},
+				}, nil)
+			},
+			expectedOutput: "TAG       ALIASES\n" +
+				"payments  api-gw, billing\n" +
+				"platform  api-gw\n",
+		},
+		{
+			name: "failed list due to database error",
+			args: []string{"ls"},
+			setupMock: func(tagger *mocks.MockAliasTagger) {
+				tagger.EXPECT().List().Return(nil, assert.AnError)
+			},
+			expectedError: "failed to list aliases",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTagger := mocks.NewMockAliasTagger(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(mockTagger)
+			}
+			tagCmd := cmd.NewTagCmd(mockTagger)
+
+			var out bytes.Buffer
+			tagCmd.SetOut(&out)
+			tagCmd.SetErr(&bytes.Buffer{})
+			tagCmd.SetArgs(tt.args)
+			err := tagCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+				return
+			}
+
+			assert.EqualError(t, err, tt.expectedError)
+			if tt.expectedExitCode != 0 {
+				var exitErr *cmd.ExitError
+				assert.True(t, errors.As(err, &exitErr))
+				assert.Equal(t, tt.expectedExitCode, exitErr.Code)
+			}
+		})
+	}
+}
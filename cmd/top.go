@@ -0,0 +1,60 @@
+//go:generate mockgen -destination=../mocks/cmd/top.go -package=mocks -source=top.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"gs/libs"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+type AliasRanker interface {
+	Top(limit int) ([]libs.ScoredEntry, error)
+}
+
+var topOutputFormats = []string{"table", "plain"}
+
+func NewTopCmd(ranker AliasRanker) *cobra.Command {
+	var limit int
+	var output string
+
+	topCmd := &cobra.Command{
+		Use:   "top",
+		Short: "List the most frequently and recently used projects",
+		Long: `List the projects you switch to most, ranked by frecency: a mix of how often
+and how recently each one was visited with 'gs <alias>'. Projects that have
+not been used for a long time gradually drop out of the ranking.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !contains(topOutputFormats, output) {
+				return fmt.Errorf("invalid output format %q, must be one of %v", output, topOutputFormats)
+			}
+
+			ranked, err := ranker.Top(limit)
+			if err != nil {
+				return errors.New("failed to rank aliases")
+			}
+
+			out := cmd.OutOrStdout()
+			if output == "plain" {
+				for _, entry := range ranked {
+					fmt.Fprintf(out, "%s\t%s\n", entry.Alias, entry.Path)
+				}
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "SCORE\tALIAS\tPATH\tUSES")
+			for _, entry := range ranked {
+				fmt.Fprintf(tw, "%.1f\t%s\t%s\t%d\n", entry.Score, entry.Alias, entry.Path, entry.UseCount)
+			}
+			return tw.Flush()
+		},
+	}
+
+	topCmd.Flags().IntVarP(&limit, "limit", "n", 10, "number of projects to show, 0 for all")
+	topCmd.Flags().StringVarP(&output, "output", "o", "table", "output format: table or plain")
+	return topCmd
+}
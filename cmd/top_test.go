@@ -0,0 +1,83 @@
+package cmd_test
+
+import (
+	"bytes"
+	"gs/cmd"
+	"gs/libs"
+	mocks "gs/mocks/cmd"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTopCmd(t *testing.T) {
+	ranked := []libs.ScoredEntry{
+		{Entry: libs.Entry{Alias: "api", Record: libs.Record{Path: "/src/api", UseCount: 12}}, Score: 48},
+		{Entry: libs.Entry{Alias: "web", Record: libs.Record{Path: "/src/web", UseCount: 3}}, Score: 1.5},
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		mockTop        MockCall[[]libs.ScoredEntry]
+		limit          int
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:    "successful table with default limit",
+			args:    []string{},
+			mockTop: MockCall[[]libs.ScoredEntry]{Times: 1, Response: ranked},
+			limit:   10,
+			expectedOutput: "SCORE  ALIAS  PATH      USES\n" +
+				"48.0   api    /src/api  12\n" +
+				"1.5    web    /src/web  3\n",
+		},
+		{
+			name:           "successful plain with limit",
+			args:           []string{"-n", "1", "-o", "plain"},
+			mockTop:        MockCall[[]libs.ScoredEntry]{Times: 1, Response: ranked[:1]},
+			limit:          1,
+			expectedOutput: "api\t/src/api\n",
+		},
+		{
+			name:          "failed due to invalid output format",
+			args:          []string{"-o", "json"},
+			expectedError: `invalid output format "json", must be one of [table plain]`,
+		},
+		{
+			name:          "failed due to database error",
+			args:          []string{},
+			mockTop:       MockCall[[]libs.ScoredEntry]{Times: 1, Error: assert.AnError},
+			limit:         10,
+			expectedError: "failed to rank aliases",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRanker := mocks.NewMockAliasRanker(ctrl)
+			if tt.mockTop.Times > 0 {
+				mockRanker.EXPECT().Top(tt.limit).Return(tt.mockTop.Response, tt.mockTop.Error).Times(tt.mockTop.Times)
+			}
+			topCmd := cmd.NewTopCmd(mockRanker)
+
+			var out bytes.Buffer
+			topCmd.SetOut(&out)
+			topCmd.SetErr(&bytes.Buffer{})
+			topCmd.SetArgs(tt.args)
+			err := topCmd.Execute()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, out.String())
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,586 @@
+package libs
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrUnknownConfigKey = errors.New("unknown config key")
+
+// ConfigFileEnv names the environment variable that points gs at a config
+// file, overriding the search.
+const ConfigFileEnv = "GS_CONFIG"
+
+// ConfigEnvPrefix is prepended to a key, upper-cased with dots replaced by
+// underscores, to override it from the environment: GS_PICK_FINDER overrides
+// pick.finder.
+const ConfigEnvPrefix = "GS"
+
+// ConfigFileName is the file gs looks for in each config directory.
+const ConfigFileName = "config.yaml"
+
+// previewKeyPrefix starts the keys holding a preview command per finder.
+// Any finder name may follow it, not only the KnownFinders.
+const previewKeyPrefix = "pick.preview."
+
+// ConfigKey describes a setting gs understands.
+type ConfigKey struct {
+	Name        string
+	Description string
+	// Flag is the global flag that overrides the key, when it should not be
+	// derived from the name.
+	Flag string
+	// Env is an environment variable that overrides the key besides the one
+	// derived from the name.
+	Env string
+	// List keys hold several values, written comma-separated on the command
+	// line and in environment variables.
+	List bool
+}
+
+// ConfigKeys lists every setting, in the order gs config list shows them.
+var ConfigKeys = configKeys()
+
+func configKeys() []ConfigKey {
+	keys := []ConfigKey{
+		{Name: "app_name", Description: "name of the directory under $XDG_DATA_HOME that holds the database"},
+		{Name: "author", Description: "author of the configuration, informational only"},
+		{Name: "db_path", Flag: "db", Description: "database file, by default $XDG_DATA_HOME/<app_name>/bbolt.db"},
+		{Name: "default_profile", Flag: "profile", Env: "GS_PROFILE", Description: "profile whose aliases gs works on"},
+		{Name: "kv_bucket_name", Description: "database bucket holding the aliases"},
+		{Name: "profile_rules", List: true, Description: "rules <dir>=<profile> choosing the profile for commands run under <dir>, the deepest <dir> wins"},
+		{Name: "pick.finder", Description: "fuzzy finder for 'gs' and 'gs pick': fzf, sk, peco or builtin"},
+	}
+	for _, finder := range KnownFinders {
+		keys = append(keys, ConfigKey{
+			Name:        previewKeyPrefix + finder,
+			Description: fmt.Sprintf("preview command for %s, where {2} is the project path", finder),
+		})
+	}
+	return append(keys, ConfigKey{
+		Name:        "scan.ignore",
+		Description: "directory name patterns 'gs scan' and 'gs relocate' do not descend into",
+		List:        true,
+	})
+}
+
+// ConfigDefaults holds the settings gs uses when nothing else sets them.
+var ConfigDefaults = map[string]any{
+	"app_name":        "gs",
+	"author":          "",
+	"db_path":         "",
+	"default_profile": DefaultProfile,
+	"kv_bucket_name":  "gs",
+	"profile_rules":   []string{},
+	"pick.finder":     "",
+	"scan.ignore":     DefaultScanIgnore,
+}
+
+// LookupConfigKey returns the setting named name.
+func LookupConfigKey(name string) (ConfigKey, bool) {
+	name = strings.ToLower(name)
+	for _, key := range ConfigKeys {
+		if key.Name == name {
+			return key, true
+		}
+	}
+	if finder, ok := strings.CutPrefix(name, previewKeyPrefix); ok && finder != "" && !strings.Contains(finder, ".") {
+		return ConfigKey{Name: name, Description: fmt.Sprintf("preview command for %s", finder)}, true
+	}
+	return ConfigKey{}, false
+}
+
+// SuggestConfigKey returns the known key closest to a misspelled name, or ""
+// when none is close enough.
+func SuggestConfigKey(name string) string {
+	name = strings.ToLower(name)
+	best, bestDistance := "", max(2, len(name)/3)+1
+	for _, key := range ConfigKeys {
+		d := editDistance(name, key.Name)
+		// A key given without its section, as in "finder", or cut short
+		if _, last, ok := strings.Cut(key.Name, "."); ok && !strings.Contains(name, ".") {
+			d = min(d, editDistance(name, last))
+		}
+		if name != "" && strings.HasPrefix(key.Name, name) {
+			d = min(d, 1)
+		}
+		if d < bestDistance {
+			best, bestDistance = key.Name, d
+		}
+	}
+	return best
+}
+
+func unknownKeyError(name string) error {
+	if suggestion := SuggestConfigKey(name); suggestion != "" {
+		return fmt.Errorf("%w %q, did you mean %q?", ErrUnknownConfigKey, name, suggestion)
+	}
+	return fmt.Errorf("%w %q", ErrUnknownConfigKey, name)
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// Config is the typed form of the settings.
+type Config struct {
+	AppName        string     `mapstructure:"app_name"`
+	Author         string     `mapstructure:"author"`
+	DBPath         string     `mapstructure:"db_path"`
+	DefaultProfile string     `mapstructure:"default_profile"`
+	KVBucketName   string     `mapstructure:"kv_bucket_name"`
+	ProfileRules   []string   `mapstructure:"profile_rules"`
+	Pick           PickConfig `mapstructure:"pick"`
+	Scan           ScanConfig `mapstructure:"scan"`
+}
+
+type PickConfig struct {
+	Finder string `mapstructure:"finder"`
+	// Preview maps a finder to its preview command.
+	Preview map[string]string `mapstructure:"preview"`
+}
+
+type ScanConfig struct {
+	Ignore []string `mapstructure:"ignore"`
+}
+
+var appNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Validate reports every setting that gs cannot work with.
+func (c Config) Validate() error {
+	var errs []error
+	if !appNamePattern.MatchString(c.AppName) {
+		errs = append(errs, fmt.Errorf("app_name %q must start with a letter or digit and contain only letters, digits, '.', '_' and '-'", c.AppName))
+	}
+	switch c.KVBucketName {
+	case "":
+		errs = append(errs, errors.New("kv_bucket_name must not be empty"))
+	case MetaBucketName:
+		errs = append(errs, fmt.Errorf("kv_bucket_name %q is reserved", c.KVBucketName))
+	}
+	if err := ValidateProfile(c.DefaultProfile); err != nil {
+		errs = append(errs, fmt.Errorf("default_profile %q must start with a letter or digit and contain only letters, digits, '.', '_' and '-'", c.DefaultProfile))
+	}
+	if _, err := ParseProfileRules(c.ProfileRules); err != nil {
+		errs = append(errs, err)
+	}
+	if strings.TrimSpace(c.Pick.Finder) != c.Pick.Finder {
+		errs = append(errs, fmt.Errorf("pick.finder %q must not start or end with spaces", c.Pick.Finder))
+	}
+	for _, pattern := range c.Scan.Ignore {
+		if _, err := filepath.Match(pattern, ""); pattern == "" || err != nil {
+			errs = append(errs, fmt.Errorf("scan.ignore pattern %q is not a valid pattern", pattern))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ConfigService loads, reads and writes the configuration of gs.
+type ConfigService struct {
+	v        *viper.Viper
+	file     string
+	searched []string
+	config   Config
+}
+
+// ConfigSearchPaths returns the files gs loads its config from, in order:
+// $GS_CONFIG, $XDG_CONFIG_HOME/gs/config.yaml (~/.config when unset),
+// ~/.gs/config.yaml and config.yaml in the current directory.
+func ConfigSearchPaths() []string {
+	var paths []string
+	if file := os.Getenv(ConfigFileEnv); file != "" {
+		paths = append(paths, file)
+	}
+
+	home, homeErr := os.UserHomeDir()
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	// The XDG spec says to ignore relative paths
+	if !filepath.IsAbs(configHome) {
+		configHome = ""
+		if homeErr == nil {
+			configHome = filepath.Join(home, ".config")
+		}
+	}
+	if configHome != "" {
+		paths = append(paths, filepath.Join(configHome, "gs", ConfigFileName))
+	}
+	if homeErr == nil {
+		paths = append(paths, filepath.Join(home, ".gs", ConfigFileName))
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		paths = append(paths, filepath.Join(wd, ConfigFileName))
+	} else {
+		paths = append(paths, ConfigFileName)
+	}
+	return paths
+}
+
+// newConfigViper returns a viper that knows every key, so that each can be
+// overridden from the environment, and falls back to ConfigDefaults.
+func newConfigViper(v *viper.Viper, env bool) *viper.Viper {
+	for key, value := range ConfigDefaults {
+		v.SetDefault(key, value)
+	}
+	for _, finder := range KnownFinders {
+		v.SetDefault(previewKeyPrefix+finder, "")
+	}
+	v.SetConfigType("yaml")
+	if env {
+		v.SetEnvPrefix(ConfigEnvPrefix)
+		v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+		v.AutomaticEnv()
+		for _, key := range ConfigKeys {
+			if key.Env != "" {
+				_ = v.BindEnv(key.Name, key.Env)
+			}
+		}
+	}
+	return v
+}
+
+// LoadConfig reads the first existing file of ConfigSearchPaths into v on
+// top of ConfigDefaults, with GS_* environment variables and any flags bound
+// to v taking precedence, and validates the result. Finding no file is not an
+// error, but a $GS_CONFIG that does not exist is, since the user asked for
+// that file explicitly. When the file is missing in that way, cannot be
+// parsed or holds invalid settings, the error comes with a ConfigService that
+// still reads and writes the file, so that it can be fixed with gs.
+func LoadConfig(v *viper.Viper) (*ConfigService, error) {
+	config := &ConfigService{v: newConfigViper(v, true), searched: ConfigSearchPaths()}
+	explicit := os.Getenv(ConfigFileEnv) != ""
+	for i, path := range config.searched {
+		_, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			if explicit && i == 0 {
+				return config, fmt.Errorf("%s: %w", path, err)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		config.file = path
+		break
+	}
+
+	if config.file != "" {
+		v.SetConfigFile(config.file)
+		if err := v.ReadInConfig(); err != nil {
+			return config, fmt.Errorf("%s: %w", config.file, err)
+		}
+	}
+	if err := v.Unmarshal(&config.config); err != nil {
+		return config, err
+	}
+	if err := config.config.Validate(); err != nil {
+		if config.file == "" {
+			return config, err
+		}
+		return config, fmt.Errorf("%s: %w", config.file, err)
+	}
+	return config, nil
+}
+
+// Config returns the settings in effect.
+func (c *ConfigService) Config() Config {
+	return c.config
+}
+
+// File returns the config file that was loaded, or "" when gs runs on the
+// built-in defaults.
+func (c *ConfigService) File() string {
+	return c.file
+}
+
+// SearchPaths returns the files that were looked for, in order.
+func (c *ConfigService) SearchPaths() []string {
+	return c.searched
+}
+
+// WritePath returns the file that Set and Edit write to: the loaded file, or
+// where gs looks first when there is none.
+func (c *ConfigService) WritePath() string {
+	if c.file != "" || len(c.searched) == 0 {
+		return c.file
+	}
+	return c.searched[0]
+}
+
+// Get returns the value in effect for key, with list values comma-separated.
+func (c *ConfigService) Get(name string) (string, error) {
+	key, ok := LookupConfigKey(name)
+	if !ok {
+		return "", unknownKeyError(name)
+	}
+	if key.List {
+		return strings.Join(c.v.GetStringSlice(key.Name), ","), nil
+	}
+	return c.v.GetString(key.Name), nil
+}
+
+// Keys returns the known keys and any preview keys set for other finders.
+func (c *ConfigService) Keys() []string {
+	keys := make([]string, 0, len(ConfigKeys))
+	seen := map[string]bool{}
+	for _, key := range ConfigKeys {
+		keys = append(keys, key.Name)
+		seen[key.Name] = true
+	}
+	var extra []string
+	for _, name := range c.v.AllKeys() {
+		if key, ok := LookupConfigKey(name); ok && !seen[key.Name] {
+			extra = append(extra, key.Name)
+		}
+	}
+	sort.Strings(extra)
+	return append(keys, extra...)
+}
+
+// UnknownKeys returns the keys in the config file that gs does not
+// understand, which are most likely misspelled.
+func (c *ConfigService) UnknownKeys() ([]string, error) {
+	if c.file == "" {
+		return nil, nil
+	}
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.SetConfigFile(c.file)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("%s: %w", c.file, err)
+	}
+	var unknown []string
+	for _, name := range v.AllKeys() {
+		if _, ok := LookupConfigKey(name); !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// Set validates value for key and writes it to the config file, creating
+// the file if needed. Comments and the order of the other keys are kept.
+func (c *ConfigService) Set(name, value string) error {
+	key, ok := LookupConfigKey(name)
+	if !ok {
+		return unknownKeyError(name)
+	}
+	path := c.WritePath()
+
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if key.List {
+		node = &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range splitList(value) {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+		}
+	}
+	if err := setYAMLPath(doc.Content[0], strings.Split(key.Name, "."), node); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if err := validateConfigData(out.Bytes()); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, out.Bytes(), 0644)
+}
+
+// splitList splits a comma-separated list value, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// setYAMLPath sets the value at path below the mapping node, creating the
+// mappings on the way. A replaced node keeps its comments.
+func setYAMLPath(mapping *yaml.Node, path []string, value *yaml.Node) error {
+	if mapping.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping", mapping.Line)
+	}
+	for i := 0; i < len(mapping.Content)-1; i += 2 {
+		if !strings.EqualFold(mapping.Content[i].Value, path[0]) {
+			continue
+		}
+		current := mapping.Content[i+1]
+		if len(path) > 1 {
+			return setYAMLPath(current, path[1:], value)
+		}
+		value.HeadComment, value.LineComment, value.FootComment = current.HeadComment, current.LineComment, current.FootComment
+		*current = *value
+		return nil
+	}
+
+	for _, name := range path[:len(path)-1] {
+		child := &yaml.Node{Kind: yaml.MappingNode}
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, child)
+		mapping = child
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: path[len(path)-1]}, value)
+	return nil
+}
+
+// validateConfigData checks config file contents on top of the defaults,
+// without environment variables or flags.
+func validateConfigData(data []byte) error {
+	v := newConfigViper(viper.New(), false)
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		return err
+	}
+	return config.Validate()
+}
+
+// Edit opens a copy of the config file in $VISUAL or $EDITOR, vi when
+// neither is set, and checks it once the editor exits. Only a valid copy
+// replaces the file, which is created if needed. After an invalid edit, Edit
+// asks on stderr whether to reopen the editor and leaves the file untouched
+// unless the answer is yes or empty.
+func (c *ConfigService) Edit(stdin io.Reader, stdout, stderr io.Writer) error {
+	path := c.WritePath()
+	mode := fs.FileMode(0644)
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		data = []byte("# gs configuration, run 'gs config list' to see the available keys\n")
+	case err != nil:
+		return err
+	default:
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+	}
+
+	// The copy keeps the file name so that editors recognise it as YAML
+	dir, err := os.MkdirTemp("", "gs-config-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	draft := filepath.Join(dir, ConfigFileName)
+	if err := os.WriteFile(draft, data, 0600); err != nil {
+		return err
+	}
+
+	var answers *bufio.Reader
+	if stdin != nil {
+		answers = bufio.NewReader(stdin)
+	}
+	for {
+		if err := runEditor(draft, stdin, stdout, stderr); err != nil {
+			return err
+		}
+		if data, err = os.ReadFile(draft); err != nil {
+			return err
+		}
+		invalid := validateConfigData(data)
+		if invalid == nil {
+			break
+		}
+
+		fmt.Fprintf(stderr, "%s: %v\nEdit again? [Y/n] ", path, invalid)
+		answer, err := "", io.EOF
+		if answers != nil {
+			answer, err = answers.ReadString('\n')
+		}
+		reply := strings.ToLower(strings.TrimSpace(answer))
+		// Without input there is nobody to edit again
+		if reply == "y" || reply == "yes" || (reply == "" && err == nil) {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintln(stderr)
+		}
+		return fmt.Errorf("%s was not changed: %w", path, invalid)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, mode); err != nil {
+		return err
+	}
+	c.file = path
+	return nil
+}
+
+// runEditor opens path in $VISUAL or $EDITOR, vi when neither is set.
+func runEditor(path string, stdin io.Reader, stdout, stderr io.Writer) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// Editors are often configured with arguments, as in "code --wait"
+	args := append(strings.Fields(editor), path)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", editor, err)
+	}
+	return nil
+}
@@ -0,0 +1,429 @@
+package libs_test
+
+import (
+	"bytes"
+	"gs/libs"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	writeConfig := func(t *testing.T, path, bucket string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("kv_bucket_name: "+bucket+"\n"), 0644))
+	}
+
+	tests := []struct {
+		name       string
+		xdg        bool
+		files      map[string]string
+		gsConfig   string
+		wantFile   string
+		wantBucket string
+		wantError  bool
+	}{
+		{
+			name:       "built-in defaults without a file",
+			wantBucket: "gs",
+		},
+		{
+			name:       "current directory",
+			files:      map[string]string{"work/config.yaml": "cwd"},
+			wantFile:   "work/config.yaml",
+			wantBucket: "cwd",
+		},
+		{
+			name:       "home directory before current directory",
+			files:      map[string]string{"home/.gs/config.yaml": "home", "work/config.yaml": "cwd"},
+			wantFile:   "home/.gs/config.yaml",
+			wantBucket: "home",
+		},
+		{
+			name:       "default XDG directory before home directory",
+			files:      map[string]string{"home/.config/gs/config.yaml": "xdg", "home/.gs/config.yaml": "home"},
+			wantFile:   "home/.config/gs/config.yaml",
+			wantBucket: "xdg",
+		},
+		{
+			name:       "XDG_CONFIG_HOME",
+			xdg:        true,
+			files:      map[string]string{"xdg/gs/config.yaml": "xdg", "home/.config/gs/config.yaml": "ignored"},
+			wantFile:   "xdg/gs/config.yaml",
+			wantBucket: "xdg",
+		},
+		{
+			name:       "GS_CONFIG before everything else",
+			xdg:        true,
+			files:      map[string]string{"custom.yml": "custom", "xdg/gs/config.yaml": "xdg"},
+			gsConfig:   "custom.yml",
+			wantFile:   "custom.yml",
+			wantBucket: "custom",
+		},
+		{
+			name:      "missing GS_CONFIG",
+			files:     map[string]string{"home/.gs/config.yaml": "home"},
+			gsConfig:  "missing.yaml",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(root, "work"), 0755))
+			t.Setenv("HOME", filepath.Join(root, "home"))
+			t.Setenv("XDG_CONFIG_HOME", "")
+			if tt.xdg {
+				t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+			}
+			t.Setenv(libs.ConfigFileEnv, "")
+			if tt.gsConfig != "" {
+				t.Setenv(libs.ConfigFileEnv, filepath.Join(root, tt.gsConfig))
+			}
+			t.Chdir(filepath.Join(root, "work"))
+			for path, bucket := range tt.files {
+				writeConfig(t, filepath.Join(root, path), bucket)
+			}
+
+			v := viper.New()
+			config, err := libs.LoadConfig(v)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			wantFile := ""
+			if tt.wantFile != "" {
+				wantFile = filepath.Join(root, tt.wantFile)
+			}
+			assert.Equal(t, wantFile, config.File())
+			assert.Equal(t, tt.wantBucket, v.GetString("kv_bucket_name"))
+			assert.Equal(t, "gs", v.GetString("app_name"))
+		})
+	}
+
+	t.Run("search order", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv("HOME", filepath.Join(root, "home"))
+		t.Setenv("XDG_CONFIG_HOME", "relative/ignored")
+		t.Setenv(libs.ConfigFileEnv, filepath.Join(root, "custom.yaml"))
+		t.Chdir(root)
+
+		assert.Equal(t, []string{
+			filepath.Join(root, "custom.yaml"),
+			filepath.Join(root, "home", ".config", "gs", "config.yaml"),
+			filepath.Join(root, "home", ".gs", "config.yaml"),
+			filepath.Join(root, "config.yaml"),
+		}, libs.ConfigSearchPaths())
+	})
+}
+
+// loadConfigFile loads path as the only config file, with a clean
+// environment.
+func loadConfigFile(t *testing.T, path string) (*libs.ConfigService, *viper.Viper) {
+	t.Helper()
+	t.Setenv(libs.ConfigFileEnv, path)
+	t.Setenv("HOME", t.TempDir())
+	v := viper.New()
+	config, err := libs.LoadConfig(v)
+	require.NoError(t, err)
+	return config, v
+}
+
+// loadWithoutConfigFile loads the config where there is no config file and
+// returns the file that gs would create.
+func loadWithoutConfigFile(t *testing.T) (*libs.ConfigService, string) {
+	t.Helper()
+	root := t.TempDir()
+	t.Setenv(libs.ConfigFileEnv, "")
+	t.Setenv("HOME", filepath.Join(root, "home"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+	t.Chdir(root)
+	config, err := libs.LoadConfig(viper.New())
+	require.NoError(t, err)
+	require.Equal(t, "", config.File())
+	return config, filepath.Join(root, "xdg", "gs", "config.yaml")
+}
+
+func TestLoadConfig_Overrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("kv_bucket_name: file\npick:\n  finder: sk\n  preview:\n    fzf: cat {2}\n    mine: tree {2}\n"), 0644))
+	t.Setenv("GS_KV_BUCKET_NAME", "env")
+	t.Setenv("GS_SCAN_IGNORE", "dist,.*")
+	t.Setenv("GS_PICK_FINDER", "fzf")
+
+	config, v := loadConfigFile(t, path)
+	flags := pflag.NewFlagSet("gs", pflag.ContinueOnError)
+	flags.String("pick-finder", "", "")
+	require.NoError(t, flags.Parse([]string{"--pick-finder", "peco"}))
+	require.NoError(t, v.BindPFlag("pick.finder", flags.Lookup("pick-finder")))
+
+	assert.Equal(t, libs.Config{
+		AppName:        "gs",
+		DefaultProfile: "default",
+		KVBucketName:   "env",
+		ProfileRules:   []string{},
+		Pick: libs.PickConfig{
+			Finder:  "fzf",
+			Preview: map[string]string{"fzf": "cat {2}", "sk": "", "peco": "", "mine": "tree {2}"},
+		},
+		Scan: libs.ScanConfig{Ignore: []string{"dist", ".*"}},
+	}, config.Config())
+
+	finder, err := config.Get("pick.finder")
+	assert.NoError(t, err)
+	assert.Equal(t, "peco", finder)
+	ignore, err := config.Get("SCAN.IGNORE")
+	assert.NoError(t, err)
+	assert.Equal(t, "dist,.*", ignore)
+	_, err = config.Get("pick.findr")
+	assert.EqualError(t, err, `unknown config key "pick.findr", did you mean "pick.finder"?`)
+
+	assert.Equal(t, []string{
+		"app_name", "author", "db_path", "default_profile", "kv_bucket_name", "profile_rules", "pick.finder",
+		"pick.preview.fzf", "pick.preview.sk", "pick.preview.peco", "scan.ignore",
+		"pick.preview.mine",
+	}, config.Keys())
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := libs.Config{AppName: "gs", DefaultProfile: "default", KVBucketName: "gs", Scan: libs.ScanConfig{Ignore: []string{".*", "node_modules"}}}
+
+	tests := []struct {
+		name   string
+		modify func(*libs.Config)
+		want   string
+	}{
+		{name: "valid", modify: func(c *libs.Config) {}},
+		{name: "empty bucket", modify: func(c *libs.Config) { c.KVBucketName = "" }, want: "kv_bucket_name must not be empty"},
+		{name: "reserved bucket", modify: func(c *libs.Config) { c.KVBucketName = libs.MetaBucketName }, want: `kv_bucket_name "__gs_meta" is reserved`},
+		{name: "app name with separator", modify: func(c *libs.Config) { c.AppName = "../gs" }, want: `app_name "../gs" must start with a letter or digit and contain only letters, digits, '.', '_' and '-'`},
+		{name: "profile with separator", modify: func(c *libs.Config) { c.DefaultProfile = "work/a" }, want: `default_profile "work/a" must start with a letter or digit and contain only letters, digits, '.', '_' and '-'`},
+		{name: "valid profile rules", modify: func(c *libs.Config) { c.ProfileRules = []string{"/src/work=work", "/src/work/oss=oss"} }},
+		{
+			name: "invalid profile rules",
+			modify: func(c *libs.Config) {
+				c.ProfileRules = []string{"/src/work", "work=work", "/src/a=a/b", "/src/a=a", "/src/a/=b"}
+			},
+			want: `profile_rules entry "/src/work" must have the form <dir>=<profile>` + "\n" +
+				`profile_rules entry "work=work": directory must be absolute or start with ~` + "\n" +
+				`profile_rules entry "/src/a=a/b": invalid profile name: "a/b"` + "\n" +
+				`profile_rules entry "/src/a/=b" repeats the directory /src/a`,
+		},
+		{name: "padded finder", modify: func(c *libs.Config) { c.Pick.Finder = "fzf " }, want: `pick.finder "fzf " must not start or end with spaces`},
+		{
+			name:   "every problem",
+			modify: func(c *libs.Config) { c.AppName = ""; c.Scan.Ignore = []string{"[", ""} },
+			want: `app_name "" must start with a letter or digit and contain only letters, digits, '.', '_' and '-'` + "\n" +
+				`scan.ignore pattern "[" is not a valid pattern` + "\n" +
+				`scan.ignore pattern "" is not a valid pattern`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.modify(&config)
+			err := config.Validate()
+			if tt.want == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.want)
+			}
+		})
+	}
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("kv_bucket_name: \"\"\n"), 0644))
+		t.Setenv(libs.ConfigFileEnv, path)
+		config, err := libs.LoadConfig(viper.New())
+		assert.EqualError(t, err, path+": kv_bucket_name must not be empty")
+		// The file can still be fixed through the returned service
+		require.NotNil(t, config)
+		assert.Equal(t, path, config.File())
+		assert.NoError(t, config.Set("kv_bucket_name", "gs"))
+	})
+
+	t.Run("unparsable file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("pick: [\n"), 0644))
+		t.Setenv(libs.ConfigFileEnv, path)
+		config, err := libs.LoadConfig(viper.New())
+		assert.ErrorContains(t, err, path+": ")
+		require.NotNil(t, config)
+		assert.Equal(t, path, config.WritePath())
+	})
+}
+
+func TestConfigService_Set(t *testing.T) {
+	original := "# gs settings\napp_name: gs # do not change\npick:\n  # finder for gs pick\n  finder: \"\"\n"
+
+	tests := []struct {
+		name      string
+		content   *string
+		key       string
+		value     string
+		want      string
+		wantError string
+	}{
+		{
+			name:    "existing key keeps comments",
+			content: &original,
+			key:     "pick.finder",
+			value:   "fzf",
+			want:    "# gs settings\napp_name: gs # do not change\npick:\n  # finder for gs pick\n  finder: fzf\n",
+		},
+		{
+			name:    "new nested list key",
+			content: &original,
+			key:     "scan.ignore",
+			value:   ".*, dist",
+			want:    original + "scan:\n  ignore:\n    - .*\n    - dist\n",
+		},
+		{
+			name:  "new file",
+			key:   "kv_bucket_name",
+			value: "123",
+			want:  "kv_bucket_name: \"123\"\n",
+		},
+		{
+			name:      "invalid value",
+			content:   &original,
+			key:       "app_name",
+			value:     "a/b",
+			want:      original,
+			wantError: `app_name "a/b" must start with a letter or digit and contain only letters, digits, '.', '_' and '-'`,
+		},
+		{
+			name:      "misspelled key",
+			content:   &original,
+			key:       "scan.ignor",
+			want:      original,
+			wantError: `unknown config key "scan.ignor", did you mean "scan.ignore"?`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config *libs.ConfigService
+			var path string
+			if tt.content != nil {
+				path = filepath.Join(t.TempDir(), "config.yaml")
+				require.NoError(t, os.WriteFile(path, []byte(*tt.content), 0644))
+				config, _ = loadConfigFile(t, path)
+			} else {
+				config, path = loadWithoutConfigFile(t)
+			}
+
+			err := config.Set(tt.key, tt.value)
+			if tt.wantError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantError)
+			}
+			data, _ := os.ReadFile(path)
+			assert.Equal(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestConfigService_UnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("kv_bucket: gs\npick:\n  findr: fzf\n  preview:\n    mine: cat\n"), 0644))
+	config, _ := loadConfigFile(t, path)
+
+	unknown, err := config.UnknownKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kv_bucket", "pick.findr"}, unknown)
+}
+
+func TestSuggestConfigKey(t *testing.T) {
+	tests := map[string]string{
+		"pick.findr":  "pick.finder",
+		"kv_bucket":   "kv_bucket_name",
+		"finder":      "pick.finder",
+		"Scan.Ignore": "scan.ignore",
+		"colour":      "",
+		"":            "",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, libs.SuggestConfigKey(name), name)
+	}
+}
+
+// answerFile returns a file to use as stdin for Edit. The editor inherits a
+// file instead of draining it as it would a pipe fed from a reader.
+func answerFile(t *testing.T, answers string) *os.File {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "answers")
+	require.NoError(t, os.WriteFile(path, []byte(answers), 0644))
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestConfigService_Edit(t *testing.T) {
+	// The editor adds the bucket on the first run and fixes it on later ones
+	editor := filepath.Join(t.TempDir(), "editor")
+	require.NoError(t, os.WriteFile(editor, []byte("#!/bin/sh\n"+
+		"if grep -q kv_bucket_name \"$1\"; then sed -i 's/^kv_bucket_name: .*/kv_bucket_name: fixed/' \"$1\"\n"+
+		"else printf 'kv_bucket_name: %s\\n' \"$GS_TEST_BUCKET\" >> \"$1\"; fi\n"), 0755))
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", editor)
+
+	t.Run("creates the file", func(t *testing.T) {
+		config, path := loadWithoutConfigFile(t)
+		t.Setenv("GS_TEST_BUCKET", "work")
+
+		assert.NoError(t, config.Edit(nil, io.Discard, io.Discard))
+		data, _ := os.ReadFile(path)
+		assert.Contains(t, string(data), "kv_bucket_name: work\n")
+		assert.Equal(t, path, config.File())
+	})
+
+	t.Run("keeps the file after an invalid result", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("# mine\n"), 0600))
+		config, _ := loadConfigFile(t, path)
+		t.Setenv("GS_TEST_BUCKET", `""`)
+
+		var stderr bytes.Buffer
+		err := config.Edit(answerFile(t, "n\n"), io.Discard, &stderr)
+		assert.EqualError(t, err, path+" was not changed: kv_bucket_name must not be empty")
+		assert.Equal(t, path+": kv_bucket_name must not be empty\nEdit again? [Y/n] ", stderr.String())
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, "# mine\n", string(data))
+	})
+
+	t.Run("edits again after an invalid result", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("# mine\n"), 0600))
+		config, _ := loadConfigFile(t, path)
+		t.Setenv("GS_TEST_BUCKET", `""`)
+
+		assert.NoError(t, config.Edit(answerFile(t, "\n"), io.Discard, io.Discard))
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, "# mine\nkv_bucket_name: fixed\n", string(data))
+		info, _ := os.Stat(path)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("gives up without input", func(t *testing.T) {
+		config, path := loadWithoutConfigFile(t)
+		t.Setenv("GS_TEST_BUCKET", `""`)
+
+		assert.EqualError(t, config.Edit(nil, io.Discard, io.Discard), path+" was not changed: kv_bucket_name must not be empty")
+		assert.NoFileExists(t, path)
+	})
+}
@@ -2,11 +2,14 @@
 package libs
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"time"
 
-	"github.com/spf13/viper"
 	"go.etcd.io/bbolt"
 )
 
@@ -18,70 +21,243 @@ type DB interface {
 
 type Tx interface {
 	Bucket(name []byte) Bucket
+	CreateBucket(name []byte) (Bucket, error)
+	DeleteBucket(name []byte) error
+	// ForEachBucket calls fn with the name of every top-level bucket.
+	ForEachBucket(fn func(name []byte) error) error
 }
 
 type Bucket interface {
 	Put(key, value []byte) error
 	Get(key []byte) []byte
+	Delete(key []byte) error
+	ForEach(fn func(key, value []byte) error) error
 }
 
+// MetaBucketName is the bucket gs keeps its own bookkeeping in, next to the
+// bucket holding the aliases.
+const MetaBucketName = "__gs_meta"
+
+// ErrDBLocked is returned when another gs process holds the database for
+// longer than the lock timeout.
+var ErrDBLocked = errors.New("database is in use by another gs process")
+
+// DefaultDBLockTimeout is how long gs waits for another gs process to release
+// the database file.
+const DefaultDBLockTimeout = 5 * time.Second
+
+// BoltDB is a bbolt database that is opened again on the next transaction
+// after Close, so that gs can let go of the file lock while it waits for
+// something else, such as a child command or the user.
 type BoltDB struct {
-	db *bbolt.DB
+	db           *bbolt.DB
+	path         string
+	kvBucketName string
+	lockTimeout  time.Duration
 }
 
-func getDBPath() (string, error) {
-	dirname, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
+// BoltDBOption customises a BoltDB.
+type BoltDBOption func(*BoltDB)
+
+// WithLockTimeout makes the database wait at most timeout for the file lock.
+func WithLockTimeout(timeout time.Duration) BoltDBOption {
+	return func(b *BoltDB) {
+		b.lockTimeout = timeout
 	}
+}
 
-	folderName := fmt.Sprintf(".%s", viper.GetString("app_name"))
-	dbPath := filepath.Join(dirname, folderName, "bbolt.db")
-	if _, err := os.Stat(filepath.Dir(dbPath)); os.IsNotExist(err) {
-		if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
-			return "", err
+// DBFileName is the name of the database file in its directory.
+const DBFileName = "bbolt.db"
+
+// LegacyDBMove records that ResolveDBPath moved the database out of the
+// directory older versions of gs kept it in.
+type LegacyDBMove struct {
+	From   string
+	To     string
+	Backup string
+}
+
+// ResolveDBPath returns the database file to use. A configured path wins,
+// with "~" and environment variables expanded. Otherwise it is
+// $XDG_DATA_HOME/<appName>/bbolt.db, or ~/.local/share/<appName>/bbolt.db
+// when XDG_DATA_HOME is unset or relative. When only the legacy
+// ~/.<appName>/bbolt.db exists, it is copied to the new location first and
+// renamed to a backup, which the returned move describes.
+func ResolveDBPath(appName, configured string) (string, *LegacyDBMove, error) {
+	if configured != "" {
+		expanded, err := expandPath(configured)
+		if err != nil {
+			return "", nil, err
 		}
+		path, err := filepath.Abs(expanded)
+		return path, nil, err
 	}
 
-	return dbPath, nil
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", nil, err
+	}
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	// The XDG spec says to ignore relative paths
+	if !filepath.IsAbs(dataHome) {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	path := filepath.Join(dataHome, appName, DBFileName)
+
+	legacy := filepath.Join(home, "."+appName, DBFileName)
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		return path, nil, err
+	}
+	if _, err := os.Stat(legacy); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return path, nil, nil
+		}
+		return "", nil, err
+	}
+	move, err := moveLegacyDB(legacy, path, time.Now())
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to move %s to %s: %w", legacy, path, err)
+	}
+	return path, move, nil
 }
 
-func NewBoltDB(kvBucketName string) (*BoltDB, error) {
-	path, err := getDBPath()
+// moveLegacyDB copies the database at from to a temporary file next to to,
+// renames that into place and only then renames from to a backup, so that an
+// interruption leaves a usable database behind.
+func moveLegacyDB(from, to string, now time.Time) (*LegacyDBMove, error) {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return nil, err
+	}
+
+	src, err := os.Open(from)
 	if err != nil {
 		return nil, err
 	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(to), DBFileName+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), to); err != nil {
+		return nil, err
+	}
+
+	backup := fmt.Sprintf("%s.moved-%s.bak", from, now.Format("20060102T150405"))
+	if err := os.Rename(from, backup); err != nil {
+		return nil, err
+	}
+	return &LegacyDBMove{From: from, To: to, Backup: backup}, nil
+}
 
-	db, err := bbolt.Open(path, 0666, nil)
+// OpenBoltDB prepares the database file at path and creates its directory if
+// needed. The file itself is only opened, and the alias bucket and the meta
+// bucket created, by the first transaction, so that commands which never use
+// the database do not wait for another gs process holding it.
+func OpenBoltDB(path string, kvBucketName string, opts ...BoltDBOption) (*BoltDB, error) {
+	b := &BoltDB{path: path, kvBucketName: kvBucketName, lockTimeout: DefaultDBLockTimeout}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// open returns the open database, opening the file on first use and again
+// after Close.
+func (b *BoltDB) open() (*bbolt.DB, error) {
+	if b.db != nil {
+		return b.db, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+		return nil, err
+	}
+	db, err := bbolt.Open(b.path, 0666, &bbolt.Options{Timeout: b.lockTimeout})
+	if errors.Is(err, bbolt.ErrTimeout) {
+		return nil, fmt.Errorf("%w: %s", ErrDBLocked, b.path)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	err = db.Update(func(tx *bbolt.Tx) error {
-		_, err := tx.CreateBucketIfNotExists([]byte(kvBucketName))
-		return err
+		for _, name := range []string{b.kvBucketName, MetaBucketName} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
-	return &BoltDB{db: db}, nil
+	b.db = db
+	return db, nil
 }
 
 func (b *BoltDB) Update(fn func(Tx) error) error {
-	return b.db.Update(func(tx *bbolt.Tx) error {
+	db, err := b.open()
+	if err != nil {
+		return err
+	}
+	return db.Update(func(tx *bbolt.Tx) error {
 		return fn(&BoltTx{tx})
 	})
 }
 
 func (b *BoltDB) View(fn func(Tx) error) error {
-	return b.db.View(func(tx *bbolt.Tx) error {
+	db, err := b.open()
+	if err != nil {
+		return err
+	}
+	return db.View(func(tx *bbolt.Tx) error {
 		return fn(&BoltTx{tx})
 	})
 }
 
+// Close releases the database file and its lock until the next transaction.
 func (b *BoltDB) Close() error {
-	return b.db.Close()
+	if b.db == nil {
+		return nil
+	}
+	err := b.db.Close()
+	b.db = nil
+	return err
+}
+
+// Path returns the location of the database file.
+func (b *BoltDB) Path() string {
+	return b.path
+}
+
+// Backup writes a consistent copy of the database to dest.
+func (b *BoltDB) Backup(dest string) error {
+	db, err := b.open()
+	if err != nil {
+		return err
+	}
+	return db.View(func(tx *bbolt.Tx) error {
+		return tx.CopyFile(dest, 0600)
+	})
 }
 
 type BoltTx struct {
@@ -96,6 +272,24 @@ func (t *BoltTx) Bucket(name []byte) Bucket {
 	return &BoltBucket{bucket}
 }
 
+func (t *BoltTx) CreateBucket(name []byte) (Bucket, error) {
+	bucket, err := t.Tx.CreateBucket(name)
+	if err != nil {
+		return nil, err
+	}
+	return &BoltBucket{bucket}, nil
+}
+
+func (t *BoltTx) DeleteBucket(name []byte) error {
+	return t.Tx.DeleteBucket(name)
+}
+
+func (t *BoltTx) ForEachBucket(fn func(name []byte) error) error {
+	return t.Tx.ForEach(func(name []byte, _ *bbolt.Bucket) error {
+		return fn(name)
+	})
+}
+
 type BoltBucket struct {
 	bucket *bbolt.Bucket
 }
@@ -107,3 +301,11 @@ func (b *BoltBucket) Put(key, value []byte) error {
 func (b *BoltBucket) Get(key []byte) []byte {
 	return b.bucket.Get(key)
 }
+
+func (b *BoltBucket) Delete(key []byte) error {
+	return b.bucket.Delete(key)
+}
+
+func (b *BoltBucket) ForEach(fn func(key, value []byte) error) error {
+	return b.bucket.ForEach(fn)
+}
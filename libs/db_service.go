@@ -1,38 +1,640 @@
 package libs
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrAliasNotFound = errors.New("alias not found")
+	ErrAliasExists   = errors.New("alias already exists")
+	ErrTagNotFound   = errors.New("tag not found")
+	ErrInvalidTag    = errors.New("invalid tag")
+)
+
+// Entry is a stored alias together with its record.
+type Entry struct {
+	Alias string
+	Record
+}
 
 type DBService struct {
-	db           DB
+	db DB
+	// baseBucketName is the kv_bucket_name setting, which holds the aliases
+	// of the default profile and prefixes the buckets of the others.
+	baseBucketName string
+	selection      ProfileSelection
+	// kvBucketName is the bucket of the selected profile.
 	kvBucketName string
+	now          func() time.Time
+}
+
+// DBServiceOption customises a DBService.
+type DBServiceOption func(*DBService)
+
+// WithClock makes the service read the current time from now, which tests use
+// to get stable timestamps.
+func WithClock(now func() time.Time) DBServiceOption {
+	return func(s *DBService) {
+		s.now = now
+	}
+}
+
+// WithProfile makes the service work on the aliases of profile instead of
+// the DefaultProfile.
+func WithProfile(profile string) DBServiceOption {
+	return WithProfileSelection(ProfileSelection{Profile: profile})
+}
+
+// WithProfileSelection is WithProfile that also remembers why the profile was
+// chosen, for 'gs profile which'.
+func WithProfileSelection(selection ProfileSelection) DBServiceOption {
+	return func(s *DBService) {
+		s.selection = selection
+	}
 }
 
-func NewDBService(db DB, kvBucketName string) *DBService {
-	return &DBService{db, kvBucketName}
+func NewDBService(db DB, kvBucketName string, opts ...DBServiceOption) *DBService {
+	s := &DBService{db: db, baseBucketName: kvBucketName, selection: ProfileSelection{Profile: DefaultProfile}, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.kvBucketName = ProfileBucketName(kvBucketName, s.selection.Profile)
+	return s
+}
+
+// Release closes the database so that other gs processes can use it while
+// this one waits, for example on a child command or the user. The next call
+// opens it again.
+func (s *DBService) Release() error {
+	return s.db.Close()
+}
+
+// AddOptions controls what Add does when the alias is already taken and what
+// else it stores with the path.
+type AddOptions struct {
+	// Force overwrites the existing alias.
+	Force bool
+	// Suffix stores the path under the first free alias of the form alias-N.
+	Suffix bool
+	// Identity is stored with the path so that 'gs relocate' can find the
+	// repository after it moves.
+	Identity RepoIdentity
+	// NonGit is stored with the path; see Record.NonGit.
+	NonGit bool
+}
+
+// AddResult describes what Add stored.
+type AddResult struct {
+	// Alias is the alias the path was stored under, including any suffix.
+	Alias string
+	// ExistingAliases lists other aliases that already point at the same path.
+	ExistingAliases []string
+}
+
+// Add stores path under alias. The collision check and the write happen in
+// the same transaction. Adding an alias that already points at path only
+// refreshes the stored identity.
+func (s *DBService) Add(alias, path string, opts AddOptions) (AddResult, error) {
+	result := AddResult{Alias: alias}
+	err := s.db.Update(func(tx Tx) error {
+		b := tx.Bucket([]byte(s.kvBucketName))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", s.kvBucketName)
+		}
+
+		if value := b.Get([]byte(alias)); value != nil {
+			existing, err := DecodeRecord(value)
+			if err != nil {
+				return err
+			}
+			switch {
+			case existing.Path == path:
+				// Keep the existing record and its usage history
+				newIdentity := !opts.Identity.IsZero() && existing.Identity() != opts.Identity
+				if !newIdentity && existing.NonGit == opts.NonGit {
+					return nil
+				}
+				if newIdentity {
+					existing.Remote, existing.RootCommit = opts.Identity.Remote, opts.Identity.RootCommit
+				}
+				existing.NonGit = opts.NonGit
+				existing.UpdatedAt = s.now()
+				return putRecord(b, alias, existing)
+			case opts.Force:
+			case opts.Suffix:
+				result.Alias = nextFreeAlias(b, alias)
+			default:
+				return fmt.Errorf("%w: %s points at %s", ErrAliasExists, alias, existing.Path)
+			}
+		}
+
+		err := b.ForEach(func(key, value []byte) error {
+			record, err := DecodeRecord(value)
+			if err != nil {
+				return err
+			}
+			if record.Path == path && string(key) != result.Alias {
+				result.ExistingAliases = append(result.ExistingAliases, string(key))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		now := s.now()
+		return putRecord(b, result.Alias, Record{
+			Path:       path,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+			Remote:     opts.Identity.Remote,
+			RootCommit: opts.Identity.RootCommit,
+			NonGit:     opts.NonGit,
+		})
+	})
+	if err != nil {
+		return AddResult{}, err
+	}
+	return result, nil
 }
 
-func (s *DBService) Add(key, value string) error {
+// AddAll stores every entry in one transaction. If any alias is already
+// taken, nothing is stored. Usage fields of the entries are ignored.
+func (s *DBService) AddAll(entries []Entry) error {
 	return s.db.Update(func(tx Tx) error {
 		b := tx.Bucket([]byte(s.kvBucketName))
 		if b == nil {
 			return fmt.Errorf("bucket %s not found", s.kvBucketName)
 		}
-		return b.Put([]byte(key), []byte(value))
+		now := s.now()
+		for _, entry := range entries {
+			if existing := b.Get([]byte(entry.Alias)); existing != nil {
+				return fmt.Errorf("%w: %s", ErrAliasExists, entry.Alias)
+			}
+			record := Record{
+				Path:       entry.Path,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+				Tags:       entry.Tags,
+				Remote:     entry.Remote,
+				RootCommit: entry.RootCommit,
+			}
+			if err := putRecord(b, entry.Alias, record); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 }
 
+func nextFreeAlias(b Bucket, alias string) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", alias, n)
+		if b.Get([]byte(candidate)) == nil {
+			return candidate
+		}
+	}
+}
+
+// Get returns the path stored for key, or an empty string if there is none.
 func (s *DBService) Get(key string) (string, error) {
+	record, found, err := s.GetRecord(key)
+	if err != nil || !found {
+		return "", err
+	}
+	return record.Path, nil
+}
+
+// GetRecord returns the record stored for alias and whether it exists.
+func (s *DBService) GetRecord(alias string) (Record, bool, error) {
 	var value []byte
 	err := s.db.View(func(tx Tx) error {
 		b := tx.Bucket([]byte(s.kvBucketName))
 		if b == nil {
 			return fmt.Errorf("bucket %s not found", s.kvBucketName)
 		}
-		value = b.Get([]byte(key))
+		// Copy out: values are only valid for the life of the transaction
+		value = append([]byte(nil), b.Get([]byte(alias))...)
+		return nil
+	})
+	if err != nil {
+		return Record{}, false, err
+	}
+	if len(value) == 0 {
+		return Record{}, false, nil
+	}
+	record, err := DecodeRecord(value)
+	if err != nil {
+		return Record{}, false, err
+	}
+	return record, true, nil
+}
+
+// Match resolves query to a stored entry using MatchAlias, falling back to
+// prefix, substring and fuzzy matches when no alias equals query.
+func (s *DBService) Match(query string) (Entry, error) {
+	entries, err := s.List()
+	if err != nil {
+		return Entry{}, err
+	}
+	return MatchAlias(query, entries)
+}
+
+func (s *DBService) List() ([]Entry, error) {
+	var entries []Entry
+	err := s.ForEach(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ForEach calls fn for every stored entry in alias order and stops at the
+// first error fn returns. fn runs inside a read transaction, so it must not
+// write to the database or block for long.
+func (s *DBService) ForEach(fn func(entry Entry) error) error {
+	return s.db.View(func(tx Tx) error {
+		b := tx.Bucket([]byte(s.kvBucketName))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", s.kvBucketName)
+		}
+		return b.ForEach(func(key, value []byte) error {
+			record, err := DecodeRecord(value)
+			if err != nil {
+				return fmt.Errorf("alias %s: %w", key, err)
+			}
+			return fn(Entry{Alias: string(key), Record: record})
+		})
+	})
+}
+
+// Remove deletes every given alias in a single transaction. Nothing is removed
+// if any of the aliases does not exist.
+func (s *DBService) Remove(aliases ...string) error {
+	return s.db.Update(func(tx Tx) error {
+		b := tx.Bucket([]byte(s.kvBucketName))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", s.kvBucketName)
+		}
+		for _, alias := range aliases {
+			if b.Get([]byte(alias)) == nil {
+				return fmt.Errorf("%w: %s", ErrAliasNotFound, alias)
+			}
+			if err := b.Delete([]byte(alias)); err != nil {
+				return err
+			}
+			if err := s.renameInHistory(tx, alias, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Rename moves the path stored under oldAlias to newAlias in a single
+// transaction. It refuses to overwrite an existing newAlias.
+func (s *DBService) Rename(oldAlias, newAlias string) error {
+	return s.db.Update(func(tx Tx) error {
+		b := tx.Bucket([]byte(s.kvBucketName))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", s.kvBucketName)
+		}
+		value := b.Get([]byte(oldAlias))
+		if value == nil {
+			return fmt.Errorf("%w: %s", ErrAliasNotFound, oldAlias)
+		}
+		if b.Get([]byte(newAlias)) != nil {
+			return fmt.Errorf("%w: %s", ErrAliasExists, newAlias)
+		}
+		// Values returned by Get are only valid until the bucket is modified
+		value = append([]byte(nil), value...)
+		if err := b.Put([]byte(newAlias), value); err != nil {
+			return err
+		}
+		if err := b.Delete([]byte(oldAlias)); err != nil {
+			return err
+		}
+		return s.renameInHistory(tx, oldAlias, newAlias)
+	})
+}
+
+// renameInHistory points the visit history at newAlias where it names alias,
+// or forgets alias when newAlias is empty, so that 'gs -' never goes back to
+// an alias that is gone.
+func (s *DBService) renameInHistory(tx Tx, alias, newAlias string) error {
+	meta := tx.Bucket([]byte(MetaBucketName))
+	if meta == nil {
+		return fmt.Errorf("bucket %s not found", MetaBucketName)
+	}
+	for _, name := range []string{"current", "previous"} {
+		key := s.historyKey(name)
+		if string(meta.Get(key)) != alias {
+			continue
+		}
+		var err error
+		if newAlias == "" {
+			err = meta.Delete(key)
+		} else {
+			err = meta.Put(key, []byte(newAlias))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Move points an existing alias at a new path.
+func (s *DBService) Move(alias, path string) error {
+	return s.db.Update(func(tx Tx) error {
+		b := tx.Bucket([]byte(s.kvBucketName))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", s.kvBucketName)
+		}
+		value := b.Get([]byte(alias))
+		if value == nil {
+			return fmt.Errorf("%w: %s", ErrAliasNotFound, alias)
+		}
+		record, err := DecodeRecord(value)
+		if err != nil {
+			return err
+		}
+		record.Path = path
+		record.UpdatedAt = s.now()
+		return putRecord(b, alias, record)
+	})
+}
+
+// Relocate points every alias in paths at its new path in one transaction.
+// If any alias does not exist, no alias is changed.
+func (s *DBService) Relocate(paths map[string]string) error {
+	aliases := make([]string, 0, len(paths))
+	for alias := range paths {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	return s.db.Update(func(tx Tx) error {
+		b := tx.Bucket([]byte(s.kvBucketName))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", s.kvBucketName)
+		}
+		for _, alias := range aliases {
+			value := b.Get([]byte(alias))
+			if value == nil {
+				return fmt.Errorf("%w: %s", ErrAliasNotFound, alias)
+			}
+			record, err := DecodeRecord(value)
+			if err != nil {
+				return err
+			}
+			record.Path = paths[alias]
+			record.UpdatedAt = s.now()
+			if err := putRecord(b, alias, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ValidateTag checks that tag can be used in "<tag>/<alias>" references: it
+// must not be empty or contain a slash or whitespace.
+func ValidateTag(tag string) error {
+	if tag == "" || strings.ContainsAny(tag, "/ \t\n") {
+		return fmt.Errorf("%w: %q", ErrInvalidTag, tag)
+	}
+	return nil
+}
+
+// Tag adds tag to every alias in one transaction. If any alias does not
+// exist, no alias is changed.
+func (s *DBService) Tag(tag string, aliases ...string) error {
+	if err := ValidateTag(tag); err != nil {
+		return err
+	}
+	return s.db.Update(func(tx Tx) error {
+		_, err := s.updateRecords(tx, aliases, func(record *Record) bool {
+			if record.HasTag(tag) {
+				return false
+			}
+			record.Tags = append(record.Tags, tag)
+			sort.Strings(record.Tags)
+			return true
+		})
+		return err
+	})
+}
+
+// Untag removes tag from the given aliases in one transaction, or from every
+// alias carrying it when none are given. In that case it returns
+// ErrTagNotFound if no alias had the tag.
+func (s *DBService) Untag(tag string, aliases ...string) error {
+	return s.db.Update(func(tx Tx) error {
+		changed, err := s.updateRecords(tx, aliases, func(record *Record) bool {
+			if !record.HasTag(tag) {
+				return false
+			}
+			tags := record.Tags[:0]
+			for _, t := range record.Tags {
+				if t != tag {
+					tags = append(tags, t)
+				}
+			}
+			record.Tags = tags
+			return true
+		})
+		if err == nil && len(aliases) == 0 && changed == 0 {
+			return fmt.Errorf("%w: %s", ErrTagNotFound, tag)
+		}
+		return err
+	})
+}
+
+// updateRecords applies update to the records of aliases, or of every alias
+// when none are given, and stores those it reports as changed. It returns the
+// number of changed records.
+func (s *DBService) updateRecords(tx Tx, aliases []string, update func(record *Record) bool) (int, error) {
+	b := tx.Bucket([]byte(s.kvBucketName))
+	if b == nil {
+		return 0, fmt.Errorf("bucket %s not found", s.kvBucketName)
+	}
+
+	records := map[string]Record{}
+	if len(aliases) == 0 {
+		// Collect first: the bucket must not change while iterating it
+		err := b.ForEach(func(key, value []byte) error {
+			record, err := DecodeRecord(value)
+			if err != nil {
+				return fmt.Errorf("alias %s: %w", key, err)
+			}
+			records[string(key)] = record
+			aliases = append(aliases, string(key))
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		for _, alias := range aliases {
+			value := b.Get([]byte(alias))
+			if value == nil {
+				return 0, fmt.Errorf("%w: %s", ErrAliasNotFound, alias)
+			}
+			record, err := DecodeRecord(value)
+			if err != nil {
+				return 0, err
+			}
+			records[alias] = record
+		}
+	}
+
+	changed := 0
+	for _, alias := range aliases {
+		record := records[alias]
+		if !update(&record) {
+			continue
+		}
+		record.UpdatedAt = s.now()
+		if err := putRecord(b, alias, record); err != nil {
+			return 0, err
+		}
+		records[alias] = record
+		changed++
+	}
+	return changed, nil
+}
+
+func putRecord(b Bucket, alias string, record Record) error {
+	value, err := EncodeRecord(record)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(alias), value)
+}
+
+// Visit records a switch to alias and returns its path. It bumps the use
+// count and rank, ages all ranks when they grow too large, and remembers the
+// previously visited alias for VisitPrevious.
+func (s *DBService) Visit(alias string) (string, error) {
+	var path string
+	err := s.db.Update(func(tx Tx) error {
+		b := tx.Bucket([]byte(s.kvBucketName))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", s.kvBucketName)
+		}
+		meta := tx.Bucket([]byte(MetaBucketName))
+		if meta == nil {
+			return fmt.Errorf("bucket %s not found", MetaBucketName)
+		}
+
+		value := b.Get([]byte(alias))
+		if value == nil {
+			return fmt.Errorf("%w: %s", ErrAliasNotFound, alias)
+		}
+		record, err := DecodeRecord(value)
+		if err != nil {
+			return err
+		}
+		record.UseCount++
+		record.Rank++
+		record.LastUsed = s.now()
+		path = record.Path
+		if err := putRecord(b, alias, record); err != nil {
+			return err
+		}
+
+		if err := s.ageRanks(b); err != nil {
+			return err
+		}
+		return s.recordVisit(meta, alias)
+	})
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// PreviousAlias returns the alias visited before the current one, or an
+// empty string if there is none yet.
+func (s *DBService) PreviousAlias() (string, error) {
+	var previous string
+	err := s.db.View(func(tx Tx) error {
+		meta := tx.Bucket([]byte(MetaBucketName))
+		if meta == nil {
+			return fmt.Errorf("bucket %s not found", MetaBucketName)
+		}
+		previous = string(meta.Get(s.historyKey("previous")))
 		return nil
 	})
 	if err != nil {
 		return "", err
 	}
-	return string(value), nil
+	return previous, nil
+}
+
+// Top returns up to limit used aliases ordered by frecency. A limit of zero
+// or less returns all of them.
+func (s *DBService) Top(limit int) ([]ScoredEntry, error) {
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	ranked := RankEntries(entries, s.now())
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked, nil
+}
+
+func (s *DBService) ageRanks(b Bucket) error {
+	records := map[string]Record{}
+	err := b.ForEach(func(key, value []byte) error {
+		record, err := DecodeRecord(value)
+		if err != nil {
+			return err
+		}
+		records[string(key)] = record
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, alias := range ageRanks(records, MaxTotalRank) {
+		if err := putRecord(b, alias, records[alias]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *DBService) recordVisit(meta Bucket, alias string) error {
+	current := meta.Get(s.historyKey("current"))
+	if string(current) == alias {
+		return nil
+	}
+	if current != nil {
+		// Copy before writing: values are only valid until the bucket changes
+		if err := meta.Put(s.historyKey("previous"), append([]byte(nil), current...)); err != nil {
+			return err
+		}
+	}
+	return meta.Put(s.historyKey("current"), []byte(alias))
+}
+
+func (s *DBService) historyKey(name string) []byte {
+	return historyKey(s.kvBucketName, name)
+}
+
+// historyKey namespaces the visit history by alias bucket, and so by profile.
+func historyKey(bucket, name string) []byte {
+	return []byte(fmt.Sprintf("history/%s/%s", bucket, name))
 }
@@ -4,18 +4,38 @@ import (
 	"errors"
 	"gs/libs"
 	mocks "gs/mocks/libs"
+	"reflect"
 	"testing"
+	"time"
 
 	"go.uber.org/mock/gomock"
 )
 
+var testNow = time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+
+func testClock() time.Time {
+	return testNow
+}
+
+// encodedRecord returns the bytes DBService writes for record.
+func encodedRecord(record libs.Record) []byte {
+	value, err := libs.EncodeRecord(record)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
 func TestDBService_Add(t *testing.T) {
 	tests := []struct {
-		name      string
-		key       string
-		value     string
-		setupMock func(*mocks.MockDB, *mocks.MockTx, *mocks.MockBucket)
-		wantErr   bool
+		name       string
+		key        string
+		value      string
+		opts       libs.AddOptions
+		setupMock  func(*mocks.MockDB, *mocks.MockTx, *mocks.MockBucket)
+		wantResult libs.AddResult
+		wantErrIs  error
+		wantErr    bool
 	}{
 		{
 			name:  "successful add",
@@ -32,9 +52,151 @@ func TestDBService_Add(t *testing.T) {
 
 				// Set expectations for what happens inside the transaction function
 				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
-				mockBucket.EXPECT().Put([]byte("test-key"), []byte("test-value")).Return(nil)
+				mockBucket.EXPECT().Get([]byte("test-key")).Return(nil)
+				mockBucket.EXPECT().ForEach(gomock.Any()).Return(nil)
+				mockBucket.EXPECT().Put([]byte("test-key"), encodedRecord(libs.Record{Path: "test-value", CreatedAt: testNow, UpdatedAt: testNow})).Return(nil)
 			},
-			wantErr: false,
+			wantResult: libs.AddResult{Alias: "test-key"},
+			wantErr:    false,
+		},
+		{
+			name:  "re-adding the same path is a no-op",
+			key:   "test-key",
+			value: "test-value",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				// Existing usage history must survive, so nothing is written
+				mockBucket.EXPECT().Get([]byte("test-key")).Return(encodedRecord(libs.Record{Path: "test-value", UseCount: 3}))
+			},
+			wantResult: libs.AddResult{Alias: "test-key"},
+			wantErr:    false,
+		},
+		{
+			name:  "successful add with identity",
+			key:   "test-key",
+			value: "test-value",
+			opts:  libs.AddOptions{Identity: libs.RepoIdentity{Remote: "git@example.com:org/repo.git", RootCommit: "abc123"}},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("test-key")).Return(nil)
+				mockBucket.EXPECT().ForEach(gomock.Any()).Return(nil)
+				mockBucket.EXPECT().Put([]byte("test-key"), encodedRecord(libs.Record{
+					Path: "test-value", CreatedAt: testNow, UpdatedAt: testNow,
+					Remote: "git@example.com:org/repo.git", RootCommit: "abc123",
+				})).Return(nil)
+			},
+			wantResult: libs.AddResult{Alias: "test-key"},
+		},
+		{
+			name:  "re-adding the same path stores a new identity",
+			key:   "test-key",
+			value: "test-value",
+			opts:  libs.AddOptions{Identity: libs.RepoIdentity{RootCommit: "abc123"}},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("test-key")).Return(encodedRecord(libs.Record{Path: "test-value", UseCount: 3}))
+				mockBucket.EXPECT().Put([]byte("test-key"), encodedRecord(libs.Record{
+					Path: "test-value", UseCount: 3, UpdatedAt: testNow, RootCommit: "abc123",
+				})).Return(nil)
+			},
+			wantResult: libs.AddResult{Alias: "test-key"},
+		},
+		{
+			name:  "re-adding the same path with allow-non-git marks it as non-git",
+			key:   "test-key",
+			value: "test-value",
+			opts:  libs.AddOptions{NonGit: true},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("test-key")).Return(encodedRecord(libs.Record{Path: "test-value", UseCount: 3, RootCommit: "abc123"}))
+				mockBucket.EXPECT().Put([]byte("test-key"), encodedRecord(libs.Record{
+					Path: "test-value", UseCount: 3, UpdatedAt: testNow, RootCommit: "abc123", NonGit: true,
+				})).Return(nil)
+			},
+			wantResult: libs.AddResult{Alias: "test-key"},
+		},
+		{
+			name:  "alias already exists",
+			key:   "test-key",
+			value: "test-value",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("test-key")).Return([]byte("other-value"))
+			},
+			wantErrIs: libs.ErrAliasExists,
+			wantErr:   true,
+		},
+		{
+			name:  "alias already exists with force",
+			key:   "test-key",
+			value: "test-value",
+			opts:  libs.AddOptions{Force: true},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("test-key")).Return([]byte("other-value"))
+				mockBucket.EXPECT().ForEach(gomock.Any()).Return(nil)
+				mockBucket.EXPECT().Put([]byte("test-key"), encodedRecord(libs.Record{Path: "test-value", CreatedAt: testNow, UpdatedAt: testNow})).Return(nil)
+			},
+			wantResult: libs.AddResult{Alias: "test-key"},
+			wantErr:    false,
+		},
+		{
+			name:  "alias already exists with suffix",
+			key:   "test-key",
+			value: "test-value",
+			opts:  libs.AddOptions{Suffix: true},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("test-key")).Return([]byte("other-value"))
+				mockBucket.EXPECT().Get([]byte("test-key-2")).Return([]byte("another-value"))
+				mockBucket.EXPECT().Get([]byte("test-key-3")).Return(nil)
+				mockBucket.EXPECT().ForEach(gomock.Any()).Return(nil)
+				mockBucket.EXPECT().Put([]byte("test-key-3"), encodedRecord(libs.Record{Path: "test-value", CreatedAt: testNow, UpdatedAt: testNow})).Return(nil)
+			},
+			wantResult: libs.AddResult{Alias: "test-key-3"},
+			wantErr:    false,
+		},
+		{
+			name:  "path already registered under another alias",
+			key:   "test-key",
+			value: "test-value",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("test-key")).Return(nil)
+				mockBucket.EXPECT().ForEach(gomock.Any()).DoAndReturn(func(fn func(key, value []byte) error) error {
+					if err := fn([]byte("other-key"), []byte("test-value")); err != nil {
+						return err
+					}
+					return fn([]byte("unrelated-key"), []byte("unrelated-value"))
+				})
+				mockBucket.EXPECT().Put([]byte("test-key"), encodedRecord(libs.Record{Path: "test-value", CreatedAt: testNow, UpdatedAt: testNow})).Return(nil)
+			},
+			wantResult: libs.AddResult{Alias: "test-key", ExistingAliases: []string{"other-key"}},
+			wantErr:    false,
 		},
 		{
 			name:  "database update error",
@@ -67,7 +229,9 @@ func TestDBService_Add(t *testing.T) {
 					return fn(mockTx)
 				})
 				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
-				mockBucket.EXPECT().Put([]byte("test-key"), []byte("test-value")).Return(errors.New("put error"))
+				mockBucket.EXPECT().Get([]byte("test-key")).Return(nil)
+				mockBucket.EXPECT().ForEach(gomock.Any()).Return(nil)
+				mockBucket.EXPECT().Put([]byte("test-key"), encodedRecord(libs.Record{Path: "test-value", CreatedAt: testNow, UpdatedAt: testNow})).Return(errors.New("put error"))
 			},
 			wantErr: true,
 		},
@@ -84,11 +248,83 @@ func TestDBService_Add(t *testing.T) {
 
 			tt.setupMock(mockDB, mockTx, mockBucket)
 
-			service := libs.NewDBService(mockDB, "test-bucket")
-			err := service.Add(tt.key, tt.value)
+			service := libs.NewDBService(mockDB, "test-bucket", libs.WithClock(testClock))
+			result, err := service.Add(tt.key, tt.value, tt.opts)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.Add() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("Service.Add() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if !reflect.DeepEqual(result, tt.wantResult) {
+				t.Errorf("Service.Add() result = %v, want %v", result, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestDBService_AddAll(t *testing.T) {
+	entries := []libs.Entry{
+		{Alias: "api", Record: libs.Record{Path: "/src/api", Remote: "git@example.com:org/api.git", UseCount: 9}},
+		{Alias: "web", Record: libs.Record{Path: "/src/web", RootCommit: "abc123"}},
+	}
+
+	tests := []struct {
+		name      string
+		setupMock func(*mocks.MockDB, *mocks.MockTx, *mocks.MockBucket)
+		wantErrIs error
+	}{
+		{
+			name: "successful add of every entry",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				gomock.InOrder(
+					mockBucket.EXPECT().Get([]byte("api")).Return(nil),
+					mockBucket.EXPECT().Put([]byte("api"), encodedRecord(libs.Record{
+						Path: "/src/api", CreatedAt: testNow, UpdatedAt: testNow, Remote: "git@example.com:org/api.git",
+					})).Return(nil),
+					mockBucket.EXPECT().Get([]byte("web")).Return(nil),
+					mockBucket.EXPECT().Put([]byte("web"), encodedRecord(libs.Record{
+						Path: "/src/web", CreatedAt: testNow, UpdatedAt: testNow, RootCommit: "abc123",
+					})).Return(nil),
+				)
+			},
+		},
+		{
+			name: "alias already taken",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("api")).Return(nil)
+				mockBucket.EXPECT().Put([]byte("api"), gomock.Any()).Return(nil)
+				mockBucket.EXPECT().Get([]byte("web")).Return([]byte("/elsewhere/web"))
+			},
+			wantErrIs: libs.ErrAliasExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDB(ctrl)
+			mockTx := mocks.NewMockTx(ctrl)
+			mockBucket := mocks.NewMockBucket(ctrl)
+			tt.setupMock(mockDB, mockTx, mockBucket)
+
+			service := libs.NewDBService(mockDB, "test-bucket", libs.WithClock(testClock))
+			err := service.AddAll(entries)
+
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("Service.AddAll() error = %v, want %v", err, tt.wantErrIs)
 			}
 		})
 	}
@@ -116,6 +352,32 @@ func TestDBService_Get(t *testing.T) {
 			wantValue: "test-value",
 			wantErr:   false,
 		},
+		{
+			name: "successful get of a structured record",
+			key:  "test-key",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().View(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("test-key")).Return(encodedRecord(libs.Record{Path: "test-value", Note: "note"}))
+			},
+			wantValue: "test-value",
+			wantErr:   false,
+		},
+		{
+			name: "corrupt record",
+			key:  "test-key",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().View(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("test-key")).Return([]byte("{not json"))
+			},
+			wantValue: "",
+			wantErr:   true,
+		},
 		{
 			name: "key not found",
 			key:  "nonexistent-key",
@@ -176,3 +438,826 @@ func TestDBService_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestDBService_List(t *testing.T) {
+	tests := []struct {
+		name        string
+		setupMock   func(*mocks.MockDB, *mocks.MockTx, *mocks.MockBucket)
+		wantEntries []libs.Entry
+		wantErr     bool
+	}{
+		{
+			name: "successful list",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().View(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().ForEach(gomock.Any()).DoAndReturn(func(fn func(key, value []byte) error) error {
+					// Replay the stored pairs through the callback like bbolt would
+					if err := fn([]byte("alpha"), []byte("/src/alpha")); err != nil {
+						return err
+					}
+					return fn([]byte("beta"), encodedRecord(libs.Record{Path: "/src/beta", UseCount: 4}))
+				})
+			},
+			wantEntries: []libs.Entry{
+				{Alias: "alpha", Record: libs.Record{Version: libs.RecordVersion, Path: "/src/alpha"}},
+				{Alias: "beta", Record: libs.Record{Version: libs.RecordVersion, Path: "/src/beta", UseCount: 4}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty bucket",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().View(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().ForEach(gomock.Any()).Return(nil)
+			},
+			wantEntries: nil,
+			wantErr:     false,
+		},
+		{
+			name: "bucket not found",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().View(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "database view error",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().View(gomock.Any()).Return(errors.New("database error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDB(ctrl)
+			mockTx := mocks.NewMockTx(ctrl)
+			mockBucket := mocks.NewMockBucket(ctrl)
+
+			tt.setupMock(mockDB, mockTx, mockBucket)
+
+			service := libs.NewDBService(mockDB, "test-bucket")
+			entries, err := service.List()
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(entries, tt.wantEntries) {
+				t.Errorf("Service.List() entries = %v, want %v", entries, tt.wantEntries)
+			}
+		})
+	}
+}
+
+func TestDBService_ForEach(t *testing.T) {
+	stop := errors.New("stop")
+	tests := []struct {
+		name        string
+		fnErr       error
+		wantAliases []string
+		wantErr     error
+	}{
+		{
+			name:        "visits every entry",
+			wantAliases: []string{"alpha", "beta"},
+		},
+		{
+			name:        "stops at the first callback error",
+			fnErr:       stop,
+			wantAliases: []string{"alpha"},
+			wantErr:     stop,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDB(ctrl)
+			mockTx := mocks.NewMockTx(ctrl)
+			mockBucket := mocks.NewMockBucket(ctrl)
+
+			mockDB.EXPECT().View(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+				return fn(mockTx)
+			})
+			mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+			mockBucket.EXPECT().ForEach(gomock.Any()).DoAndReturn(func(fn func(key, value []byte) error) error {
+				if err := fn([]byte("alpha"), encodedRecord(libs.Record{Path: "/src/alpha"})); err != nil {
+					return err
+				}
+				return fn([]byte("beta"), encodedRecord(libs.Record{Path: "/src/beta"}))
+			})
+
+			var aliases []string
+			service := libs.NewDBService(mockDB, "test-bucket")
+			err := service.ForEach(func(entry libs.Entry) error {
+				aliases = append(aliases, entry.Alias)
+				return tt.fnErr
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.ForEach() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(aliases, tt.wantAliases) {
+				t.Errorf("Service.ForEach() visited %v, want %v", aliases, tt.wantAliases)
+			}
+		})
+	}
+}
+
+func TestDBService_Remove(t *testing.T) {
+	tests := []struct {
+		name      string
+		aliases   []string
+		setupMock func(*mocks.MockDB, *mocks.MockTx, *mocks.MockBucket, *mocks.MockBucket)
+		wantErrIs error
+		wantErr   bool
+	}{
+		{
+			name:    "successful remove of several aliases",
+			aliases: []string{"alpha", "beta"},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket, mockMeta *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockTx.EXPECT().Bucket([]byte(libs.MetaBucketName)).Return(mockMeta).Times(2)
+				gomock.InOrder(
+					mockBucket.EXPECT().Get([]byte("alpha")).Return([]byte("/src/alpha")),
+					mockBucket.EXPECT().Delete([]byte("alpha")).Return(nil),
+					// The history forgets the removed alias it was on
+					mockMeta.EXPECT().Get([]byte("history/test-bucket/current")).Return([]byte("alpha")),
+					mockMeta.EXPECT().Delete([]byte("history/test-bucket/current")).Return(nil),
+					mockMeta.EXPECT().Get([]byte("history/test-bucket/previous")).Return([]byte("web")),
+					mockBucket.EXPECT().Get([]byte("beta")).Return([]byte("/src/beta")),
+					mockBucket.EXPECT().Delete([]byte("beta")).Return(nil),
+					mockMeta.EXPECT().Get([]byte("history/test-bucket/current")).Return(nil),
+					mockMeta.EXPECT().Get([]byte("history/test-bucket/previous")).Return([]byte("web")),
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "alias not found",
+			aliases: []string{"alpha"},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket, mockMeta *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("alpha")).Return(nil)
+			},
+			wantErrIs: libs.ErrAliasNotFound,
+			wantErr:   true,
+		},
+		{
+			name:    "bucket delete error",
+			aliases: []string{"alpha"},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket, mockMeta *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("alpha")).Return([]byte("/src/alpha"))
+				mockBucket.EXPECT().Delete([]byte("alpha")).Return(errors.New("delete error"))
+			},
+			wantErr: true,
+		},
+		{
+			name:    "bucket not found",
+			aliases: []string{"alpha"},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket, mockMeta *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(nil)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDB(ctrl)
+			mockTx := mocks.NewMockTx(ctrl)
+			mockBucket := mocks.NewMockBucket(ctrl)
+			mockMeta := mocks.NewMockBucket(ctrl)
+
+			tt.setupMock(mockDB, mockTx, mockBucket, mockMeta)
+
+			service := libs.NewDBService(mockDB, "test-bucket")
+			err := service.Remove(tt.aliases...)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.Remove() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("Service.Remove() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestDBService_Rename(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldAlias  string
+		newAlias  string
+		setupMock func(*mocks.MockDB, *mocks.MockTx, *mocks.MockBucket, *mocks.MockBucket)
+		wantErrIs error
+		wantErr   bool
+	}{
+		{
+			name:     "successful rename",
+			oldAlias: "old",
+			newAlias: "new",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket, mockMeta *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("old")).Return([]byte("/src/project"))
+				mockBucket.EXPECT().Get([]byte("new")).Return(nil)
+				mockBucket.EXPECT().Put([]byte("new"), []byte("/src/project")).Return(nil)
+				mockBucket.EXPECT().Delete([]byte("old")).Return(nil)
+				// The history follows the renamed alias
+				mockTx.EXPECT().Bucket([]byte(libs.MetaBucketName)).Return(mockMeta)
+				mockMeta.EXPECT().Get([]byte("history/test-bucket/current")).Return([]byte("other"))
+				mockMeta.EXPECT().Get([]byte("history/test-bucket/previous")).Return([]byte("old"))
+				mockMeta.EXPECT().Put([]byte("history/test-bucket/previous"), []byte("new")).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:     "old alias not found",
+			oldAlias: "old",
+			newAlias: "new",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket, mockMeta *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("old")).Return(nil)
+			},
+			wantErrIs: libs.ErrAliasNotFound,
+			wantErr:   true,
+		},
+		{
+			name:     "new alias already exists",
+			oldAlias: "old",
+			newAlias: "new",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket, mockMeta *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("old")).Return([]byte("/src/project"))
+				mockBucket.EXPECT().Get([]byte("new")).Return([]byte("/src/other"))
+			},
+			wantErrIs: libs.ErrAliasExists,
+			wantErr:   true,
+		},
+		{
+			name:     "bucket put error",
+			oldAlias: "old",
+			newAlias: "new",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket, mockMeta *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("old")).Return([]byte("/src/project"))
+				mockBucket.EXPECT().Get([]byte("new")).Return(nil)
+				mockBucket.EXPECT().Put([]byte("new"), []byte("/src/project")).Return(errors.New("put error"))
+			},
+			wantErr: true,
+		},
+		{
+			name:     "database update error",
+			oldAlias: "old",
+			newAlias: "new",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket, mockMeta *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).Return(errors.New("database error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDB(ctrl)
+			mockTx := mocks.NewMockTx(ctrl)
+			mockBucket := mocks.NewMockBucket(ctrl)
+			mockMeta := mocks.NewMockBucket(ctrl)
+
+			tt.setupMock(mockDB, mockTx, mockBucket, mockMeta)
+
+			service := libs.NewDBService(mockDB, "test-bucket")
+			err := service.Rename(tt.oldAlias, tt.newAlias)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.Rename() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("Service.Rename() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestDBService_Move(t *testing.T) {
+	tests := []struct {
+		name      string
+		alias     string
+		path      string
+		setupMock func(*mocks.MockDB, *mocks.MockTx, *mocks.MockBucket)
+		wantErrIs error
+		wantErr   bool
+	}{
+		{
+			name:  "successful move",
+			alias: "project",
+			path:  "/src/new",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("project")).Return(encodedRecord(libs.Record{Path: "/src/old", CreatedAt: testNow.Add(-time.Hour), UseCount: 2}))
+				mockBucket.EXPECT().Put([]byte("project"), encodedRecord(libs.Record{Path: "/src/new", CreatedAt: testNow.Add(-time.Hour), UpdatedAt: testNow, UseCount: 2})).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "alias not found",
+			alias: "project",
+			path:  "/src/new",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("project")).Return(nil)
+			},
+			wantErrIs: libs.ErrAliasNotFound,
+			wantErr:   true,
+		},
+		{
+			name:  "bucket not found",
+			alias: "project",
+			path:  "/src/new",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(nil)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDB(ctrl)
+			mockTx := mocks.NewMockTx(ctrl)
+			mockBucket := mocks.NewMockBucket(ctrl)
+
+			tt.setupMock(mockDB, mockTx, mockBucket)
+
+			service := libs.NewDBService(mockDB, "test-bucket", libs.WithClock(testClock))
+			err := service.Move(tt.alias, tt.path)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.Move() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("Service.Move() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestDBService_Visit(t *testing.T) {
+	visited := libs.Record{Path: "/src/api", UseCount: 3, Rank: 3, LastUsed: testNow, CreatedAt: testNow.Add(-time.Hour)}
+
+	tests := []struct {
+		name      string
+		alias     string
+		setupMock func(*mocks.MockDB, *mocks.MockTx, *mocks.MockBucket, *mocks.MockBucket)
+		want      string
+		wantErrIs error
+		wantErr   bool
+	}{
+		{
+			name:  "successful visit records usage and history",
+			alias: "api",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket, mockMeta *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockTx.EXPECT().Bucket([]byte(libs.MetaBucketName)).Return(mockMeta)
+				mockBucket.EXPECT().Get([]byte("api")).Return(encodedRecord(libs.Record{Path: "/src/api", UseCount: 2, Rank: 2, CreatedAt: testNow.Add(-time.Hour)}))
+				mockBucket.EXPECT().Put([]byte("api"), encodedRecord(visited)).Return(nil)
+				mockBucket.EXPECT().ForEach(gomock.Any()).DoAndReturn(func(fn func(k, v []byte) error) error {
+					return fn([]byte("api"), encodedRecord(visited))
+				})
+				mockMeta.EXPECT().Get([]byte("history/test-bucket/current")).Return([]byte("web"))
+				mockMeta.EXPECT().Put([]byte("history/test-bucket/previous"), []byte("web")).Return(nil)
+				mockMeta.EXPECT().Put([]byte("history/test-bucket/current"), []byte("api")).Return(nil)
+			},
+			want: "/src/api",
+		},
+		{
+			name:  "revisiting the current alias keeps the history",
+			alias: "api",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket, mockMeta *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockTx.EXPECT().Bucket([]byte(libs.MetaBucketName)).Return(mockMeta)
+				mockBucket.EXPECT().Get([]byte("api")).Return(encodedRecord(libs.Record{Path: "/src/api", UseCount: 2, Rank: 2, CreatedAt: testNow.Add(-time.Hour)}))
+				mockBucket.EXPECT().Put([]byte("api"), encodedRecord(visited)).Return(nil)
+				mockBucket.EXPECT().ForEach(gomock.Any()).Return(nil)
+				mockMeta.EXPECT().Get([]byte("history/test-bucket/current")).Return([]byte("api"))
+			},
+			want: "/src/api",
+		},
+		{
+			name:  "alias not found",
+			alias: "api",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket, mockMeta *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockTx.EXPECT().Bucket([]byte(libs.MetaBucketName)).Return(mockMeta)
+				mockBucket.EXPECT().Get([]byte("api")).Return(nil)
+			},
+			wantErrIs: libs.ErrAliasNotFound,
+			wantErr:   true,
+		},
+		{
+			name:  "bucket not found",
+			alias: "api",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket, mockMeta *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(nil)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDB(ctrl)
+			mockTx := mocks.NewMockTx(ctrl)
+			mockBucket := mocks.NewMockBucket(ctrl)
+			mockMeta := mocks.NewMockBucket(ctrl)
+
+			tt.setupMock(mockDB, mockTx, mockBucket, mockMeta)
+
+			service := libs.NewDBService(mockDB, "test-bucket", libs.WithClock(testClock))
+			got, err := service.Visit(tt.alias)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.Visit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("Service.Visit() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if got != tt.want {
+				t.Errorf("Service.Visit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDBService_PreviousAlias(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []byte
+		want     string
+	}{
+		{name: "previous alias recorded", previous: []byte("web"), want: "web"},
+		{name: "no previous alias yet", previous: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDB(ctrl)
+			mockTx := mocks.NewMockTx(ctrl)
+			mockMeta := mocks.NewMockBucket(ctrl)
+
+			mockDB.EXPECT().View(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+				return fn(mockTx)
+			})
+			mockTx.EXPECT().Bucket([]byte(libs.MetaBucketName)).Return(mockMeta)
+			mockMeta.EXPECT().Get([]byte("history/test-bucket/previous")).Return(tt.previous)
+
+			service := libs.NewDBService(mockDB, "test-bucket")
+			got, err := service.PreviousAlias()
+
+			if err != nil {
+				t.Errorf("Service.PreviousAlias() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Service.PreviousAlias() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDBService_PreviousAlias_FollowsAliasChanges(t *testing.T) {
+	// openProfileDB visits alpha and then beta, leaving alpha as the previous alias
+	service := libs.NewDBService(openProfileDB(t), "gs")
+
+	steps := []struct {
+		name   string
+		change func() error
+		want   string
+	}{
+		{name: "rename", change: func() error { return service.Rename("alpha", "gamma") }, want: "gamma"},
+		{name: "remove", change: func() error { return service.Remove("gamma") }, want: ""},
+	}
+	for _, step := range steps {
+		if err := step.change(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		got, err := service.PreviousAlias()
+		if err != nil {
+			t.Fatalf("%s: Service.PreviousAlias() error = %v", step.name, err)
+		}
+		if got != step.want {
+			t.Errorf("after %s: Service.PreviousAlias() = %q, want %q", step.name, got, step.want)
+		}
+	}
+}
+
+func TestDBService_Relocate(t *testing.T) {
+	tests := []struct {
+		name      string
+		paths     map[string]string
+		setupMock func(*mocks.MockDB, *mocks.MockTx, *mocks.MockBucket)
+		wantErrIs error
+	}{
+		{
+			name:  "successful relocate of several aliases",
+			paths: map[string]string{"beta": "/new/beta", "alpha": "/new/alpha"},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				gomock.InOrder(
+					mockBucket.EXPECT().Get([]byte("alpha")).Return(encodedRecord(libs.Record{Path: "/old/alpha", UseCount: 2})),
+					mockBucket.EXPECT().Put([]byte("alpha"), encodedRecord(libs.Record{Path: "/new/alpha", UseCount: 2, UpdatedAt: testNow})).Return(nil),
+					mockBucket.EXPECT().Get([]byte("beta")).Return([]byte("/old/beta")),
+					mockBucket.EXPECT().Put([]byte("beta"), encodedRecord(libs.Record{Path: "/new/beta", UpdatedAt: testNow})).Return(nil),
+				)
+			},
+		},
+		{
+			name:  "unknown alias",
+			paths: map[string]string{"alpha": "/new/alpha", "ghost": "/new/ghost"},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("alpha")).Return([]byte("/old/alpha"))
+				mockBucket.EXPECT().Put([]byte("alpha"), gomock.Any()).Return(nil)
+				mockBucket.EXPECT().Get([]byte("ghost")).Return(nil)
+			},
+			wantErrIs: libs.ErrAliasNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDB(ctrl)
+			mockTx := mocks.NewMockTx(ctrl)
+			mockBucket := mocks.NewMockBucket(ctrl)
+			tt.setupMock(mockDB, mockTx, mockBucket)
+
+			service := libs.NewDBService(mockDB, "test-bucket", libs.WithClock(testClock))
+			err := service.Relocate(tt.paths)
+
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("Service.Relocate() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestDBService_Tag(t *testing.T) {
+	created := testNow.Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		tag       string
+		aliases   []string
+		setupMock func(*mocks.MockDB, *mocks.MockTx, *mocks.MockBucket)
+		wantErrIs error
+		wantErr   bool
+	}{
+		{
+			name:    "successful tag keeps tags sorted and skips tagged aliases",
+			tag:     "payments",
+			aliases: []string{"api-gw", "billing"},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("api-gw")).Return(encodedRecord(libs.Record{Path: "/src/api-gw", CreatedAt: created, Tags: []string{"platform"}}))
+				mockBucket.EXPECT().Get([]byte("billing")).Return(encodedRecord(libs.Record{Path: "/src/billing", Tags: []string{"payments"}}))
+				mockBucket.EXPECT().Put([]byte("api-gw"), encodedRecord(libs.Record{Path: "/src/api-gw", CreatedAt: created, UpdatedAt: testNow, Tags: []string{"payments", "platform"}})).Return(nil)
+			},
+		},
+		{
+			name:    "unknown alias tags nothing",
+			tag:     "payments",
+			aliases: []string{"api-gw", "ghost"},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("api-gw")).Return(encodedRecord(libs.Record{Path: "/src/api-gw"}))
+				mockBucket.EXPECT().Get([]byte("ghost")).Return(nil)
+			},
+			wantErrIs: libs.ErrAliasNotFound,
+			wantErr:   true,
+		},
+		{
+			name:      "invalid tag",
+			tag:       "team/payments",
+			aliases:   []string{"api-gw"},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {},
+			wantErrIs: libs.ErrInvalidTag,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDB(ctrl)
+			mockTx := mocks.NewMockTx(ctrl)
+			mockBucket := mocks.NewMockBucket(ctrl)
+
+			tt.setupMock(mockDB, mockTx, mockBucket)
+
+			service := libs.NewDBService(mockDB, "test-bucket", libs.WithClock(testClock))
+			err := service.Tag(tt.tag, tt.aliases...)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.Tag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("Service.Tag() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestDBService_Untag(t *testing.T) {
+	tests := []struct {
+		name      string
+		tag       string
+		aliases   []string
+		setupMock func(*mocks.MockDB, *mocks.MockTx, *mocks.MockBucket)
+		wantErrIs error
+		wantErr   bool
+	}{
+		{
+			name:    "successful untag of given aliases",
+			tag:     "payments",
+			aliases: []string{"api-gw", "docs"},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("api-gw")).Return(encodedRecord(libs.Record{Path: "/src/api-gw", Tags: []string{"payments", "platform"}}))
+				mockBucket.EXPECT().Get([]byte("docs")).Return(encodedRecord(libs.Record{Path: "/src/docs"}))
+				mockBucket.EXPECT().Put([]byte("api-gw"), encodedRecord(libs.Record{Path: "/src/api-gw", UpdatedAt: testNow, Tags: []string{"platform"}})).Return(nil)
+			},
+		},
+		{
+			name: "successful untag of every alias",
+			tag:  "payments",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().ForEach(gomock.Any()).DoAndReturn(func(fn func(k, v []byte) error) error {
+					for _, kv := range [][2][]byte{
+						{[]byte("api-gw"), encodedRecord(libs.Record{Path: "/src/api-gw", Tags: []string{"payments"}})},
+						{[]byte("billing"), encodedRecord(libs.Record{Path: "/src/billing", Tags: []string{"payments"}})},
+						{[]byte("docs"), encodedRecord(libs.Record{Path: "/src/docs"})},
+					} {
+						if err := fn(kv[0], kv[1]); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+				mockBucket.EXPECT().Put([]byte("api-gw"), encodedRecord(libs.Record{Path: "/src/api-gw", UpdatedAt: testNow, Tags: []string{}})).Return(nil)
+				mockBucket.EXPECT().Put([]byte("billing"), encodedRecord(libs.Record{Path: "/src/billing", UpdatedAt: testNow, Tags: []string{}})).Return(nil)
+			},
+		},
+		{
+			name: "tag on no alias",
+			tag:  "payments",
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().ForEach(gomock.Any()).DoAndReturn(func(fn func(k, v []byte) error) error {
+					return fn([]byte("docs"), encodedRecord(libs.Record{Path: "/src/docs"}))
+				})
+			},
+			wantErrIs: libs.ErrTagNotFound,
+			wantErr:   true,
+		},
+		{
+			name:    "unknown alias untags nothing",
+			tag:     "payments",
+			aliases: []string{"ghost"},
+			setupMock: func(mockDB *mocks.MockDB, mockTx *mocks.MockTx, mockBucket *mocks.MockBucket) {
+				mockDB.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(libs.Tx) error) error {
+					return fn(mockTx)
+				})
+				mockTx.EXPECT().Bucket([]byte("test-bucket")).Return(mockBucket)
+				mockBucket.EXPECT().Get([]byte("ghost")).Return(nil)
+			},
+			wantErrIs: libs.ErrAliasNotFound,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDB(ctrl)
+			mockTx := mocks.NewMockTx(ctrl)
+			mockBucket := mocks.NewMockBucket(ctrl)
+
+			tt.setupMock(mockDB, mockTx, mockBucket)
+
+			service := libs.NewDBService(mockDB, "test-bucket", libs.WithClock(testClock))
+			err := service.Untag(tt.tag, tt.aliases...)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.Untag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("Service.Untag() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package libs_test
+
+import (
+	"gs/libs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDBPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		xdg        bool
+		configured string
+		files      map[string]string
+		want       string
+		wantMoved  bool
+		wantFiles  map[string]string
+	}{
+		{
+			name: "default data directory",
+			want: "home/.local/share/gs/bbolt.db",
+		},
+		{
+			name: "XDG_DATA_HOME",
+			xdg:  true,
+			want: "xdg/gs/bbolt.db",
+		},
+		{
+			name:       "configured path with tilde",
+			xdg:        true,
+			configured: "~/dbs/gs.db",
+			files:      map[string]string{"home/.gs/bbolt.db": "legacy"},
+			want:       "home/dbs/gs.db",
+			wantFiles:  map[string]string{"home/.gs/bbolt.db": "legacy"},
+		},
+		{
+			name:      "legacy database is moved with a backup",
+			xdg:       true,
+			files:     map[string]string{"home/.gs/bbolt.db": "legacy"},
+			want:      "xdg/gs/bbolt.db",
+			wantMoved: true,
+			wantFiles: map[string]string{"xdg/gs/bbolt.db": "legacy"},
+		},
+		{
+			name:      "legacy database is left alone once the new one exists",
+			files:     map[string]string{"home/.gs/bbolt.db": "legacy", "home/.local/share/gs/bbolt.db": "current"},
+			want:      "home/.local/share/gs/bbolt.db",
+			wantFiles: map[string]string{"home/.gs/bbolt.db": "legacy", "home/.local/share/gs/bbolt.db": "current"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			t.Setenv("HOME", filepath.Join(root, "home"))
+			t.Setenv("XDG_DATA_HOME", "")
+			if tt.xdg {
+				t.Setenv("XDG_DATA_HOME", filepath.Join(root, "xdg"))
+			}
+			for path, content := range tt.files {
+				path = filepath.Join(root, path)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			}
+
+			path, moved, err := libs.ResolveDBPath("gs", tt.configured)
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(root, tt.want), path)
+
+			if tt.wantMoved {
+				require.NotNil(t, moved)
+				legacy := filepath.Join(root, "home", ".gs", "bbolt.db")
+				assert.Equal(t, legacy, moved.From)
+				assert.Equal(t, path, moved.To)
+				assert.NoFileExists(t, legacy)
+				backup, err := os.ReadFile(moved.Backup)
+				assert.NoError(t, err)
+				assert.Equal(t, "legacy", string(backup))
+				matches, _ := filepath.Glob(filepath.Join(root, "home", ".gs", "bbolt.db.moved-*.bak"))
+				assert.Equal(t, []string{moved.Backup}, matches)
+			} else {
+				assert.Nil(t, moved)
+			}
+			for path, content := range tt.wantFiles {
+				data, err := os.ReadFile(filepath.Join(root, path))
+				assert.NoError(t, err)
+				assert.Equal(t, content, string(data))
+			}
+		})
+	}
+}
+
+func TestOpenBoltDB_CreatesDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "gs", "bbolt.db")
+
+	db, err := libs.OpenBoltDB(path, "test-bucket")
+	require.NoError(t, err)
+	defer db.Close()
+	assert.DirExists(t, filepath.Dir(path))
+	assert.NoFileExists(t, path)
+	assert.Equal(t, path, db.Path())
+
+	require.NoError(t, db.View(func(tx libs.Tx) error { return nil }))
+	assert.FileExists(t, path)
+}
+
+func TestOpenBoltDB_Lock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bbolt.db")
+	db, err := libs.OpenBoltDB(path, "test-bucket", libs.WithLockTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// The file is only opened and locked by the first transaction
+	require.NoError(t, db.View(func(tx libs.Tx) error { return nil }))
+	locked, err := libs.OpenBoltDB(path, "test-bucket", libs.WithLockTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+	assert.ErrorIs(t, locked.View(func(tx libs.Tx) error { return nil }), libs.ErrDBLocked)
+
+	// Closing releases the lock until the next transaction reopens the file
+	require.NoError(t, db.Close())
+	other, err := libs.OpenBoltDB(path, "test-bucket", libs.WithLockTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+	require.NoError(t, other.View(func(tx libs.Tx) error { return nil }))
+	assert.ErrorIs(t, db.View(func(tx libs.Tx) error { return nil }), libs.ErrDBLocked)
+	require.NoError(t, other.Close())
+
+	err = db.View(func(tx libs.Tx) error {
+		assert.NotNil(t, tx.Bucket([]byte("test-bucket")))
+		return nil
+	})
+	assert.NoError(t, err)
+}
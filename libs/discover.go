@@ -0,0 +1,145 @@
+package libs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultScanIgnore lists the directory name patterns, in filepath.Match
+// syntax, that FindRepositories skips unless configured otherwise: hidden
+// directories and dependency trees that vendor other repositories.
+var DefaultScanIgnore = []string{".*", "node_modules", "vendor"}
+
+// FindRepositories returns the top-level directories of the Git repositories
+// under root, sorted, looking at most maxDepth levels below root (0 for no
+// limit). Directories are read concurrently. It does not descend into
+// repositories, directories matching the ignore patterns or directories it
+// cannot read, so nested repositories are not reported. Symlinks are not
+// followed.
+func (f *FileService) FindRepositories(root string, maxDepth int) ([]string, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.ReadDir(root); err != nil {
+		return nil, err
+	}
+
+	var (
+		mu    sync.Mutex
+		repos []string
+		wg    sync.WaitGroup
+	)
+	// Bound the number of directories being read at once, not the goroutines
+	slots := make(chan struct{}, 4*runtime.NumCPU())
+
+	var visit func(dir string, depth int)
+	visit = func(dir string, depth int) {
+		defer wg.Done()
+		slots <- struct{}{}
+		_, found, err := detectInDir(dir)
+		var entries []os.DirEntry
+		if err == nil && !found && (maxDepth <= 0 || depth < maxDepth) {
+			entries, err = os.ReadDir(dir)
+		}
+		<-slots
+
+		if err != nil {
+			return
+		}
+		if found {
+			mu.Lock()
+			repos = append(repos, dir)
+			mu.Unlock()
+			return
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && !f.ignored(entry.Name()) {
+				wg.Add(1)
+				go visit(filepath.Join(dir, entry.Name()), depth+1)
+			}
+		}
+	}
+
+	wg.Add(1)
+	visit(root, 0)
+	wg.Wait()
+
+	sort.Strings(repos)
+	return repos, nil
+}
+
+func (f *FileService) ignored(name string) bool {
+	for _, pattern := range f.scanIgnore {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// AssignAliases derives an alias for every repository path that is unique
+// among paths and not in taken. The alias is the directory name without a
+// ".git" suffix. Colliding repositories are prefixed with their parent
+// directories one at a time ("org-repo", then "src-org-repo"), and any that
+// still collide get a numeric suffix in path order. The result does not
+// depend on the order of paths.
+func AssignAliases(paths []string, taken map[string]bool) map[string]string {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+
+	components := make(map[string][]string, len(sorted))
+	levels := make(map[string]int, len(sorted))
+	for _, path := range sorted {
+		parts := pathComponents(filepath.ToSlash(path))
+		if n := len(parts); n > 0 {
+			parts[n-1] = strings.TrimSuffix(parts[n-1], ".git")
+		}
+		components[path] = parts
+	}
+	aliasAt := func(path string) string {
+		parts := components[path]
+		start := max(len(parts)-1-levels[path], 0)
+		return strings.Join(parts[start:], "-")
+	}
+
+	for changed := true; changed; {
+		changed = false
+		users := map[string][]string{}
+		for _, path := range sorted {
+			alias := aliasAt(path)
+			users[alias] = append(users[alias], path)
+		}
+		for alias, group := range users {
+			if len(group) == 1 && !taken[alias] {
+				continue
+			}
+			for _, path := range group {
+				if levels[path] < len(components[path])-1 {
+					levels[path]++
+					changed = true
+				}
+			}
+		}
+	}
+
+	used := make(map[string]bool, len(taken)+len(sorted))
+	for alias := range taken {
+		used[alias] = true
+	}
+	aliases := make(map[string]string, len(sorted))
+	for _, path := range sorted {
+		alias := aliasAt(path)
+		for n := 2; used[alias]; n++ {
+			alias = aliasAt(path) + "-" + strconv.Itoa(n)
+		}
+		used[alias] = true
+		aliases[path] = alias
+	}
+	return aliases
+}
@@ -0,0 +1,109 @@
+package libs_test
+
+import (
+	"gs/libs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileService_FindRepositories(t *testing.T) {
+	root := t.TempDir()
+	mustGitDir(t, filepath.Join(root, "org", "api", ".git"))
+	mustGitDir(t, filepath.Join(root, "org", "api", "vendored", ".git"))
+	mustGitDir(t, filepath.Join(root, "solo", ".git"))
+	mustGitDir(t, filepath.Join(root, "a", "b", "c", "deep", ".git"))
+	mustGitDir(t, filepath.Join(root, ".cache", "hidden", ".git"))
+	mustGitDir(t, filepath.Join(root, "web", "node_modules", "dep", ".git"))
+	mustGitDir(t, filepath.Join(root, "build", "out", ".git"))
+	mustGitDir(t, filepath.Join(root, "bare.git"))
+	if err := os.MkdirAll(filepath.Join(root, "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     []libs.FileServiceOption
+		maxDepth int
+		want     []string
+	}{
+		{
+			name:     "limited depth",
+			maxDepth: 2,
+			want:     []string{"bare.git", "build/out", "org/api", "solo"},
+		},
+		{
+			name: "unlimited depth",
+			want: []string{"a/b/c/deep", "bare.git", "build/out", "org/api", "solo"},
+		},
+		{
+			name:     "configured ignore patterns",
+			opts:     []libs.FileServiceOption{libs.WithScanIgnore([]string{"b*", "org"})},
+			maxDepth: 3,
+			want:     []string{".cache/hidden", "solo", "web/node_modules/dep"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, err := libs.NewFileService(tt.opts...).FindRepositories(root, tt.maxDepth)
+			assert.NoError(t, err)
+
+			want := make([]string, 0, len(tt.want))
+			for _, rel := range tt.want {
+				want = append(want, filepath.Join(root, filepath.FromSlash(rel)))
+			}
+			assert.Equal(t, want, repos)
+		})
+	}
+
+	t.Run("missing root", func(t *testing.T) {
+		_, err := libs.NewFileService().FindRepositories(filepath.Join(root, "missing"), 0)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestAssignAliases(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		taken map[string]bool
+		want  map[string]string
+	}{
+		{
+			name:  "directory names",
+			paths: []string{"/src/api", "/src/web", "/srv/mirror.git"},
+			want:  map[string]string{"/src/api": "api", "/src/web": "web", "/srv/mirror.git": "mirror"},
+		},
+		{
+			name:  "parent prefix for repositories sharing a name",
+			paths: []string{"/src/other/api", "/src/acme/api", "/src/web"},
+			want:  map[string]string{"/src/acme/api": "acme-api", "/src/other/api": "other-api", "/src/web": "web"},
+		},
+		{
+			name:  "parent prefix for a name already taken",
+			paths: []string{"/src/acme/api", "/src/web"},
+			taken: map[string]bool{"api": true},
+			want:  map[string]string{"/src/acme/api": "acme-api", "/src/web": "web"},
+		},
+		{
+			name:  "more parents until unique",
+			paths: []string{"/home/a/org/api", "/home/b/org/api"},
+			want:  map[string]string{"/home/a/org/api": "a-org-api", "/home/b/org/api": "b-org-api"},
+		},
+		{
+			name:  "numeric suffix as a last resort",
+			paths: []string{"/api", "/x/api"},
+			taken: map[string]bool{"x-api": true},
+			want:  map[string]string{"/api": "api", "/x/api": "x-api-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, libs.AssignAliases(tt.paths, tt.taken))
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package libs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ExecStatus is the outcome of running a command in one project.
+type ExecStatus string
+
+const (
+	ExecOK      ExecStatus = "ok"
+	ExecFailed  ExecStatus = "failed"
+	ExecError   ExecStatus = "error"
+	ExecSkipped ExecStatus = "skipped"
+)
+
+// ExecOptions controls how ExecService.Run runs a command.
+type ExecOptions struct {
+	// Parallel is how many projects run at once; values below 1 mean 1.
+	Parallel int
+	// FailFast stops starting new projects and kills running ones after the
+	// first failure.
+	FailFast bool
+	// Stdout and Stderr receive the output of every project, one complete
+	// line at a time, each prefixed with the alias.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// ExecResult describes how the command went in one project.
+type ExecResult struct {
+	Alias    string
+	Path     string
+	Status   ExecStatus
+	ExitCode int
+	Duration time.Duration
+	// Err is set when the command could not be run at all.
+	Err error
+}
+
+type ExecService struct {
+}
+
+func NewExecService() *ExecService {
+	return &ExecService{}
+}
+
+// Run runs command in the directory of every project and returns one result
+// per project, in the order given. Cancelling ctx kills running commands and
+// skips the rest.
+func (s *ExecService) Run(ctx context.Context, projects []Entry, command []string, opts ExecOptions) []ExecResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	width := 0
+	for _, project := range projects {
+		width = max(width, utf8.RuneCountInString(project.Alias))
+	}
+
+	results := make([]ExecResult, len(projects))
+	slots := make(chan struct{}, max(opts.Parallel, 1))
+	var wg sync.WaitGroup
+	for i, project := range projects {
+		results[i] = ExecResult{Alias: project.Alias, Path: project.Path, Status: ExecSkipped}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+		if ctx.Err() != nil {
+			<-slots
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, project Entry) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			prefix := fmt.Sprintf("%-*s | ", width, project.Alias)
+			stdout := &prefixWriter{mu: &mu, out: opts.Stdout, prefix: prefix}
+			stderr := &prefixWriter{mu: &mu, out: opts.Stderr, prefix: prefix}
+			result := runCommand(ctx, project, command, stdout, stderr)
+			stdout.Flush()
+			stderr.Flush()
+
+			results[i] = result
+			if opts.FailFast && result.Status != ExecOK {
+				cancel()
+			}
+		}(i, project)
+	}
+	wg.Wait()
+	return results
+}
+
+func runCommand(ctx context.Context, project Entry, command []string, stdout, stderr io.Writer) ExecResult {
+	result := ExecResult{Alias: project.Alias, Path: project.Path}
+	start := time.Now()
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = project.Path
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	result.Duration = time.Since(start)
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		result.Status = ExecOK
+	case errors.As(err, &exitErr):
+		result.Status = ExecFailed
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		result.Status = ExecError
+		result.ExitCode = -1
+		result.Err = err
+		fmt.Fprintf(stderr, "%v\n", err)
+	}
+	return result
+}
+
+// prefixWriter writes complete lines to out, each preceded by prefix, so that
+// output from projects running in parallel never mixes within a line.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.writeLine(w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a final line that did not end in a newline.
+func (w *prefixWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := append(w.buf, '\n')
+	w.buf = nil
+	return w.writeLine(line)
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	if w.out == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := io.WriteString(w.out, w.prefix+string(line))
+	return err
+}
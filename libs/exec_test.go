@@ -0,0 +1,110 @@
+package libs_test
+
+import (
+	"bytes"
+	"context"
+	"gs/libs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecService_Run(t *testing.T) {
+	root := t.TempDir()
+	project := func(alias string) libs.Entry {
+		return libs.Entry{Alias: alias, Record: libs.Record{Path: root}}
+	}
+
+	tests := []struct {
+		name       string
+		projects   []libs.Entry
+		command    []string
+		opts       libs.ExecOptions
+		wantStatus []libs.ExecStatus
+		wantCodes  []int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{
+			name:       "prefixes every line with the alias",
+			projects:   []libs.Entry{project("api"), project("billing")},
+			command:    []string{"sh", "-c", "printf 'one\\ntwo'"},
+			opts:       libs.ExecOptions{Parallel: 2},
+			wantStatus: []libs.ExecStatus{libs.ExecOK, libs.ExecOK},
+			wantCodes:  []int{0, 0},
+			wantStdout: []string{
+				"api     | one",
+				"api     | two",
+				"billing | one",
+				"billing | two",
+			},
+		},
+		{
+			name:       "runs in the project directory",
+			projects:   []libs.Entry{project("api")},
+			command:    []string{"pwd"},
+			wantStatus: []libs.ExecStatus{libs.ExecOK},
+			wantCodes:  []int{0},
+			wantStdout: []string{"api | " + root},
+		},
+		{
+			name:       "keeps going after a failure",
+			projects:   []libs.Entry{project("api"), project("billing")},
+			command:    []string{"sh", "-c", "echo oops >&2; exit 3"},
+			wantStatus: []libs.ExecStatus{libs.ExecFailed, libs.ExecFailed},
+			wantCodes:  []int{3, 3},
+			wantStderr: []string{"api     | oops", "billing | oops"},
+		},
+		{
+			name:       "skips the rest after a failure with fail fast",
+			projects:   []libs.Entry{project("api"), project("billing"), project("docs")},
+			command:    []string{"false"},
+			opts:       libs.ExecOptions{Parallel: 1, FailFast: true},
+			wantStatus: []libs.ExecStatus{libs.ExecFailed, libs.ExecSkipped, libs.ExecSkipped},
+			wantCodes:  []int{1, 0, 0},
+		},
+		{
+			name: "reports a missing directory as an error",
+			projects: []libs.Entry{
+				{Alias: "gone", Record: libs.Record{Path: filepath.Join(root, "gone")}},
+				project("api"),
+			},
+			command:    []string{"true"},
+			wantStatus: []libs.ExecStatus{libs.ExecError, libs.ExecOK},
+			wantCodes:  []int{-1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			tt.opts.Stdout, tt.opts.Stderr = &stdout, &stderr
+
+			results := libs.NewExecService().Run(context.Background(), tt.projects, tt.command, tt.opts)
+
+			assert.Len(t, results, len(tt.projects))
+			for i, result := range results {
+				assert.Equal(t, tt.projects[i].Alias, result.Alias)
+				assert.Equal(t, tt.wantStatus[i], result.Status, result.Alias)
+				assert.Equal(t, tt.wantCodes[i], result.ExitCode, result.Alias)
+				assert.Equal(t, result.Status == libs.ExecError, result.Err != nil, result.Alias)
+			}
+			// Projects run concurrently, so only the set of lines is stable
+			if tt.wantStdout != nil {
+				assert.Equal(t, tt.wantStdout, sortedLines(stdout.String()))
+			}
+			if tt.wantStderr != nil {
+				assert.Equal(t, tt.wantStderr, sortedLines(stderr.String()))
+			}
+		})
+	}
+}
+
+func sortedLines(s string) []string {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	sort.Strings(lines)
+	return lines
+}
@@ -1,29 +1,70 @@
 package libs
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
+var ErrNotGitRepository = errors.New("not inside a Git repository")
+
+// RepoKind tells how a Git repository was found.
+type RepoKind string
+
+const (
+	RepoKindWorkTree  RepoKind = "work tree"
+	RepoKindBare      RepoKind = "bare"
+	RepoKindWorktree  RepoKind = "linked worktree"
+	RepoKindSubmodule RepoKind = "submodule"
+	RepoKindGitDirEnv RepoKind = "GIT_DIR"
+)
+
+// GitRepository describes the repository enclosing a path.
+type GitRepository struct {
+	Kind RepoKind
+	// Root is the top-level directory of the work tree, or the Git directory
+	// itself for bare repositories.
+	Root string
+	// GitDir is the directory holding HEAD, refs and objects.
+	GitDir string
+}
+
 type FileService struct {
+	scanIgnore []string
 }
 
-func NewFileService() *FileService {
-	return &FileService{}
+// FileServiceOption customises a FileService.
+type FileServiceOption func(*FileService)
+
+// WithScanIgnore replaces DefaultScanIgnore as the directory name patterns
+// that FindRepositories does not descend into.
+func WithScanIgnore(patterns []string) FileServiceOption {
+	return func(f *FileService) {
+		f.scanIgnore = patterns
+	}
 }
 
-func (f *FileService) GetCurrentPath() (string, error) {
-	ex, err := os.Executable()
-	if err != nil {
-		return "", err
+func NewFileService(opts ...FileServiceOption) *FileService {
+	f := &FileService{scanIgnore: DefaultScanIgnore}
+	for _, opt := range opts {
+		opt(f)
 	}
-	return filepath.Dir(ex), nil
+	return f
+}
+
+// GetCurrentPath returns the directory the user is running gs from.
+func (f *FileService) GetCurrentPath() (string, error) {
+	return os.Getwd()
 }
 
-func (f *FileService) GetParentFolderName(path string) string {
-	return filepath.Base(filepath.Dir(path))
+// GetFolderName returns the last element of path, which is used as the
+// default alias for a project.
+func (f *FileService) GetFolderName(path string) string {
+	return filepath.Base(filepath.Clean(path))
 }
 
 func (f *FileService) CheckIfPathExists(path string) (bool, error) {
@@ -36,3 +77,219 @@ func (f *FileService) CheckIfPathExists(path string) (bool, error) {
 	}
 	return false, err
 }
+
+// NormalizePath turns a user-supplied path into the canonical form gs stores:
+// "~" and environment variables are expanded, the path is made absolute and
+// cleaned, and symlinks are resolved when resolveSymlinks is set.
+func (f *FileService) NormalizePath(path string, resolveSymlinks bool) (string, error) {
+	expanded, err := expandPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	absolute, err := filepath.Abs(expanded)
+	if err != nil {
+		return "", err
+	}
+
+	if !resolveSymlinks {
+		return absolute, nil
+	}
+	return filepath.EvalSymlinks(absolute)
+}
+
+// CanonicalizeStoredPath normalises a path that was stored by an older
+// version of gs. Relative paths cannot be recovered because the directory
+// they were added from is unknown, and symlinks are resolved only when the
+// target still exists.
+func (f *FileService) CanonicalizeStoredPath(path string) (string, error) {
+	expanded, err := expandPath(path)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(expanded) {
+		return "", fmt.Errorf("cannot canonicalise relative path %s", path)
+	}
+
+	cleaned := filepath.Clean(expanded)
+	if resolved, err := filepath.EvalSymlinks(cleaned); err == nil {
+		return resolved, nil
+	}
+	return cleaned, nil
+}
+
+func expandPath(path string) (string, error) {
+	path = os.ExpandEnv(path)
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// FindRepoRoot returns the top-level directory of the repository enclosing
+// path. It returns ErrNotGitRepository when no repository encloses path.
+func (f *FileService) FindRepoRoot(path string) (string, error) {
+	repo, err := f.DetectGitRepository(path)
+	if err != nil {
+		return "", err
+	}
+	return repo.Root, nil
+}
+
+// DetectGitRepository finds the repository enclosing path the way git does:
+// GIT_DIR wins when set, otherwise it walks up from path looking for a .git
+// directory, a .git file holding a "gitdir:" pointer (linked worktrees and
+// submodules), or a bare repository. Errors wrapping ErrNotGitRepository
+// explain why path is not in a repository.
+func (f *FileService) DetectGitRepository(path string) (GitRepository, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return GitRepository{}, err
+	}
+
+	if gitDir := os.Getenv("GIT_DIR"); gitDir != "" {
+		return detectFromGitDirEnv(dir, gitDir)
+	}
+
+	for current := dir; ; {
+		repo, found, err := detectInDir(current)
+		if err != nil || found {
+			return repo, err
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return GitRepository{}, fmt.Errorf("%w: no .git found in %s or any parent directory", ErrNotGitRepository, dir)
+		}
+		current = parent
+	}
+}
+
+// GitBranch returns the branch checked out in the repository enclosing path,
+// or the abbreviated commit when HEAD is detached.
+func (f *FileService) GitBranch(path string) (string, error) {
+	repo, err := f.DetectGitRepository(path)
+	if err != nil {
+		return "", err
+	}
+	head, err := os.ReadFile(filepath.Join(repo.GitDir, "HEAD"))
+	if err != nil {
+		return "", err
+	}
+
+	ref := strings.TrimSpace(string(head))
+	if target, ok := strings.CutPrefix(ref, "ref: "); ok {
+		return strings.TrimPrefix(target, "refs/heads/"), nil
+	}
+	return shortHash(ref), nil
+}
+
+func detectFromGitDirEnv(dir, gitDir string) (GitRepository, error) {
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	if !isGitDir(gitDir) {
+		return GitRepository{}, fmt.Errorf("%w: GIT_DIR=%s is not a Git directory", ErrNotGitRepository, gitDir)
+	}
+
+	root := dir
+	if workTree := os.Getenv("GIT_WORK_TREE"); workTree != "" {
+		root = workTree
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(dir, root)
+		}
+	}
+	return GitRepository{Kind: RepoKindGitDirEnv, Root: filepath.Clean(root), GitDir: filepath.Clean(gitDir)}, nil
+}
+
+func detectInDir(dir string) (GitRepository, bool, error) {
+	dotGit := filepath.Join(dir, ".git")
+	info, err := os.Stat(dotGit)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return GitRepository{}, false, err
+	case info.IsDir():
+		if isGitDir(dotGit) {
+			return GitRepository{Kind: RepoKindWorkTree, Root: dir, GitDir: dotGit}, true, nil
+		}
+	default:
+		gitDir, ok, err := readGitFile(dotGit)
+		if err != nil {
+			return GitRepository{}, false, err
+		}
+		if ok {
+			if !isGitDir(gitDir) {
+				return GitRepository{}, false, fmt.Errorf("%w: %s points at %s, which is not a Git directory", ErrNotGitRepository, dotGit, gitDir)
+			}
+			return GitRepository{Kind: gitFileKind(gitDir), Root: dir, GitDir: gitDir}, true, nil
+		}
+	}
+
+	if isGitDir(dir) {
+		return GitRepository{Kind: RepoKindBare, Root: dir, GitDir: dir}, true, nil
+	}
+	return GitRepository{}, false, nil
+}
+
+// readGitFile parses a .git file of the form "gitdir: <path>". A file without
+// that prefix is not a pointer and is ignored, as git does.
+func readGitFile(path string) (string, bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, err
+	}
+	rest, ok := bytes.CutPrefix(content, []byte("gitdir:"))
+	if !ok {
+		return "", false, nil
+	}
+
+	gitDir := strings.TrimSpace(string(rest))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+	return filepath.Clean(gitDir), true, nil
+}
+
+func gitFileKind(gitDir string) RepoKind {
+	parent := filepath.Base(filepath.Dir(gitDir))
+	switch {
+	case parent == "worktrees":
+		return RepoKindWorktree
+	case strings.Contains(filepath.ToSlash(gitDir), "/modules/"):
+		return RepoKindSubmodule
+	}
+	return RepoKindWorkTree
+}
+
+// isGitDir reports whether dir looks like a Git directory: a HEAD file plus
+// objects and refs, which linked worktrees share through a commondir file.
+func isGitDir(dir string) bool {
+	if !isFile(filepath.Join(dir, "HEAD")) {
+		return false
+	}
+
+	common := dir
+	if content, err := os.ReadFile(filepath.Join(dir, "commondir")); err == nil {
+		common = strings.TrimSpace(string(content))
+		if !filepath.IsAbs(common) {
+			common = filepath.Join(dir, common)
+		}
+	}
+	return isDir(filepath.Join(common, "objects")) && isDir(filepath.Join(common, "refs"))
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,430 @@
+package libs_test
+
+import (
+	"errors"
+	"gs/libs"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileService_FindRepoRoot(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupFS   func(t *testing.T, root string)
+		start     string
+		wantRoot  string
+		wantErrIs error
+	}{
+		{
+			name: "repository root itself",
+			setupFS: func(t *testing.T, root string) {
+				mustGitDir(t, filepath.Join(root, "repo", ".git"))
+			},
+			start:    "repo",
+			wantRoot: "repo",
+		},
+		{
+			name: "nested directory inside repository",
+			setupFS: func(t *testing.T, root string) {
+				mustGitDir(t, filepath.Join(root, "repo", ".git"))
+				mustMkdir(t, filepath.Join(root, "repo", "src", "pkg"))
+			},
+			start:    "repo/src/pkg",
+			wantRoot: "repo",
+		},
+		{
+			name: "linked worktree with .git file",
+			setupFS: func(t *testing.T, root string) {
+				mustWorktreeGitDir(t, filepath.Join(root, "repo", ".git"), "worktree")
+				mustMkdir(t, filepath.Join(root, "worktree", "sub"))
+				mustWriteFile(t, filepath.Join(root, "worktree", ".git"), "gitdir: ../repo/.git/worktrees/worktree\n")
+			},
+			start:    "worktree/sub",
+			wantRoot: "worktree",
+		},
+		{
+			name: "submodule inside a superproject stops at the submodule",
+			setupFS: func(t *testing.T, root string) {
+				mustGitDir(t, filepath.Join(root, "super", ".git"))
+				mustGitDir(t, filepath.Join(root, "super", ".git", "modules", "lib"))
+				mustMkdir(t, filepath.Join(root, "super", "vendor", "lib", "src"))
+				mustWriteFile(t, filepath.Join(root, "super", "vendor", "lib", ".git"), "gitdir: ../../.git/modules/lib\n")
+			},
+			start:    "super/vendor/lib/src",
+			wantRoot: "super/vendor/lib",
+		},
+		{
+			name: "stray .git file without gitdir is ignored",
+			setupFS: func(t *testing.T, root string) {
+				mustGitDir(t, filepath.Join(root, "repo", ".git"))
+				mustMkdir(t, filepath.Join(root, "repo", "inner"))
+				mustWriteFile(t, filepath.Join(root, "repo", "inner", ".git"), "not a pointer\n")
+			},
+			start:    "repo/inner",
+			wantRoot: "repo",
+		},
+		{
+			name: "not inside a repository",
+			setupFS: func(t *testing.T, root string) {
+				mustMkdir(t, filepath.Join(root, "plain"))
+			},
+			start:     "plain",
+			wantErrIs: libs.ErrNotGitRepository,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GIT_DIR", "")
+			root := t.TempDir()
+			tt.setupFS(t, root)
+
+			service := libs.NewFileService()
+			got, err := service.FindRepoRoot(filepath.Join(root, tt.start))
+
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Errorf("FileService.FindRepoRoot() error = %v, want %v", err, tt.wantErrIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FileService.FindRepoRoot() unexpected error = %v", err)
+			}
+			if want := filepath.Join(root, tt.wantRoot); got != want {
+				t.Errorf("FileService.FindRepoRoot() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFileService_DetectGitRepository(t *testing.T) {
+	tests := []struct {
+		name        string
+		setupFS     func(t *testing.T, root string)
+		env         map[string]string
+		start       string
+		wantKind    libs.RepoKind
+		wantRoot    string
+		wantGitDir  string
+		wantErrIs   error
+		wantErrText string
+	}{
+		{
+			name: "normal repository",
+			setupFS: func(t *testing.T, root string) {
+				mustGitDir(t, filepath.Join(root, "repo", ".git"))
+			},
+			start:      "repo",
+			wantKind:   libs.RepoKindWorkTree,
+			wantRoot:   "repo",
+			wantGitDir: "repo/.git",
+		},
+		{
+			name: "bare repository",
+			setupFS: func(t *testing.T, root string) {
+				mustGitDir(t, filepath.Join(root, "repo.git"))
+			},
+			start:      "repo.git",
+			wantKind:   libs.RepoKindBare,
+			wantRoot:   "repo.git",
+			wantGitDir: "repo.git",
+		},
+		{
+			name: "linked worktree",
+			setupFS: func(t *testing.T, root string) {
+				mustWorktreeGitDir(t, filepath.Join(root, "repo", ".git"), "feature")
+				mustMkdir(t, filepath.Join(root, "feature"))
+				mustWriteFile(t, filepath.Join(root, "feature", ".git"), "gitdir: "+filepath.Join(root, "repo", ".git", "worktrees", "feature")+"\n")
+			},
+			start:      "feature",
+			wantKind:   libs.RepoKindWorktree,
+			wantRoot:   "feature",
+			wantGitDir: "repo/.git/worktrees/feature",
+		},
+		{
+			name: "submodule",
+			setupFS: func(t *testing.T, root string) {
+				mustGitDir(t, filepath.Join(root, "super", ".git"))
+				mustGitDir(t, filepath.Join(root, "super", ".git", "modules", "lib"))
+				mustMkdir(t, filepath.Join(root, "super", "lib"))
+				mustWriteFile(t, filepath.Join(root, "super", "lib", ".git"), "gitdir: ../.git/modules/lib\n")
+			},
+			start:      "super/lib",
+			wantKind:   libs.RepoKindSubmodule,
+			wantRoot:   "super/lib",
+			wantGitDir: "super/.git/modules/lib",
+		},
+		{
+			name: "GIT_DIR override with work tree",
+			setupFS: func(t *testing.T, root string) {
+				mustGitDir(t, filepath.Join(root, "dotfiles.git"))
+				mustMkdir(t, filepath.Join(root, "home"))
+			},
+			env:        map[string]string{"GIT_DIR": "dotfiles.git", "GIT_WORK_TREE": "home"},
+			start:      ".",
+			wantKind:   libs.RepoKindGitDirEnv,
+			wantRoot:   "home",
+			wantGitDir: "dotfiles.git",
+		},
+		{
+			name: "GIT_DIR override pointing at nothing",
+			setupFS: func(t *testing.T, root string) {
+				mustGitDir(t, filepath.Join(root, "repo", ".git"))
+			},
+			env:         map[string]string{"GIT_DIR": "missing.git"},
+			start:       "repo",
+			wantErrIs:   libs.ErrNotGitRepository,
+			wantErrText: "is not a Git directory",
+		},
+		{
+			name: "broken worktree pointer",
+			setupFS: func(t *testing.T, root string) {
+				mustMkdir(t, filepath.Join(root, "feature"))
+				mustWriteFile(t, filepath.Join(root, "feature", ".git"), "gitdir: ../gone/.git/worktrees/feature\n")
+			},
+			start:       "feature",
+			wantErrIs:   libs.ErrNotGitRepository,
+			wantErrText: "which is not a Git directory",
+		},
+		{
+			name: "empty .git directory is not a repository",
+			setupFS: func(t *testing.T, root string) {
+				mustMkdir(t, filepath.Join(root, "repo", ".git"))
+			},
+			start:       "repo",
+			wantErrIs:   libs.ErrNotGitRepository,
+			wantErrText: "no .git found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			tt.setupFS(t, root)
+
+			t.Setenv("GIT_DIR", "")
+			t.Setenv("GIT_WORK_TREE", "")
+			for key, value := range tt.env {
+				t.Setenv(key, filepath.Join(root, value))
+			}
+
+			service := libs.NewFileService()
+			repo, err := service.DetectGitRepository(filepath.Join(root, tt.start))
+
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("FileService.DetectGitRepository() error = %v, want %v", err, tt.wantErrIs)
+				}
+				if !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Errorf("FileService.DetectGitRepository() error = %v, want it to contain %q", err, tt.wantErrText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FileService.DetectGitRepository() unexpected error = %v", err)
+			}
+			want := libs.GitRepository{
+				Kind:   tt.wantKind,
+				Root:   filepath.Join(root, tt.wantRoot),
+				GitDir: filepath.Join(root, tt.wantGitDir),
+			}
+			if repo != want {
+				t.Errorf("FileService.DetectGitRepository() = %+v, want %+v", repo, want)
+			}
+		})
+	}
+}
+
+func TestFileService_NormalizePath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GS_TEST_SRC", filepath.Join(home, "src"))
+	mustGitDir(t, filepath.Join(home, "src", "real", ".git"))
+	if err := os.Symlink(filepath.Join(home, "src", "real"), filepath.Join(home, "link")); err != nil {
+		t.Fatal(err)
+	}
+	// Resolve the temp dir itself, which may sit behind a symlink such as /tmp on macOS
+	realHome, err := filepath.EvalSymlinks(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(filepath.Join(home, "src"))
+
+	tests := []struct {
+		name            string
+		path            string
+		resolveSymlinks bool
+		want            string
+		wantErrIs       error
+	}{
+		{
+			name: "tilde is expanded",
+			path: "~/src/real",
+			want: filepath.Join(home, "src", "real"),
+		},
+		{
+			name: "environment variables are expanded",
+			path: "$GS_TEST_SRC/real",
+			want: filepath.Join(home, "src", "real"),
+		},
+		{
+			name: "relative path is made absolute and cleaned",
+			path: "./real/../real/",
+			want: filepath.Join(realHome, "src", "real"),
+		},
+		{
+			name: "symlink is kept",
+			path: "~/link",
+			want: filepath.Join(home, "link"),
+		},
+		{
+			name:            "symlink is resolved",
+			path:            "~/link",
+			resolveSymlinks: true,
+			want:            filepath.Join(realHome, "src", "real"),
+		},
+		{
+			name:            "missing path cannot be resolved",
+			path:            "~/missing",
+			resolveSymlinks: true,
+			wantErrIs:       fs.ErrNotExist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := libs.NewFileService()
+			got, err := service.NormalizePath(tt.path, tt.resolveSymlinks)
+
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Errorf("FileService.NormalizePath() error = %v, want %v", err, tt.wantErrIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FileService.NormalizePath() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FileService.NormalizePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileService_CanonicalizeStoredPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	mustMkdir(t, filepath.Join(home, "src", "real"))
+	realHome, err := filepath.EvalSymlinks(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := libs.NewFileService()
+
+	got, err := service.CanonicalizeStoredPath("~/src/./real/")
+	if err != nil || got != filepath.Join(realHome, "src", "real") {
+		t.Errorf("FileService.CanonicalizeStoredPath() = %v, %v", got, err)
+	}
+
+	got, err = service.CanonicalizeStoredPath("~/src/gone/../gone")
+	if err != nil || got != filepath.Join(home, "src", "gone") {
+		t.Errorf("FileService.CanonicalizeStoredPath() of a missing path = %v, %v", got, err)
+	}
+
+	if _, err := service.CanonicalizeStoredPath("../relative"); err == nil {
+		t.Errorf("FileService.CanonicalizeStoredPath() of a relative path should fail")
+	}
+}
+
+func TestFileService_GitBranch(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	mustGitDir(t, filepath.Join(repo, ".git"))
+	mustMkdir(t, filepath.Join(repo, "cmd"))
+
+	detached := filepath.Join(root, "detached")
+	mustGitDir(t, filepath.Join(detached, ".git"))
+	mustWriteFile(t, filepath.Join(detached, ".git", "HEAD"), "0123456789abcdef0123456789abcdef01234567\n")
+
+	worktree := filepath.Join(root, "feature")
+	mustWorktreeGitDir(t, filepath.Join(repo, ".git"), "feature")
+	mustMkdir(t, worktree)
+	mustWriteFile(t, filepath.Join(worktree, ".git"), "gitdir: "+filepath.Join(repo, ".git", "worktrees", "feature")+"\n")
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{name: "branch of the enclosing repository", path: filepath.Join(repo, "cmd"), want: "main"},
+		{name: "detached HEAD", path: detached, want: "0123456"},
+		{name: "linked worktree has its own HEAD", path: worktree, want: "feature"},
+		{name: "not a repository", path: root, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GIT_DIR", "")
+			got, err := libs.NewFileService().GitBranch(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FileService.GitBranch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FileService.GitBranch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileService_GetFolderName(t *testing.T) {
+	service := libs.NewFileService()
+	for path, want := range map[string]string{
+		"/src/gitswitch":  "gitswitch",
+		"/src/gitswitch/": "gitswitch",
+		"gitswitch":       "gitswitch",
+	} {
+		if got := service.GetFolderName(path); got != want {
+			t.Errorf("FileService.GetFolderName(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+// mustGitDir creates the minimal layout git needs to recognise a Git directory.
+func mustGitDir(t *testing.T, path string) {
+	t.Helper()
+	mustMkdir(t, filepath.Join(path, "objects"))
+	mustMkdir(t, filepath.Join(path, "refs"))
+	mustWriteFile(t, filepath.Join(path, "HEAD"), "ref: refs/heads/main\n")
+}
+
+// mustWorktreeGitDir creates a repository at gitDir with a linked worktree
+// named name, whose private Git directory shares objects through commondir.
+func mustWorktreeGitDir(t *testing.T, gitDir, name string) {
+	t.Helper()
+	mustGitDir(t, gitDir)
+	worktreeDir := filepath.Join(gitDir, "worktrees", name)
+	mustMkdir(t, worktreeDir)
+	mustWriteFile(t, filepath.Join(worktreeDir, "HEAD"), "ref: refs/heads/"+name+"\n")
+	mustWriteFile(t, filepath.Join(worktreeDir, "commondir"), "../..\n")
+}
+
+func mustMkdir(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustWriteFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
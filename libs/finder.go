@@ -0,0 +1,74 @@
+package libs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+var ErrFinderNotFound = errors.New("finder not found in PATH")
+
+// BuiltinFinder names the built-in picker wherever a finder can be chosen.
+const BuiltinFinder = "builtin"
+
+// KnownFinders are the external finders whose options gs knows. Any other
+// command that reads lines on stdin and prints the chosen one works too.
+var KnownFinders = []string{"fzf", "sk", "peco"}
+
+// finderArgs returns the arguments for finder. Input lines are
+// "alias<TAB>path", so in a preview command {1} is the alias and {2} the path.
+func finderArgs(finder, preview string) []string {
+	switch finder {
+	case "fzf", "sk":
+		args := []string{"--delimiter", "\t", "--prompt", "gs> "}
+		if preview != "" {
+			args = append(args, "--preview", preview)
+		}
+		return args
+	case "peco":
+		return []string{"--prompt", "gs>"}
+	}
+	return nil
+}
+
+// runFinder streams items to the external finder and returns the item on the
+// line it prints. The finder draws on the terminal itself; stderr is passed
+// through. Leaving the finder without a choice gives ErrPickerCancelled.
+func runFinder(finder, preview string, items []PickerItem, stderr io.Writer) (PickerItem, error) {
+	path, err := exec.LookPath(finder)
+	if err != nil {
+		return PickerItem{}, fmt.Errorf("%w: %s", ErrFinderNotFound, finder)
+	}
+
+	var input, output bytes.Buffer
+	for _, item := range items {
+		fmt.Fprintf(&input, "%s\t%s\n", item.Alias, item.Path)
+	}
+
+	cmd := exec.Command(path, finderArgs(finder, preview)...)
+	cmd.Stdin = &input
+	cmd.Stdout = &output
+	cmd.Stderr = stderr
+	runErr := cmd.Run()
+
+	line, _, _ := strings.Cut(output.String(), "\n")
+	alias, _, _ := strings.Cut(line, "\t")
+	if alias == "" {
+		// Finders exit non-zero when cancelled or when nothing matched
+		var exitErr *exec.ExitError
+		if runErr == nil || errors.As(runErr, &exitErr) {
+			return PickerItem{}, ErrPickerCancelled
+		}
+		return PickerItem{}, fmt.Errorf("failed to run %s: %w", finder, runErr)
+	}
+
+	for _, item := range items {
+		if item.Alias == alias {
+			return item, nil
+		}
+	}
+	return PickerItem{}, fmt.Errorf("%s returned unknown alias %q", finder, alias)
+}
@@ -0,0 +1,157 @@
+package libs_test
+
+import (
+	"errors"
+	"gs/libs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeFinder installs an executable named name in front of PATH.
+// It records its arguments and input next to itself and runs script.
+func fakeFinder(t *testing.T, name, script string) string {
+	t.Helper()
+	dir := t.TempDir()
+	content := "#!/bin/sh\n" +
+		`printf '%s\n' "$@" > "$(dirname "$0")/args"` + "\n" +
+		`tee "$(dirname "$0")/input" | ` + script + "\n"
+	mustWriteExecutable(t, filepath.Join(dir, name), content)
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+func mustWriteExecutable(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustTempFile(t *testing.T, content string) *os.File {
+	t.Helper()
+	file, err := os.CreateTemp(t.TempDir(), "tty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
+func TestTerminalPicker_PickWith(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("fake finders need /bin/sh")
+	}
+	items := pickerItems[:3]
+
+	tests := []struct {
+		name       string
+		finder     string
+		configured string
+		script     string
+		stdin      string
+		want       string
+		wantArgs   []string
+		wantErrIs  error
+		wantErr    bool
+	}{
+		{
+			name:     "fzf gets the preview and the chosen line is parsed",
+			finder:   "fzf",
+			script:   "sed -n 2p",
+			want:     "web",
+			wantArgs: []string{"--delimiter", "\t", "--prompt", "gs> ", "--preview", "ls -p {2}"},
+		},
+		{
+			name:       "configured finder is used when none is given",
+			configured: "peco",
+			script:     "tail -n 1",
+			want:       "docs",
+			wantArgs:   []string{"--prompt", "gs>"},
+		},
+		{
+			name:     "unknown finders only get the lines",
+			finder:   "pick-one",
+			script:   "head -n 1",
+			want:     "api",
+			wantArgs: []string{""},
+		},
+		{
+			name:      "cancelled finder",
+			finder:    "fzf",
+			script:    "cat > /dev/null; exit 130",
+			wantErrIs: libs.ErrPickerCancelled,
+		},
+		{
+			name:    "finder printing an unknown alias",
+			finder:  "fzf",
+			script:  "echo 'ghost\t/nowhere'",
+			wantErr: true,
+		},
+		{
+			name:      "missing finder",
+			finder:    "skim-not-installed",
+			wantErrIs: libs.ErrFinderNotFound,
+		},
+		{
+			name:       "builtin finder falls back to the numbered prompt without a terminal",
+			finder:     libs.BuiltinFinder,
+			configured: "fzf",
+			stdin:      "3\n",
+			want:       "docs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Cases without a script run no external finder
+			var dir string
+			if tt.script != "" {
+				name := tt.finder
+				if name == "" {
+					name = tt.configured
+				}
+				dir = fakeFinder(t, name, tt.script)
+			}
+
+			picker := libs.NewTerminalPicker(mustTempFile(t, tt.stdin), mustTempFile(t, ""),
+				libs.WithFinder(tt.configured),
+				libs.WithFinderPreviews(map[string]string{"fzf": "ls -p {2}"}),
+			)
+			got, err := picker.PickWith(tt.finder, items)
+
+			if tt.wantErrIs != nil || tt.wantErr {
+				if err == nil || (tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs)) {
+					t.Fatalf("TerminalPicker.PickWith() error = %v, want %v", err, tt.wantErrIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TerminalPicker.PickWith() error = %v", err)
+			}
+			if got.Alias != tt.want {
+				t.Errorf("TerminalPicker.PickWith() = %v, want %v", got.Alias, tt.want)
+			}
+			if tt.script == "" {
+				return
+			}
+
+			args, _ := os.ReadFile(filepath.Join(dir, "args"))
+			if gotArgs := strings.Split(strings.TrimSuffix(string(args), "\n"), "\n"); strings.Join(gotArgs, "|") != strings.Join(tt.wantArgs, "|") {
+				t.Errorf("finder args = %q, want %q", gotArgs, tt.wantArgs)
+			}
+			input, _ := os.ReadFile(filepath.Join(dir, "input"))
+			wantInput := "api\t/src/backend/api\nweb\t/src/frontend/website\ndocs\t/home/me/documentation\n"
+			if string(input) != wantInput {
+				t.Errorf("finder input = %q, want %q", input, wantInput)
+			}
+		})
+	}
+}
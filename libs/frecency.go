@@ -0,0 +1,84 @@
+package libs
+
+import (
+	"sort"
+	"time"
+)
+
+// MaxTotalRank is the total rank across all aliases above which every rank
+// is aged, so that projects that are no longer used fade out of the ranking.
+const MaxTotalRank = 10000
+
+// ScoredEntry is an entry together with its frecency score.
+type ScoredEntry struct {
+	Entry
+	Score float64
+}
+
+// FrecencyScore combines how often and how recently a project was used,
+// following the weighting zoxide uses: recent visits count for more.
+func FrecencyScore(record Record, now time.Time) float64 {
+	if record.Rank <= 0 || record.LastUsed.IsZero() {
+		return 0
+	}
+
+	age := now.Sub(record.LastUsed)
+	switch {
+	case age < time.Hour:
+		return record.Rank * 4
+	case age < 24*time.Hour:
+		return record.Rank * 2
+	case age < 7*24*time.Hour:
+		return record.Rank / 2
+	}
+	return record.Rank / 4
+}
+
+// RankEntries scores entries at now and returns the ones that have been used,
+// best first. Ties are broken alphabetically by alias.
+func RankEntries(entries []Entry, now time.Time) []ScoredEntry {
+	var scored []ScoredEntry
+	for _, entry := range entries {
+		if score := FrecencyScore(entry.Record, now); score > 0 {
+			scored = append(scored, ScoredEntry{Entry: entry, Score: score})
+		}
+	}
+
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+		return scored[i].Alias < scored[j].Alias
+	})
+	return scored
+}
+
+// ageRanks scales every rank down once their sum passes maxTotal, keeping the
+// sum at 90% of maxTotal, and drops ranks that fall below 1 out of the
+// ranking. Aliases themselves are never removed. It returns the aliases
+// whose records changed.
+func ageRanks(records map[string]Record, maxTotal float64) []string {
+	total := 0.0
+	for _, record := range records {
+		total += record.Rank
+	}
+	if total <= maxTotal {
+		return nil
+	}
+
+	factor := 0.9 * maxTotal / total
+	var changed []string
+	for alias, record := range records {
+		if record.Rank == 0 {
+			continue
+		}
+		record.Rank *= factor
+		if record.Rank < 1 {
+			record.Rank = 0
+		}
+		records[alias] = record
+		changed = append(changed, alias)
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,90 @@
+package libs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFrecencyScore(t *testing.T) {
+	now := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		record Record
+		want   float64
+	}{
+		{name: "never used", record: Record{}, want: 0},
+		{name: "used within the hour", record: Record{Rank: 3, LastUsed: now.Add(-10 * time.Minute)}, want: 12},
+		{name: "used within the day", record: Record{Rank: 3, LastUsed: now.Add(-5 * time.Hour)}, want: 6},
+		{name: "used within the week", record: Record{Rank: 3, LastUsed: now.Add(-3 * 24 * time.Hour)}, want: 1.5},
+		{name: "used long ago", record: Record{Rank: 3, LastUsed: now.Add(-60 * 24 * time.Hour)}, want: 0.75},
+		{name: "aged out of the ranking", record: Record{Rank: 0, UseCount: 40, LastUsed: now}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FrecencyScore(tt.record, now); got != tt.want {
+				t.Errorf("FrecencyScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankEntries(t *testing.T) {
+	now := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Alias: "old", Record: Record{Rank: 100, LastUsed: now.Add(-60 * 24 * time.Hour)}},
+		{Alias: "recent", Record: Record{Rank: 10, LastUsed: now.Add(-time.Minute)}},
+		{Alias: "unused", Record: Record{}},
+		{Alias: "beta", Record: Record{Rank: 5, LastUsed: now.Add(-2 * time.Hour)}},
+		{Alias: "alpha", Record: Record{Rank: 5, LastUsed: now.Add(-3 * time.Hour)}},
+	}
+
+	var got []string
+	for _, entry := range RankEntries(entries, now) {
+		got = append(got, entry.Alias)
+	}
+
+	// old scores 25, recent 40, alpha and beta tie at 10 and sort alphabetically
+	want := []string{"recent", "old", "alpha", "beta"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RankEntries() = %v, want %v", got, want)
+	}
+}
+
+func TestAgeRanks(t *testing.T) {
+	t.Run("below the threshold nothing changes", func(t *testing.T) {
+		records := map[string]Record{"a": {Rank: 40}, "b": {Rank: 50}}
+		if changed := ageRanks(records, 100); changed != nil {
+			t.Errorf("ageRanks() changed %v, want nothing", changed)
+		}
+		if records["a"].Rank != 40 || records["b"].Rank != 50 {
+			t.Errorf("ageRanks() modified records below the threshold: %v", records)
+		}
+	})
+
+	t.Run("above the threshold ranks decay and tiny ones are pruned", func(t *testing.T) {
+		records := map[string]Record{
+			"busy":   {Rank: 199, UseCount: 199},
+			"rare":   {Rank: 1, UseCount: 1},
+			"unused": {},
+		}
+		changed := ageRanks(records, 100)
+
+		if !reflect.DeepEqual(changed, []string{"busy", "rare"}) {
+			t.Errorf("ageRanks() changed %v, want [busy rare]", changed)
+		}
+		// The total is brought down to 90% of the threshold
+		if got := records["busy"].Rank; got < 89.5 || got > 89.6 {
+			t.Errorf("ageRanks() busy rank = %v, want about 89.55", got)
+		}
+		if got := records["rare"].Rank; got != 0 {
+			t.Errorf("ageRanks() rare rank = %v, want it pruned to 0", got)
+		}
+		// Pruning only drops the rank; the alias and its history stay
+		if records["rare"].UseCount != 1 {
+			t.Errorf("ageRanks() lost the use count of a pruned record")
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package libs
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RepoIdentity tells repositories apart independently of where they are on
+// disk, so that a moved repository can be found again.
+type RepoIdentity struct {
+	// Remote is the URL of the "origin" remote, or of the first remote in
+	// alphabetical order when there is no origin.
+	Remote string
+	// RootCommit is the hash of the first commit in the history of HEAD.
+	RootCommit string
+}
+
+// IsZero reports whether nothing identifies the repository.
+func (i RepoIdentity) IsZero() bool {
+	return i.Remote == "" && i.RootCommit == ""
+}
+
+// Identity returns the identity stored in r.
+func (r Record) Identity() RepoIdentity {
+	return RepoIdentity{Remote: r.Remote, RootCommit: r.RootCommit}
+}
+
+// RepoIdentity reads the identity of the repository at path. A repository
+// without remotes or commits yields an identity with those fields empty.
+func (f *FileService) RepoIdentity(path string) (RepoIdentity, error) {
+	ctx := context.Background()
+	var identity RepoIdentity
+
+	out, err := git(ctx, path, "config", "--get-regexp", `^remote\..*\.url$`)
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		// git config exits with 1 when no key matches
+	case err != nil:
+		return RepoIdentity{}, err
+	default:
+		identity.Remote = pickRemote(string(out))
+	}
+
+	out, err = git(ctx, path, "rev-list", "--max-parents=0", "HEAD")
+	if err != nil {
+		if isUnbornHead(ctx, path) {
+			return identity, nil
+		}
+		return RepoIdentity{}, err
+	}
+	roots := strings.Fields(string(out))
+	if len(roots) > 0 {
+		// Merged histories have several roots; any fixed choice will do
+		sort.Strings(roots)
+		identity.RootCommit = roots[0]
+	}
+	return identity, nil
+}
+
+// pickRemote chooses a URL from 'git config --get-regexp' output of
+// "remote.<name>.url <url>" lines.
+func pickRemote(config string) string {
+	urls := map[string]string{}
+	var names []string
+	for _, line := range strings.Split(strings.TrimSpace(config), "\n") {
+		key, url, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "remote."), ".url")
+		if _, seen := urls[name]; !seen {
+			urls[name] = url
+			names = append(names, name)
+		}
+	}
+	if url, ok := urls["origin"]; ok {
+		return url
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return urls[names[0]]
+}
+
+// isUnbornHead reports whether HEAD names a branch without commits.
+func isUnbornHead(ctx context.Context, path string) bool {
+	_, err := git(ctx, path, "rev-parse", "--verify", "--quiet", "HEAD")
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == 1
+}
+
+// NormalizeRemoteURL reduces the ways of writing a remote URL to one, so that
+// "git@github.com:org/repo.git" and "https://github.com/org/repo" compare
+// equal. Local paths are cleaned.
+func NormalizeRemoteURL(url string) string {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return ""
+	}
+
+	rest, hasScheme := "", false
+	if i := strings.Index(url, "://"); i >= 0 {
+		rest, hasScheme = url[i+3:], true
+	}
+	switch {
+	case hasScheme:
+		url = rest
+	case strings.HasPrefix(url, "/") || strings.HasPrefix(url, "."):
+		return filepath.Clean(url)
+	default:
+		// scp-like syntax: [user@]host:path
+		if host, path, ok := strings.Cut(url, ":"); ok && !strings.Contains(host, "/") {
+			url = host + "/" + path
+		}
+	}
+
+	if at := strings.Index(url, "@"); at >= 0 && at < strings.Index(url+"/", "/") {
+		url = url[at+1:]
+	}
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	return strings.ToLower(url)
+}
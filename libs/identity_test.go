@@ -0,0 +1,103 @@
+package libs_test
+
+import (
+	"gs/libs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileService_RepoIdentity(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+
+	root := t.TempDir()
+	withRemotes := filepath.Join(root, "with-remotes")
+	gitRun(t, root, "init", "-q", withRemotes)
+	gitRun(t, withRemotes, "commit", "-q", "--allow-empty", "-m", "first")
+	gitRun(t, withRemotes, "commit", "-q", "--allow-empty", "-m", "second")
+	gitRun(t, withRemotes, "remote", "add", "upstream", "https://example.com/upstream/repo.git")
+	gitRun(t, withRemotes, "remote", "add", "origin", "git@example.com:org/repo.git")
+
+	fork := filepath.Join(root, "fork")
+	gitRun(t, root, "init", "-q", fork)
+	gitRun(t, fork, "remote", "add", "zeta", "https://example.com/zeta.git")
+	gitRun(t, fork, "remote", "add", "alpha", "https://example.com/alpha.git")
+
+	empty := filepath.Join(root, "empty")
+	gitRun(t, root, "init", "-q", empty)
+
+	rootCommit := func(dir string) string {
+		out, err := exec.Command("git", "-C", dir, "rev-list", "--max-parents=0", "HEAD").Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    libs.RepoIdentity
+		wantErr bool
+	}{
+		{
+			name: "origin remote and root commit",
+			path: withRemotes,
+			want: libs.RepoIdentity{Remote: "git@example.com:org/repo.git", RootCommit: rootCommit(withRemotes)},
+		},
+		{
+			name: "first remote without origin and no commits",
+			path: fork,
+			want: libs.RepoIdentity{Remote: "https://example.com/alpha.git"},
+		},
+		{
+			name: "no remote and no commits",
+			path: empty,
+		},
+		{
+			name:    "not a repository",
+			path:    t.TempDir(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := libs.NewFileService().RepoIdentity(tt.path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, identity)
+		})
+	}
+}
+
+func TestNormalizeRemoteURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "git@github.com:Org/Repo.git", want: "github.com/org/repo"},
+		{url: "https://github.com/org/repo", want: "github.com/org/repo"},
+		{url: "https://user@github.com/org/repo.git/", want: "github.com/org/repo"},
+		{url: "ssh://git@github.com/org/repo.git", want: "github.com/org/repo"},
+		{url: "/srv/git/repo.git", want: "/srv/git/repo.git"},
+		{url: "../shared/./repo", want: "../shared/repo"},
+		{url: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, libs.NormalizeRemoteURL(tt.url))
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package libs
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ErrAmbiguousAlias is matched by AmbiguousAliasError through errors.Is.
+var ErrAmbiguousAlias = errors.New("ambiguous alias")
+
+// AmbiguousAliasError is returned by MatchAlias when several aliases match a
+// query equally well. Candidates are sorted alphabetically.
+type AmbiguousAliasError struct {
+	Query      string
+	Candidates []string
+}
+
+func (e *AmbiguousAliasError) Error() string {
+	return fmt.Sprintf("%s: %s matches %s", ErrAmbiguousAlias, e.Query, strings.Join(e.Candidates, ", "))
+}
+
+func (e *AmbiguousAliasError) Is(target error) bool {
+	return target == ErrAmbiguousAlias
+}
+
+// MatchKind orders the ways a query can match, from weakest to strongest.
+type MatchKind int
+
+const (
+	MatchNone MatchKind = iota
+	MatchFuzzy
+	MatchSubstring
+	MatchPrefix
+	MatchExact
+)
+
+// matchTarget is the part of an entry a query matched, from weakest to
+// strongest.
+type matchTarget int
+
+const (
+	// targetParent is a directory above the project, such as "src".
+	targetParent matchTarget = iota
+	targetBase
+	targetAlias
+)
+
+// matchQuality ranks a single match. A hit on the alias or the final path
+// component beats any hit on a parent directory, since those are shared by
+// many projects. Kinds are compared next, then a hit on the alias beats a hit
+// on the final path component, then the fuzzy score decides.
+type matchQuality struct {
+	kind   MatchKind
+	target matchTarget
+	score  int
+}
+
+func (q matchQuality) better(other matchQuality) bool {
+	if onParent, otherOnParent := q.target == targetParent, other.target == targetParent; onParent != otherOnParent {
+		return otherOnParent
+	}
+	if q.kind != other.kind {
+		return q.kind > other.kind
+	}
+	if q.target != other.target {
+		return q.target > other.target
+	}
+	return q.score > other.score
+}
+
+// Scores for fuzzy matching: every matched character counts, characters that
+// follow the previous match or start a word count extra.
+const (
+	fuzzyCharScore        = 1
+	fuzzyConsecutiveBonus = 2
+	fuzzyBoundaryBonus    = 3
+)
+
+// MatchAlias finds the entry that query refers to. An alias equal to query
+// always wins. A query of the form "<tag>/<name>" is matched against the
+// aliases carrying tag only. Otherwise aliases and the final components of
+// their paths are compared case-insensitively, preferring exact, prefix,
+// substring and then fuzzy (subsequence) matches. The parent directories in
+// the paths are only compared when nothing else matches. It returns
+// ErrAliasNotFound if nothing matches and an *AmbiguousAliasError if the best
+// matches are tied.
+func MatchAlias(query string, entries []Entry) (Entry, error) {
+	for _, entry := range entries {
+		if entry.Alias == query {
+			return entry, nil
+		}
+	}
+
+	if tag, name, ok := strings.Cut(query, "/"); ok {
+		var tagged []Entry
+		for _, entry := range entries {
+			if entry.HasTag(tag) {
+				tagged = append(tagged, entry)
+			}
+		}
+		entry, err := MatchAlias(name, tagged)
+		var ambiguous *AmbiguousAliasError
+		switch {
+		case errors.As(err, &ambiguous):
+			ambiguous.Query = query
+		case errors.Is(err, ErrAliasNotFound):
+			err = fmt.Errorf("%w: %s", ErrAliasNotFound, query)
+		}
+		return entry, err
+	}
+
+	type candidate struct {
+		entry   Entry
+		quality matchQuality
+	}
+	var candidates []candidate
+	for _, entry := range entries {
+		if quality := matchEntry(query, entry); quality.kind != MatchNone {
+			candidates = append(candidates, candidate{entry: entry, quality: quality})
+		}
+	}
+	if len(candidates) == 0 {
+		return Entry{}, fmt.Errorf("%w: %s", ErrAliasNotFound, query)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].quality != candidates[j].quality {
+			return candidates[i].quality.better(candidates[j].quality)
+		}
+		return candidates[i].entry.Alias < candidates[j].entry.Alias
+	})
+
+	best := candidates[0]
+	var tied []string
+	for _, c := range candidates {
+		if c.quality != best.quality {
+			break
+		}
+		tied = append(tied, c.entry.Alias)
+	}
+	if len(tied) > 1 {
+		return Entry{}, &AmbiguousAliasError{Query: query, Candidates: tied}
+	}
+	return best.entry, nil
+}
+
+// matchEntry returns the best match of query against the alias and the path
+// components of entry.
+func matchEntry(query string, entry Entry) matchQuality {
+	best := matchQuality{}
+	consider := func(target string, on matchTarget) {
+		kind, score := matchString(query, target)
+		if quality := (matchQuality{kind: kind, target: on, score: score}); kind != MatchNone && quality.better(best) {
+			best = quality
+		}
+	}
+
+	consider(entry.Alias, targetAlias)
+	components := pathComponents(entry.Path)
+	for i, component := range components {
+		on := targetParent
+		if i == len(components)-1 {
+			on = targetBase
+		}
+		consider(component, on)
+	}
+	return best
+}
+
+// matchString classifies how query matches target, ignoring case. The score is
+// only meaningful for fuzzy matches.
+func matchString(query, target string) (MatchKind, int) {
+	q, t := strings.ToLower(query), strings.ToLower(target)
+	switch {
+	case q == "":
+		return MatchNone, 0
+	case q == t:
+		return MatchExact, 0
+	case strings.HasPrefix(t, q):
+		return MatchPrefix, 0
+	case strings.Contains(t, q):
+		return MatchSubstring, 0
+	}
+	if score, ok := fuzzyScore([]rune(q), []rune(target)); ok {
+		return MatchFuzzy, score
+	}
+	return MatchNone, 0
+}
+
+// fuzzyScore returns the highest score of query as a subsequence of target and
+// whether it is a subsequence at all. query must already be lower case.
+func fuzzyScore(query, target []rune) (int, bool) {
+	if len(query) > len(target) {
+		return 0, false
+	}
+	// best[j] is the highest score with the current query rune at target[j],
+	// or -1 if it cannot be placed there.
+	best := make([]int, len(target))
+	for i, r := range query {
+		next := make([]int, len(target))
+		for j, c := range target {
+			next[j] = -1
+			if unicode.ToLower(c) != r {
+				continue
+			}
+			char := fuzzyCharScore
+			if isWordStart(target, j) {
+				char += fuzzyBoundaryBonus
+			}
+			if i == 0 {
+				next[j] = char
+				continue
+			}
+			for k := 0; k < j; k++ {
+				if best[k] < 0 {
+					continue
+				}
+				score := best[k] + char
+				if k == j-1 {
+					score += fuzzyConsecutiveBonus
+				}
+				if score > next[j] {
+					next[j] = score
+				}
+			}
+		}
+		best = next
+	}
+
+	top, ok := 0, false
+	for _, score := range best {
+		if score >= 0 && (!ok || score > top) {
+			top, ok = score, true
+		}
+	}
+	return top, ok
+}
+
+// isWordStart reports whether target[i] starts a word: it is the first rune,
+// follows a separator or is an upper case letter after a lower case one.
+func isWordStart(target []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, cur := target[i-1], target[i]
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+func pathComponents(path string) []string {
+	return strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '\\'
+	})
+}
@@ -0,0 +1,102 @@
+package libs_test
+
+import (
+	"errors"
+	"gs/libs"
+	"reflect"
+	"testing"
+)
+
+func TestMatchAlias(t *testing.T) {
+	entry := func(alias, path string) libs.Entry {
+		return libs.Entry{Alias: alias, Record: libs.Record{Path: path}}
+	}
+	entries := []libs.Entry{
+		entry("api", "/src/backend/api"),
+		entry("api-gateway", "/src/backend/gateway"),
+		entry("apis", "/src/backend/apis"),
+		entry("web", "/src/frontend/website"),
+		entry("docs", "/home/me/notes/documentation"),
+		entry("gitswitch", "/src/tools/gitswitch"),
+		entry("go-sdk", "/src/tools/go-sdk"),
+	}
+
+	tagged := []libs.Entry{
+		{Alias: "payments-gw", Record: libs.Record{Path: "/src/payments-gw", Tags: []string{"payments"}}},
+		{Alias: "billing", Record: libs.Record{Path: "/src/billing", Tags: []string{"payments"}}},
+		{Alias: "billing-ui", Record: libs.Record{Path: "/src/billing-ui", Tags: []string{"payments"}}},
+		{Alias: "gateway", Record: libs.Record{Path: "/src/gateway", Tags: []string{"platform"}}},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		entries        []libs.Entry
+		want           string
+		wantCandidates []string
+		wantErrIs      error
+	}{
+		{name: "exact alias wins over everything", query: "api", entries: entries, want: "api"},
+		{name: "exact alias ignoring case", query: "WEB", entries: entries, want: "web"},
+		{name: "unique alias prefix", query: "api-g", entries: entries, want: "api-gateway"},
+		{name: "ambiguous alias prefix", query: "ap", entries: entries, wantCandidates: []string{"api", "api-gateway", "apis"}},
+		{name: "exact path component beats alias prefix", query: "gateway", entries: entries, want: "api-gateway"},
+		{name: "alias prefix beats path component prefix", query: "do", entries: entries, want: "docs"},
+		{name: "unique path component prefix", query: "docu", entries: entries, want: "docs"},
+		{name: "unique substring", query: "ebsi", entries: entries, want: "web"},
+		{name: "fuzzy prefers word starts", query: "gs", entries: entries, want: "go-sdk"},
+		{name: "fuzzy across a path component", query: "gtwy", entries: entries, want: "api-gateway"},
+		{name: "parent directory as a fallback", query: "front", entries: entries, want: "web"},
+		{name: "fuzzy final component beats exact parent directory", query: "tools", entries: append([]libs.Entry{entry("tbs", "/src/ops/toolbox-scripts")}, entries...), want: "tbs"},
+		{name: "shared parent directory is ambiguous", query: "tools", entries: entries, wantCandidates: []string{"gitswitch", "go-sdk"}},
+		{name: "tied fuzzy matches are ambiguous", query: "pis", entries: []libs.Entry{entry("xpxixs", "/a"), entry("ypyiys", "/b")}, wantCandidates: []string{"xpxixs", "ypyiys"}},
+		{name: "tag scopes the match", query: "payments/gw", entries: tagged, want: "payments-gw"},
+		{name: "tag with exact alias", query: "platform/gateway", entries: tagged, want: "gateway"},
+		{name: "tag with ambiguous alias", query: "payments/b", entries: tagged, wantCandidates: []string{"billing", "billing-ui"}},
+		{name: "alias containing a slash wins over the tag", query: "payments/gw", entries: append([]libs.Entry{entry("payments/gw", "/x")}, tagged...), want: "payments/gw"},
+		{name: "unknown tag", query: "nope/gw", entries: tagged, wantErrIs: libs.ErrAliasNotFound},
+		{name: "no match", query: "zzz", entries: entries, wantErrIs: libs.ErrAliasNotFound},
+		{name: "empty query", query: "", entries: entries, wantErrIs: libs.ErrAliasNotFound},
+		{name: "no entries", query: "api", entries: nil, wantErrIs: libs.ErrAliasNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := libs.MatchAlias(tt.query, tt.entries)
+
+			if tt.wantCandidates != nil {
+				var ambiguous *libs.AmbiguousAliasError
+				if !errors.As(err, &ambiguous) {
+					t.Fatalf("MatchAlias() error = %v, want an AmbiguousAliasError", err)
+				}
+				if !errors.Is(err, libs.ErrAmbiguousAlias) {
+					t.Errorf("MatchAlias() error does not match ErrAmbiguousAlias")
+				}
+				if !reflect.DeepEqual(ambiguous.Candidates, tt.wantCandidates) {
+					t.Errorf("MatchAlias() candidates = %v, want %v", ambiguous.Candidates, tt.wantCandidates)
+				}
+				return
+			}
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Errorf("MatchAlias() error = %v, want %v", err, tt.wantErrIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MatchAlias() error = %v", err)
+			}
+			if got.Alias != tt.want {
+				t.Errorf("MatchAlias() = %v, want %v", got.Alias, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmbiguousAliasError(t *testing.T) {
+	err := &libs.AmbiguousAliasError{Query: "ap", Candidates: []string{"api", "apis"}}
+	want := "ambiguous alias: ap matches api, apis"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
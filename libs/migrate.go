@@ -0,0 +1,263 @@
+package libs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// SchemaVersion is the database layout this build of gs reads and writes.
+const SchemaVersion = 2
+
+// errStopIteration ends a ForEach early.
+var errStopIteration = errors.New("stop iteration")
+
+const (
+	schemaVersionKey = "schema_version"
+	// canonicalPathsKey is the marker the first path migration left behind
+	// before schema versions were recorded; it identifies version 1.
+	canonicalPathsKey = "canonical_paths"
+)
+
+// MigrationEnv carries what migrations need besides the transaction.
+type MigrationEnv struct {
+	KVBucketName string
+	// NormalizePath canonicalises a stored path. Paths it rejects are kept.
+	NormalizePath func(path string) (string, error)
+}
+
+// Migration upgrades the database from Version-1 to Version.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(tx Tx, env MigrationEnv) error
+}
+
+// migrations must stay ordered by Version, one per schema version.
+var migrations = []Migration{
+	{Version: 1, Description: "canonicalise stored paths", Up: migrateCanonicalPaths},
+	{Version: 2, Description: "store values as versioned records", Up: migrateRecords},
+}
+
+// BackupDB is a DB that can copy itself to another file.
+type BackupDB interface {
+	DB
+	Path() string
+	Backup(dest string) error
+}
+
+// MigrationResult describes what Migrate did.
+type MigrationResult struct {
+	From       int
+	To         int
+	Applied    []Migration
+	BackupPath string
+}
+
+type Migrator struct {
+	db  BackupDB
+	env MigrationEnv
+	now func() time.Time
+}
+
+func NewMigrator(db BackupDB, env MigrationEnv) *Migrator {
+	return &Migrator{db: db, env: env, now: time.Now}
+}
+
+// Version returns the schema version of the open database.
+func (m *Migrator) Version() (int, error) {
+	var version int
+	err := m.db.View(func(tx Tx) error {
+		var err error
+		version, err = readSchemaVersion(tx)
+		return err
+	})
+	return version, err
+}
+
+// Pending returns the migrations that Migrate would apply, in order.
+func (m *Migrator) Pending() ([]Migration, error) {
+	version, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+	return pendingMigrations(version)
+}
+
+// Migrate backs up the database file and then applies every pending
+// migration in a single transaction, so a failure leaves the database as it
+// was and the backup is removed again. A database without aliases has nothing
+// to migrate and is only stamped with the current schema version.
+func (m *Migrator) Migrate() (MigrationResult, error) {
+	from, err := m.Version()
+	if err != nil {
+		return MigrationResult{}, err
+	}
+	pending, err := pendingMigrations(from)
+	if err != nil || len(pending) == 0 {
+		return MigrationResult{From: from, To: from}, err
+	}
+
+	empty, err := m.isEmpty()
+	if err != nil {
+		return MigrationResult{}, err
+	}
+	if empty {
+		err := m.db.Update(func(tx Tx) error {
+			return writeSchemaVersion(tx, SchemaVersion)
+		})
+		if err != nil {
+			return MigrationResult{}, err
+		}
+		return MigrationResult{From: from, To: SchemaVersion}, nil
+	}
+
+	backupPath := fmt.Sprintf("%s.v%d-%s.bak", m.db.Path(), from, m.now().Format("20060102T150405"))
+	if err := m.db.Backup(backupPath); err != nil {
+		return MigrationResult{}, fmt.Errorf("failed to back up database: %w", err)
+	}
+
+	err = m.db.Update(func(tx Tx) error {
+		for _, migration := range pending {
+			if err := migration.Up(tx, m.env); err != nil {
+				return fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+			}
+		}
+		return writeSchemaVersion(tx, SchemaVersion)
+	})
+	if err != nil {
+		// The database is unchanged, so the backup is not needed. Keeping it
+		// would leave another full copy behind every time the migration is retried.
+		if removeErr := os.Remove(backupPath); removeErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to remove backup: %w", removeErr))
+		}
+		return MigrationResult{}, err
+	}
+
+	return MigrationResult{From: from, To: SchemaVersion, Applied: pending, BackupPath: backupPath}, nil
+}
+
+// isEmpty reports whether the alias bucket holds no aliases.
+func (m *Migrator) isEmpty() (bool, error) {
+	empty := true
+	err := m.db.View(func(tx Tx) error {
+		b := tx.Bucket([]byte(m.env.KVBucketName))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(key, value []byte) error {
+			empty = false
+			return errStopIteration
+		})
+	})
+	if err == errStopIteration {
+		err = nil
+	}
+	return empty, err
+}
+
+func pendingMigrations(version int) ([]Migration, error) {
+	if version > SchemaVersion {
+		return nil, fmt.Errorf("database schema version %d is newer than this gs supports (%d)", version, SchemaVersion)
+	}
+	var pending []Migration
+	for _, migration := range migrations {
+		if migration.Version > version {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+func readSchemaVersion(tx Tx) (int, error) {
+	meta := tx.Bucket([]byte(MetaBucketName))
+	if meta == nil {
+		return 0, fmt.Errorf("bucket %s not found", MetaBucketName)
+	}
+
+	if value := meta.Get([]byte(schemaVersionKey)); value != nil {
+		version, err := strconv.Atoi(string(value))
+		if err != nil {
+			return 0, fmt.Errorf("invalid schema version %q", value)
+		}
+		return version, nil
+	}
+	if meta.Get([]byte(canonicalPathsKey)) != nil {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func writeSchemaVersion(tx Tx, version int) error {
+	meta := tx.Bucket([]byte(MetaBucketName))
+	if meta == nil {
+		return fmt.Errorf("bucket %s not found", MetaBucketName)
+	}
+	return meta.Put([]byte(schemaVersionKey), []byte(strconv.Itoa(version)))
+}
+
+// updateValues rewrites every value in the alias bucket through update.
+// Values for which update returns nil are left alone.
+func updateValues(tx Tx, kvBucketName string, update func(value []byte) ([]byte, error)) error {
+	b := tx.Bucket([]byte(kvBucketName))
+	if b == nil {
+		return fmt.Errorf("bucket %s not found", kvBucketName)
+	}
+
+	// Collect first: bbolt does not allow modifying a bucket while iterating it
+	updates := map[string][]byte{}
+	err := b.ForEach(func(key, value []byte) error {
+		updated, err := update(value)
+		if err != nil {
+			return fmt.Errorf("alias %s: %w", key, err)
+		}
+		if updated != nil {
+			updates[string(key)] = updated
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for alias, value := range updates {
+		if err := b.Put([]byte(alias), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func migrateCanonicalPaths(tx Tx, env MigrationEnv) error {
+	return updateValues(tx, env.KVBucketName, func(value []byte) ([]byte, error) {
+		record, err := DecodeRecord(value)
+		if err != nil {
+			return nil, err
+		}
+		canonical, err := env.NormalizePath(record.Path)
+		if err != nil || canonical == record.Path {
+			return nil, nil
+		}
+		// Version 1 stored bare paths; keep that shape for records that had it
+		if !isEncodedRecord(value) {
+			return []byte(canonical), nil
+		}
+		record.Path = canonical
+		return EncodeRecord(record)
+	})
+}
+
+func migrateRecords(tx Tx, env MigrationEnv) error {
+	return updateValues(tx, env.KVBucketName, func(value []byte) ([]byte, error) {
+		if isEncodedRecord(value) {
+			return nil, nil
+		}
+		record, err := DecodeRecord(value)
+		if err != nil {
+			return nil, err
+		}
+		return EncodeRecord(record)
+	})
+}
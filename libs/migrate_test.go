@@ -0,0 +1,230 @@
+package libs_test
+
+import (
+	"errors"
+	"gs/libs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.etcd.io/bbolt"
+)
+
+func TestMigrator_Migrate(t *testing.T) {
+	// Fake canonicalisation so the test does not depend on the real home directory
+	normalize := func(path string) (string, error) {
+		if strings.HasPrefix(path, "relative") {
+			return "", errors.New("cannot canonicalise relative path")
+		}
+		return strings.Replace(path, "~", "/home/user", 1), nil
+	}
+
+	tests := []struct {
+		name        string
+		fixture     map[string]map[string]string
+		wantFrom    int
+		wantApplied []int
+		wantRecords map[string]libs.Record
+		wantErr     bool
+	}{
+		{
+			name: "upgrade from version 0: bare paths and no meta bucket",
+			fixture: map[string]map[string]string{
+				"test-bucket": {"alpha": "~/src/alpha", "beta": "/src/beta", "gamma": "relative/gamma"},
+			},
+			wantFrom:    0,
+			wantApplied: []int{1, 2},
+			wantRecords: map[string]libs.Record{
+				"alpha": {Version: libs.RecordVersion, Path: "/home/user/src/alpha"},
+				"beta":  {Version: libs.RecordVersion, Path: "/src/beta"},
+				"gamma": {Version: libs.RecordVersion, Path: "relative/gamma"},
+			},
+		},
+		{
+			name: "upgrade from version 1: canonical bare paths",
+			fixture: map[string]map[string]string{
+				"test-bucket":       {"alpha": "/home/user/src/alpha", "beta": "~/not-touched-again"},
+				libs.MetaBucketName: {"canonical_paths": "1"},
+			},
+			wantFrom:    1,
+			wantApplied: []int{2},
+			wantRecords: map[string]libs.Record{
+				"alpha": {Version: libs.RecordVersion, Path: "/home/user/src/alpha"},
+				"beta":  {Version: libs.RecordVersion, Path: "~/not-touched-again"},
+			},
+		},
+		{
+			name: "version 2 is already current",
+			fixture: map[string]map[string]string{
+				"test-bucket":       {"alpha": `{"version":1,"path":"/src/alpha","use_count":3}`},
+				libs.MetaBucketName: {"schema_version": "2"},
+			},
+			wantFrom:    2,
+			wantApplied: nil,
+			wantRecords: map[string]libs.Record{
+				"alpha": {Version: libs.RecordVersion, Path: "/src/alpha", UseCount: 3},
+			},
+		},
+		{
+			name: "new database is stamped without a backup",
+			fixture: map[string]map[string]string{
+				"test-bucket": {},
+			},
+			wantFrom:    0,
+			wantApplied: nil,
+		},
+		{
+			name: "version from a newer gs is refused",
+			fixture: map[string]map[string]string{
+				"test-bucket":       {"alpha": "/src/alpha"},
+				libs.MetaBucketName: {"schema_version": "99"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed migration leaves the database untouched",
+			fixture: map[string]map[string]string{
+				"test-bucket": {"alpha": "~/src/alpha", "broken": "{not json"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "bbolt.db")
+			writeFixture(t, path, tt.fixture)
+
+			db, err := libs.OpenBoltDB(path, "test-bucket")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			migrator := libs.NewMigrator(db, libs.MigrationEnv{KVBucketName: "test-bucket", NormalizePath: normalize})
+			before := readBucket(t, db, "test-bucket")
+			result, err := migrator.Migrate()
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Migrator.Migrate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if after := readBucket(t, db, "test-bucket"); !reflect.DeepEqual(after, before) {
+					t.Errorf("Migrator.Migrate() changed the database on failure: %v, want %v", after, before)
+				}
+				if backups, _ := filepath.Glob(path + ".*.bak"); len(backups) != 0 {
+					t.Errorf("Migrator.Migrate() left backups behind on failure: %v", backups)
+				}
+				return
+			}
+
+			if result.From != tt.wantFrom || result.To != libs.SchemaVersion {
+				t.Errorf("Migrator.Migrate() from %d to %d, want from %d to %d", result.From, result.To, tt.wantFrom, libs.SchemaVersion)
+			}
+			var applied []int
+			for _, migration := range result.Applied {
+				applied = append(applied, migration.Version)
+			}
+			if !reflect.DeepEqual(applied, tt.wantApplied) {
+				t.Errorf("Migrator.Migrate() applied %v, want %v", applied, tt.wantApplied)
+			}
+
+			if len(tt.wantApplied) > 0 {
+				if _, err := os.Stat(result.BackupPath); err != nil {
+					t.Errorf("Migrator.Migrate() backup %q missing: %v", result.BackupPath, err)
+				}
+			} else if result.BackupPath != "" {
+				t.Errorf("Migrator.Migrate() made a backup although nothing was pending")
+			}
+
+			version, err := migrator.Version()
+			if err != nil || version != libs.SchemaVersion {
+				t.Errorf("Migrator.Version() = %d, %v, want %d", version, err, libs.SchemaVersion)
+			}
+
+			for alias, value := range readBucket(t, db, "test-bucket") {
+				if !strings.HasPrefix(value, "{") {
+					t.Errorf("alias %s still holds a bare path %q", alias, value)
+				}
+				record, err := libs.DecodeRecord([]byte(value))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(record, tt.wantRecords[alias]) {
+					t.Errorf("alias %s = %+v, want %+v", alias, record, tt.wantRecords[alias])
+				}
+			}
+		})
+	}
+}
+
+func TestMigrator_Pending(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bbolt.db")
+	writeFixture(t, path, map[string]map[string]string{"test-bucket": {"alpha": "/src/alpha"}})
+
+	db, err := libs.OpenBoltDB(path, "test-bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	migrator := libs.NewMigrator(db, libs.MigrationEnv{KVBucketName: "test-bucket"})
+	pending, err := migrator.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 || pending[0].Version != 1 || pending[1].Version != 2 {
+		t.Errorf("Migrator.Pending() = %+v, want migrations 1 and 2", pending)
+	}
+
+	// Listing pending migrations must not change anything
+	if got := readBucket(t, db, "test-bucket"); got["alpha"] != "/src/alpha" {
+		t.Errorf("Migrator.Pending() modified the database: %v", got)
+	}
+}
+
+// writeFixture creates a database file holding exactly the given buckets, the
+// way an older version of gs would have left it.
+func writeFixture(t *testing.T, path string, buckets map[string]map[string]string) {
+	t.Helper()
+	db, err := bbolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for name, values := range buckets {
+			b, err := tx.CreateBucket([]byte(name))
+			if err != nil {
+				return err
+			}
+			for key, value := range values {
+				if err := b.Put([]byte(key), []byte(value)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readBucket(t *testing.T, db libs.DB, name string) map[string]string {
+	t.Helper()
+	values := map[string]string{}
+	err := db.View(func(tx libs.Tx) error {
+		return tx.Bucket([]byte(name)).ForEach(func(key, value []byte) error {
+			values[string(key)] = string(value)
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
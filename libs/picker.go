@@ -0,0 +1,463 @@
+package libs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	// ErrPickerCancelled is returned when the picker is left without a choice.
+	ErrPickerCancelled  = errors.New("no project selected")
+	ErrInvalidSelection = errors.New("invalid selection")
+)
+
+// PickerItem is one project offered by the picker.
+type PickerItem struct {
+	Alias    string
+	Path     string
+	Branch   string
+	LastUsed time.Time
+	UseCount int
+}
+
+// Terminal is a screen in raw mode: input arrives unbuffered and without
+// echo, and output is drawn as written.
+type Terminal interface {
+	io.Reader
+	io.Writer
+	Size() (width, height int, err error)
+}
+
+// KeyCode identifies a key the picker reacts to.
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota
+	KeyEnter
+	KeyBackspace
+	KeyUp
+	KeyDown
+	KeyClear
+	KeyCancel
+)
+
+// Key is a decoded key press. Rune is only set for KeyRune.
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// ParseKeys decodes raw terminal input. Arrow keys arrive as escape
+// sequences, Ctrl-P and Ctrl-N move like the arrows, Ctrl-U clears the query
+// and Esc or Ctrl-C cancel. Other control input is dropped.
+func ParseKeys(input []byte) []Key {
+	var keys []Key
+	for len(input) > 0 {
+		switch b := input[0]; {
+		case b == '\r' || b == '\n':
+			keys = append(keys, Key{Code: KeyEnter})
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, Key{Code: KeyBackspace})
+		case b == 0x10:
+			keys = append(keys, Key{Code: KeyUp})
+		case b == 0x0e:
+			keys = append(keys, Key{Code: KeyDown})
+		case b == 0x15:
+			keys = append(keys, Key{Code: KeyClear})
+		case b == 0x03:
+			keys = append(keys, Key{Code: KeyCancel})
+		case b == 0x1b:
+			if n := escapeSequenceLen(input); n > 0 {
+				switch input[n-1] {
+				case 'A':
+					keys = append(keys, Key{Code: KeyUp})
+				case 'B':
+					keys = append(keys, Key{Code: KeyDown})
+				}
+				input = input[n:]
+				continue
+			}
+			keys = append(keys, Key{Code: KeyCancel})
+		case b < 0x20:
+		default:
+			r, size := utf8.DecodeRune(input)
+			if r != utf8.RuneError {
+				keys = append(keys, Key{Code: KeyRune, Rune: r})
+			}
+			input = input[size:]
+			continue
+		}
+		input = input[1:]
+	}
+	return keys
+}
+
+// escapeSequenceLen returns the length of the escape sequence input starts
+// with, or 0 for a lone Esc. CSI sequences such as "\x1b[A" or "\x1b[5~" run
+// up to their final byte in 0x40-0x7e, so that no parameter byte is taken for
+// typed text; SS3 sequences such as "\x1bOB" have a single final byte.
+func escapeSequenceLen(input []byte) int {
+	if len(input) < 3 {
+		return 0
+	}
+	switch input[1] {
+	case 'O':
+		return 3
+	case '[':
+		for i := 2; i < len(input); i++ {
+			if input[i] >= 0x40 && input[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		// A truncated sequence is dropped as a whole
+		return len(input)
+	}
+	return 0
+}
+
+// Picker is a full-screen fuzzy finder over projects. Typing filters the
+// list with the same rules as alias matching, the arrows move the selection
+// and Enter picks it. The lower part of the screen previews the selected
+// project.
+type Picker struct {
+	items   []PickerItem
+	query   []rune
+	matches []int
+	cursor  int
+	offset  int
+	now     func() time.Time
+	readDir func(path string) ([]string, error)
+}
+
+type PickerOption func(*Picker)
+
+// WithPickerClock sets the clock used to show how long ago projects were used.
+func WithPickerClock(now func() time.Time) PickerOption {
+	return func(p *Picker) {
+		p.now = now
+	}
+}
+
+// WithDirReader sets how the preview lists the contents of a project.
+func WithDirReader(readDir func(path string) ([]string, error)) PickerOption {
+	return func(p *Picker) {
+		p.readDir = readDir
+	}
+}
+
+// NewPicker creates a picker over items, which are shown in the given order
+// until a query is typed.
+func NewPicker(items []PickerItem, opts ...PickerOption) *Picker {
+	p := &Picker{items: items, now: time.Now, readDir: listDir}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.filter()
+	return p
+}
+
+type pickerAction int
+
+const (
+	pickerContinue pickerAction = iota
+	pickerSelect
+	pickerCancel
+)
+
+// Run draws the picker on term and handles keys until a project is chosen.
+// It returns ErrPickerCancelled if the user cancels or the input ends.
+func (p *Picker) Run(term Terminal) (PickerItem, error) {
+	buf := make([]byte, 64)
+	for {
+		if err := p.draw(term); err != nil {
+			return PickerItem{}, err
+		}
+
+		n, err := term.Read(buf)
+		for _, key := range ParseKeys(buf[:n]) {
+			switch p.handleKey(key) {
+			case pickerSelect:
+				return p.items[p.matches[p.cursor]], nil
+			case pickerCancel:
+				return PickerItem{}, ErrPickerCancelled
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return PickerItem{}, ErrPickerCancelled
+		}
+		if err != nil {
+			return PickerItem{}, err
+		}
+	}
+}
+
+func (p *Picker) handleKey(key Key) pickerAction {
+	switch key.Code {
+	case KeyRune:
+		p.query = append(p.query, key.Rune)
+		p.filter()
+	case KeyBackspace:
+		if len(p.query) > 0 {
+			p.query = p.query[:len(p.query)-1]
+			p.filter()
+		}
+	case KeyClear:
+		p.query = nil
+		p.filter()
+	case KeyUp:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case KeyDown:
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+	case KeyEnter:
+		if len(p.matches) > 0 {
+			return pickerSelect
+		}
+	case KeyCancel:
+		return pickerCancel
+	}
+	return pickerContinue
+}
+
+// filter recomputes the matching items for the query, best match first.
+// Items that match equally well keep their original order.
+func (p *Picker) filter() {
+	p.matches = p.matches[:0]
+	p.cursor, p.offset = 0, 0
+
+	if len(p.query) == 0 {
+		for i := range p.items {
+			p.matches = append(p.matches, i)
+		}
+		return
+	}
+
+	qualities := map[int]matchQuality{}
+	for i, item := range p.items {
+		quality := matchEntry(string(p.query), Entry{Alias: item.Alias, Record: Record{Path: item.Path}})
+		if quality.kind != MatchNone {
+			qualities[i] = quality
+			p.matches = append(p.matches, i)
+		}
+	}
+	sort.SliceStable(p.matches, func(i, j int) bool {
+		return qualities[p.matches[i]].better(qualities[p.matches[j]])
+	})
+}
+
+// Escape sequences used to draw the picker.
+const (
+	cursorHome      = "\x1b[H"
+	clearLine       = "\x1b[K"
+	clearBelow      = "\x1b[J"
+	cursorPosition  = "\x1b[%d;%dH"
+	defaultWidth    = 80
+	defaultHeight   = 24
+	minPreviewLines = 12
+)
+
+func (p *Picker) draw(term Terminal) error {
+	width, height, err := term.Size()
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = defaultWidth, defaultHeight
+	}
+
+	var frame strings.Builder
+	frame.WriteString(cursorHome)
+	for i, line := range p.render(width, height) {
+		if i > 0 {
+			frame.WriteString("\r\n")
+		}
+		frame.WriteString(line)
+		frame.WriteString(clearLine)
+	}
+	frame.WriteString(clearBelow)
+	// Leave the cursor after the query, where typing goes
+	fmt.Fprintf(&frame, cursorPosition, 1, len(p.query)+3)
+
+	_, err = io.WriteString(term, frame.String())
+	return err
+}
+
+// render lays out the screen: the query, a match count, the list of matches
+// and, on screens tall enough, a preview of the selected project.
+func (p *Picker) render(width, height int) []string {
+	previewHeight := 0
+	if height >= minPreviewLines {
+		previewHeight = height / 3
+	}
+	listHeight := height - 2
+	if previewHeight > 0 {
+		listHeight -= previewHeight + 1
+	}
+	listHeight = max(listHeight, 1)
+
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+listHeight {
+		p.offset = p.cursor - listHeight + 1
+	}
+
+	lines := []string{
+		truncate("> "+string(p.query), width),
+		truncate(fmt.Sprintf("  %d/%d", len(p.matches), len(p.items)), width),
+	}
+
+	aliasWidth, branchWidth := 0, 0
+	for _, item := range p.items {
+		aliasWidth = max(aliasWidth, utf8.RuneCountInString(item.Alias))
+		branchWidth = max(branchWidth, utf8.RuneCountInString(item.Branch))
+	}
+	for row := 0; row < listHeight; row++ {
+		i := p.offset + row
+		if i >= len(p.matches) {
+			lines = append(lines, "")
+			continue
+		}
+		item := p.items[p.matches[i]]
+		marker := "  "
+		if i == p.cursor {
+			marker = "> "
+		}
+		line := fmt.Sprintf("%s%s  %s  %-9s  %s", marker,
+			pad(item.Alias, aliasWidth), pad(item.Branch, branchWidth), FormatAge(p.now(), item.LastUsed), item.Path)
+		lines = append(lines, truncate(strings.TrimRight(line, " "), width))
+	}
+
+	if previewHeight > 0 {
+		lines = append(lines, strings.Repeat("─", width))
+		preview := p.preview()
+		for row := 0; row < previewHeight; row++ {
+			line := ""
+			if row < len(preview) {
+				line = truncate(preview[row], width)
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// preview describes the selected project followed by its top-level entries.
+func (p *Picker) preview() []string {
+	if len(p.matches) == 0 {
+		return nil
+	}
+	item := p.items[p.matches[p.cursor]]
+
+	branch := item.Branch
+	if branch == "" {
+		branch = "-"
+	}
+	lines := []string{
+		item.Alias,
+		"path:      " + item.Path,
+		"branch:    " + branch,
+		fmt.Sprintf("last used: %s (%d uses)", FormatAge(p.now(), item.LastUsed), item.UseCount),
+		"",
+	}
+
+	names, err := p.readDir(item.Path)
+	if err != nil {
+		return append(lines, "cannot read directory: "+err.Error())
+	}
+	return append(lines, names...)
+}
+
+// PickNumbered lists items with numbers on out and reads the chosen number
+// from in. It stands in for the picker when there is no terminal.
+func PickNumbered(items []PickerItem, in io.Reader, out io.Writer) (PickerItem, error) {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for i, item := range items {
+		fmt.Fprintf(tw, "%d)\t%s\t%s\t%s\n", i+1, item.Alias, item.Branch, item.Path)
+	}
+	if err := tw.Flush(); err != nil {
+		return PickerItem{}, err
+	}
+	fmt.Fprintf(out, "select a project [1-%d]: ", len(items))
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return PickerItem{}, err
+	}
+	choice := strings.TrimSpace(line)
+	if choice == "" {
+		return PickerItem{}, ErrPickerCancelled
+	}
+	n, err := strconv.Atoi(choice)
+	if err != nil || n < 1 || n > len(items) {
+		return PickerItem{}, fmt.Errorf("%w %q", ErrInvalidSelection, choice)
+	}
+	return items[n-1], nil
+}
+
+// listDir returns the names in path, marking directories with a trailing
+// slash and leaving out the .git directory.
+func listDir(path string) ([]string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == ".git" {
+			continue
+		}
+		if entry.IsDir() {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// FormatAge describes how long before now t was, such as "3h ago", or
+// "never" when t is zero.
+func FormatAge(now, t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	switch age := now.Sub(t); {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
+
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// truncate cuts s to width runes, marking the cut with an ellipsis.
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,259 @@
+package libs_test
+
+import (
+	"bytes"
+	"errors"
+	"gs/libs"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTerminal replays input one chunk per Read, like a raw-mode terminal
+// delivering key presses, and records everything drawn on it.
+type fakeTerminal struct {
+	input         []string
+	width, height int
+	output        bytes.Buffer
+}
+
+func (f *fakeTerminal) Read(p []byte) (int, error) {
+	if len(f.input) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, f.input[0])
+	f.input = f.input[1:]
+	return n, nil
+}
+
+func (f *fakeTerminal) Write(p []byte) (int, error) {
+	return f.output.Write(p)
+}
+
+func (f *fakeTerminal) Size() (int, int, error) {
+	return f.width, f.height, nil
+}
+
+// lastFrame returns the lines of the last screen drawn, without escape codes.
+func (f *fakeTerminal) lastFrame() []string {
+	frames := strings.Split(f.output.String(), "\x1b[H")
+	frame := frames[len(frames)-1]
+	frame = frame[:strings.Index(frame, "\x1b[J")]
+	frame = strings.ReplaceAll(frame, "\x1b[K", "")
+	return strings.Split(frame, "\r\n")
+}
+
+var pickerNow = time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+
+var pickerItems = []libs.PickerItem{
+	{Alias: "api", Path: "/src/backend/api", Branch: "main", LastUsed: pickerNow.Add(-5 * time.Minute), UseCount: 12},
+	{Alias: "web", Path: "/src/frontend/website", Branch: "develop", LastUsed: pickerNow.Add(-3 * time.Hour), UseCount: 4},
+	{Alias: "docs", Path: "/home/me/documentation", LastUsed: pickerNow.Add(-50 * time.Hour), UseCount: 1},
+	{Alias: "gateway", Path: "/src/backend/gateway", Branch: "main"},
+}
+
+func newTestPicker(items []libs.PickerItem) *libs.Picker {
+	return libs.NewPicker(items,
+		libs.WithPickerClock(func() time.Time { return pickerNow }),
+		libs.WithDirReader(func(path string) ([]string, error) {
+			if path == "/home/me/documentation" {
+				return nil, errors.New("permission denied")
+			}
+			return []string{"cmd/", "go.mod", "main.go"}, nil
+		}),
+	)
+}
+
+func TestPicker_Run(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []string
+		want      string
+		wantErrIs error
+	}{
+		{name: "enter picks the first item", input: []string{"\r"}, want: "api"},
+		{name: "arrow down moves the selection", input: []string{"\x1b[B", "\x1b[B", "\r"}, want: "docs"},
+		{name: "ctrl-n and ctrl-p move the selection", input: []string{"\x0e", "\x0e", "\x10", "\r"}, want: "web"},
+		{name: "selection stops at the ends", input: []string{"\x1b[A", "\x1b[B\x1b[B\x1b[B\x1b[B\x1b[B", "\r"}, want: "gateway"},
+		{name: "typing filters incrementally", input: []string{"g", "w", "\r"}, want: "gateway"},
+		{name: "filter matches path components", input: []string{"webs", "\r"}, want: "web"},
+		{name: "backspace widens the filter", input: []string{"gwx", "\x7f", "\r"}, want: "gateway"},
+		{name: "ctrl-u clears the query", input: []string{"docs", "\x15", "\r"}, want: "api"},
+		{name: "enter without matches does nothing", input: []string{"zzz", "\r", "\x7f\x7f\x7f", "\r"}, want: "api"},
+		{name: "escape cancels", input: []string{"a", "\x1b"}, wantErrIs: libs.ErrPickerCancelled},
+		{name: "ctrl-c cancels", input: []string{"\x03"}, wantErrIs: libs.ErrPickerCancelled},
+		{name: "end of input cancels", input: []string{"a"}, wantErrIs: libs.ErrPickerCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := &fakeTerminal{input: tt.input, width: 80, height: 24}
+			got, err := newTestPicker(pickerItems).Run(term)
+
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("Picker.Run() error = %v, want %v", err, tt.wantErrIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Picker.Run() error = %v", err)
+			}
+			if got.Alias != tt.want {
+				t.Errorf("Picker.Run() = %v, want %v", got.Alias, tt.want)
+			}
+		})
+	}
+}
+
+func TestPicker_Render(t *testing.T) {
+	t.Run("list with columns and preview", func(t *testing.T) {
+		term := &fakeTerminal{input: []string{"\x1b[B"}, width: 60, height: 14}
+		newTestPicker(pickerItems).Run(term)
+
+		want := []string{
+			"> ",
+			"  4/4",
+			"  api      main     5m ago     /src/backend/api",
+			"> web      develop  3h ago     /src/frontend/website",
+			"  docs              2d ago     /home/me/documentation",
+			"  gateway  main     never      /src/backend/gateway",
+			"",
+			"",
+			"",
+			"────────────────────────────────────────────────────────────",
+			"web",
+			"path:      /src/frontend/website",
+			"branch:    develop",
+			"last used: 3h ago (4 uses)",
+		}
+		if got := term.lastFrame(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Picker frame =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	})
+
+	t.Run("preview reports unreadable directories", func(t *testing.T) {
+		term := &fakeTerminal{input: []string{"docs"}, width: 60, height: 30}
+		newTestPicker(pickerItems).Run(term)
+
+		frame := strings.Join(term.lastFrame(), "\n")
+		if !strings.Contains(frame, "cannot read directory: permission denied") {
+			t.Errorf("Picker preview does not report the error:\n%s", frame)
+		}
+	})
+
+	t.Run("small screens drop the preview and scroll the list", func(t *testing.T) {
+		term := &fakeTerminal{input: []string{"\x0e\x0e\x0e"}, width: 30, height: 4}
+		newTestPicker(pickerItems).Run(term)
+
+		want := []string{
+			"> ",
+			"  4/4",
+			"  docs              2d ago   …",
+			"> gateway  main     never    …",
+		}
+		if got := term.lastFrame(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Picker frame =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	})
+}
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []libs.Key
+	}{
+		{
+			name:  "text, editing and movement keys",
+			input: "aé\r\x7f\x1b[A\x1bOB\x0e\x10\x15\x03\x1b\x01",
+			want: []libs.Key{
+				{Code: libs.KeyRune, Rune: 'a'},
+				{Code: libs.KeyRune, Rune: 'é'},
+				{Code: libs.KeyEnter},
+				{Code: libs.KeyBackspace},
+				{Code: libs.KeyUp},
+				{Code: libs.KeyDown},
+				{Code: libs.KeyDown},
+				{Code: libs.KeyUp},
+				{Code: libs.KeyClear},
+				{Code: libs.KeyCancel},
+				{Code: libs.KeyCancel},
+			},
+		},
+		{
+			name:  "delete and page keys are dropped whole",
+			input: "a\x1b[3~b\x1b[5~\x1b[6~c",
+			want:  []libs.Key{{Code: libs.KeyRune, Rune: 'a'}, {Code: libs.KeyRune, Rune: 'b'}, {Code: libs.KeyRune, Rune: 'c'}},
+		},
+		{
+			name:  "arrows with modifiers",
+			input: "\x1b[1;5A\x1b[1;2B",
+			want:  []libs.Key{{Code: libs.KeyUp}, {Code: libs.KeyDown}},
+		},
+		{
+			name:  "truncated sequence",
+			input: "a\x1b[12",
+			want:  []libs.Key{{Code: libs.KeyRune, Rune: 'a'}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := libs.ParseKeys([]byte(tt.input)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPickNumbered(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      string
+		wantErr   string
+		wantErrIs error
+	}{
+		{name: "number picks the item", input: "2\n", want: "web"},
+		{name: "input without newline", input: " 4 ", want: "gateway"},
+		{name: "empty answer cancels", input: "\n", wantErrIs: libs.ErrPickerCancelled},
+		{name: "no input cancels", input: "", wantErrIs: libs.ErrPickerCancelled},
+		{name: "out of range", input: "5\n", wantErr: `invalid selection "5"`, wantErrIs: libs.ErrInvalidSelection},
+		{name: "not a number", input: "api\n", wantErr: `invalid selection "api"`, wantErrIs: libs.ErrInvalidSelection},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			got, err := libs.PickNumbered(pickerItems, strings.NewReader(tt.input), &out)
+
+			wantPrompt := "1)  api      main     /src/backend/api\n" +
+				"2)  web      develop  /src/frontend/website\n" +
+				"3)  docs              /home/me/documentation\n" +
+				"4)  gateway  main     /src/backend/gateway\n" +
+				"select a project [1-4]: "
+			if out.String() != wantPrompt {
+				t.Errorf("PickNumbered() printed\n%q\nwant\n%q", out.String(), wantPrompt)
+			}
+
+			if tt.wantErrIs != nil || tt.wantErr != "" {
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Errorf("PickNumbered() error = %v, want %v", err, tt.wantErrIs)
+				}
+				if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+					t.Errorf("PickNumbered() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			switch {
+			case err != nil:
+				t.Errorf("PickNumbered() error = %v", err)
+			case got.Alias != tt.want:
+				t.Errorf("PickNumbered() = %v, want %v", got.Alias, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package libs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrProfileNotFound = errors.New("profile not found")
+	ErrProfileExists   = errors.New("profile already exists")
+	ErrInvalidProfile  = errors.New("invalid profile name")
+	ErrDefaultProfile  = errors.New("the default profile cannot be deleted or renamed")
+)
+
+// DefaultProfile is the profile whose aliases live in the kv_bucket_name
+// bucket itself, which is where gs kept every alias before profiles.
+const DefaultProfile = "default"
+
+// profileBucketSeparator joins kv_bucket_name and a profile name into the
+// bucket of that profile.
+const profileBucketSeparator = "/"
+
+var profilePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateProfile checks that name can be used as a profile: it must start
+// with a letter or digit and contain only letters, digits, '.', '_' and '-'.
+func ValidateProfile(name string) error {
+	if !profilePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidProfile, name)
+	}
+	return nil
+}
+
+// ProfileBucketName returns the bucket holding the aliases of profile.
+func ProfileBucketName(kvBucketName, profile string) string {
+	if profile == DefaultProfile {
+		return kvBucketName
+	}
+	return kvBucketName + profileBucketSeparator + profile
+}
+
+// ProfileInfo describes a stored profile.
+type ProfileInfo struct {
+	Name    string
+	Aliases int
+}
+
+// ProfileRule makes gs use Profile for commands run in Dir or below it.
+type ProfileRule struct {
+	Dir     string
+	Profile string
+}
+
+func (r ProfileRule) String() string {
+	return r.Dir + "=" + r.Profile
+}
+
+// ParseProfileRule parses a profile_rules entry of the form <dir>=<profile>.
+// The directory may start with ~ and must otherwise be absolute.
+func ParseProfileRule(rule string) (ProfileRule, error) {
+	i := strings.LastIndex(rule, "=")
+	if i < 0 {
+		return ProfileRule{}, fmt.Errorf("profile_rules entry %q must have the form <dir>=<profile>", rule)
+	}
+	dir, profile := strings.TrimSpace(rule[:i]), strings.TrimSpace(rule[i+1:])
+	if err := ValidateProfile(profile); err != nil {
+		return ProfileRule{}, fmt.Errorf("profile_rules entry %q: %w", rule, err)
+	}
+	expanded, err := expandPath(dir)
+	if err != nil {
+		return ProfileRule{}, fmt.Errorf("profile_rules entry %q: %w", rule, err)
+	}
+	if !filepath.IsAbs(expanded) {
+		return ProfileRule{}, fmt.Errorf("profile_rules entry %q: directory must be absolute or start with ~", rule)
+	}
+	return ProfileRule{Dir: filepath.Clean(expanded), Profile: profile}, nil
+}
+
+// ParseProfileRules parses every profile_rules entry and rejects two rules
+// for the same directory.
+func ParseProfileRules(rules []string) ([]ProfileRule, error) {
+	parsed := make([]ProfileRule, 0, len(rules))
+	seen := map[string]bool{}
+	var errs []error
+	for _, rule := range rules {
+		r, err := ParseProfileRule(rule)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if seen[r.Dir] {
+			errs = append(errs, fmt.Errorf("profile_rules entry %q repeats the directory %s", rule, r.Dir))
+			continue
+		}
+		seen[r.Dir] = true
+		parsed = append(parsed, r)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// MatchProfileRule returns the rule for the deepest directory containing
+// dir. Symlinks are resolved when the paths as given do not match.
+func MatchProfileRule(rules []ProfileRule, dir string) (ProfileRule, bool) {
+	var best ProfileRule
+	found := false
+	for _, rule := range rules {
+		if !withinDir(rule.Dir, dir) && !withinDir(resolveSymlinks(rule.Dir), resolveSymlinks(dir)) {
+			continue
+		}
+		if !found || len(rule.Dir) > len(best.Dir) {
+			best, found = rule, true
+		}
+	}
+	return best, found
+}
+
+// withinDir reports whether path is dir or below it.
+func withinDir(dir, path string) bool {
+	path = filepath.Clean(path)
+	if path == dir {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
+
+func resolveSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
+
+// ProfileSelection records which profile gs works on and why.
+type ProfileSelection struct {
+	Profile string
+	// Override is the flag or environment variable that named the profile,
+	// which takes precedence over the rules.
+	Override string
+	// Rule is the profile_rules entry that matched Dir, when one did.
+	Rule *ProfileRule
+	// Dir is the directory the rules were matched against.
+	Dir string
+}
+
+// SelectProfile chooses the profile for a command run in dir. A profile named
+// by override, such as --profile or $GS_PROFILE, wins; otherwise the
+// profile_rules entry for the deepest directory containing dir does, and
+// default_profile applies when no rule matches.
+func (c Config) SelectProfile(dir, override string) (ProfileSelection, error) {
+	selection := ProfileSelection{Profile: c.DefaultProfile, Override: override, Dir: dir}
+	if override != "" {
+		return selection, nil
+	}
+	rules, err := ParseProfileRules(c.ProfileRules)
+	if err != nil {
+		return ProfileSelection{}, err
+	}
+	if rule, ok := MatchProfileRule(rules, dir); ok {
+		selection.Profile, selection.Rule = rule.Profile, &rule
+	}
+	return selection, nil
+}
+
+// Profile returns the profile the service works on.
+func (s *DBService) Profile() string {
+	return s.selection.Profile
+}
+
+// ProfileSelection returns how the profile the service works on was chosen.
+func (s *DBService) ProfileSelection() ProfileSelection {
+	return s.selection
+}
+
+// CheckProfile returns ErrProfileNotFound when the selected profile has not
+// been created.
+func (s *DBService) CheckProfile() error {
+	return s.db.View(func(tx Tx) error {
+		if tx.Bucket([]byte(s.kvBucketName)) == nil {
+			return fmt.Errorf("%w: %s", ErrProfileNotFound, s.selection.Profile)
+		}
+		return nil
+	})
+}
+
+// Profiles returns the stored profiles sorted by name, with the number of
+// aliases in each.
+func (s *DBService) Profiles() ([]ProfileInfo, error) {
+	var profiles []ProfileInfo
+	err := s.db.View(func(tx Tx) error {
+		return tx.ForEachBucket(func(name []byte) error {
+			profile, ok := s.profileOf(string(name))
+			if !ok {
+				return nil
+			}
+			info := ProfileInfo{Name: profile}
+			if err := tx.Bucket(name).ForEach(func(_, _ []byte) error {
+				info.Aliases++
+				return nil
+			}); err != nil {
+				return err
+			}
+			profiles = append(profiles, info)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles, nil
+}
+
+// profileOf returns the profile that bucket belongs to, if any.
+func (s *DBService) profileOf(bucket string) (string, bool) {
+	if bucket == s.baseBucketName {
+		return DefaultProfile, true
+	}
+	profile, ok := strings.CutPrefix(bucket, s.baseBucketName+profileBucketSeparator)
+	if !ok || profile == DefaultProfile || ValidateProfile(profile) != nil {
+		return "", false
+	}
+	return profile, true
+}
+
+// CreateProfile adds an empty profile.
+func (s *DBService) CreateProfile(name string) error {
+	if err := ValidateProfile(name); err != nil {
+		return err
+	}
+	return s.db.Update(func(tx Tx) error {
+		_, err := s.createProfileBucket(tx, name)
+		return err
+	})
+}
+
+// DeleteProfile removes a profile with its aliases and visit history.
+func (s *DBService) DeleteProfile(name string) error {
+	if name == DefaultProfile {
+		return ErrDefaultProfile
+	}
+	return s.db.Update(func(tx Tx) error {
+		if _, err := s.profileBucket(tx, name); err != nil {
+			return err
+		}
+		if err := tx.DeleteBucket([]byte(ProfileBucketName(s.baseBucketName, name))); err != nil {
+			return err
+		}
+		return moveHistory(tx, ProfileBucketName(s.baseBucketName, name), "")
+	})
+}
+
+// CopyProfile creates the profile to with a copy of the aliases of from.
+// The visit history is not copied.
+func (s *DBService) CopyProfile(from, to string) error {
+	if err := ValidateProfile(to); err != nil {
+		return err
+	}
+	return s.db.Update(func(tx Tx) error {
+		return s.copyProfile(tx, from, to)
+	})
+}
+
+// RenameProfile moves the aliases and visit history of oldName to newName
+// in one transaction.
+func (s *DBService) RenameProfile(oldName, newName string) error {
+	if oldName == DefaultProfile || newName == DefaultProfile {
+		return ErrDefaultProfile
+	}
+	if err := ValidateProfile(newName); err != nil {
+		return err
+	}
+	return s.db.Update(func(tx Tx) error {
+		if err := s.copyProfile(tx, oldName, newName); err != nil {
+			return err
+		}
+		oldBucket := ProfileBucketName(s.baseBucketName, oldName)
+		if err := tx.DeleteBucket([]byte(oldBucket)); err != nil {
+			return err
+		}
+		return moveHistory(tx, oldBucket, ProfileBucketName(s.baseBucketName, newName))
+	})
+}
+
+func (s *DBService) copyProfile(tx Tx, from, to string) error {
+	src, err := s.profileBucket(tx, from)
+	if err != nil {
+		return err
+	}
+	dst, err := s.createProfileBucket(tx, to)
+	if err != nil {
+		return err
+	}
+
+	// Collect first: values are only valid until the next write
+	values := map[string][]byte{}
+	if err := src.ForEach(func(key, value []byte) error {
+		values[string(key)] = append([]byte(nil), value...)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for key, value := range values {
+		if err := dst.Put([]byte(key), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *DBService) profileBucket(tx Tx, name string) (Bucket, error) {
+	b := tx.Bucket([]byte(ProfileBucketName(s.baseBucketName, name)))
+	if b == nil {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	return b, nil
+}
+
+func (s *DBService) createProfileBucket(tx Tx, name string) (Bucket, error) {
+	bucket := []byte(ProfileBucketName(s.baseBucketName, name))
+	if tx.Bucket(bucket) != nil {
+		return nil, fmt.Errorf("%w: %s", ErrProfileExists, name)
+	}
+	return tx.CreateBucket(bucket)
+}
+
+// moveHistory moves the visit history kept for the alias bucket from to the
+// bucket to, or deletes it when to is empty.
+func moveHistory(tx Tx, from, to string) error {
+	meta := tx.Bucket([]byte(MetaBucketName))
+	if meta == nil {
+		return fmt.Errorf("bucket %s not found", MetaBucketName)
+	}
+
+	prefix := historyKey(from, "")
+	history := map[string][]byte{}
+	if err := meta.ForEach(func(key, value []byte) error {
+		if bytes.HasPrefix(key, prefix) {
+			history[string(key[len(prefix):])] = append([]byte(nil), value...)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for name, value := range history {
+		if err := meta.Delete(historyKey(from, name)); err != nil {
+			return err
+		}
+		if to == "" {
+			continue
+		}
+		if err := meta.Put(historyKey(to, name), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,319 @@
+package libs_test
+
+import (
+	"errors"
+	"gs/libs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// openProfileDB opens a fresh database with the default profile holding
+// alpha and a visit history.
+func openProfileDB(t *testing.T) libs.DB {
+	t.Helper()
+	db, err := libs.OpenBoltDB(filepath.Join(t.TempDir(), "bbolt.db"), "gs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	service := libs.NewDBService(db, "gs", libs.WithClock(testClock))
+	for _, alias := range []string{"alpha", "beta"} {
+		if _, err := service.Add(alias, "/src/"+alias, libs.AddOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.Visit(alias); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func profileNames(t *testing.T, service *libs.DBService) map[string]int {
+	t.Helper()
+	profiles, err := service.Profiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]int{}
+	for _, profile := range profiles {
+		names[profile.Name] = profile.Aliases
+	}
+	return names
+}
+
+func TestValidateProfile(t *testing.T) {
+	for _, name := range []string{"work", "client-a", "v1.2_x", "2025"} {
+		if err := libs.ValidateProfile(name); err != nil {
+			t.Errorf("ValidateProfile(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", "-work", ".work", "work/a", "my work"} {
+		if err := libs.ValidateProfile(name); !errors.Is(err, libs.ErrInvalidProfile) {
+			t.Errorf("ValidateProfile(%q) = %v, want ErrInvalidProfile", name, err)
+		}
+	}
+}
+
+func TestProfileBucketName(t *testing.T) {
+	if got := libs.ProfileBucketName("gs", libs.DefaultProfile); got != "gs" {
+		t.Errorf("ProfileBucketName(default) = %q, want %q", got, "gs")
+	}
+	if got := libs.ProfileBucketName("gs", "work"); got != "gs/work" {
+		t.Errorf("ProfileBucketName(work) = %q, want %q", got, "gs/work")
+	}
+}
+
+func TestDBService_Profiles(t *testing.T) {
+	db := openProfileDB(t)
+	service := libs.NewDBService(db, "gs")
+
+	if err := service.CheckProfile(); err != nil {
+		t.Errorf("CheckProfile() on the default profile = %v, want nil", err)
+	}
+	if err := service.CreateProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.CreateProfile("work"); !errors.Is(err, libs.ErrProfileExists) {
+		t.Errorf("CreateProfile() of existing profile = %v, want ErrProfileExists", err)
+	}
+	if err := service.CreateProfile("a/b"); !errors.Is(err, libs.ErrInvalidProfile) {
+		t.Errorf("CreateProfile() of invalid name = %v, want ErrInvalidProfile", err)
+	}
+
+	// Buckets that do not belong to a profile are not listed
+	err := db.Update(func(tx libs.Tx) error {
+		_, err := tx.CreateBucket([]byte("other"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := service.Profiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []libs.ProfileInfo{{Name: "default", Aliases: 2}, {Name: "work", Aliases: 0}}
+	if !reflect.DeepEqual(profiles, want) {
+		t.Errorf("Profiles() = %+v, want %+v", profiles, want)
+	}
+
+	work := libs.NewDBService(db, "gs", libs.WithProfile("work"))
+	if work.Profile() != "work" {
+		t.Errorf("Profile() = %q, want %q", work.Profile(), "work")
+	}
+	if err := work.CheckProfile(); err != nil {
+		t.Errorf("CheckProfile() on created profile = %v, want nil", err)
+	}
+	if _, err := work.Add("gamma", "/src/gamma", libs.AddOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := work.Get("alpha"); err != nil || path != "" {
+		t.Errorf("Get() of alias in another profile = %q, %v, want no path", path, err)
+	}
+
+	missing := libs.NewDBService(db, "gs", libs.WithProfile("home"))
+	if err := missing.CheckProfile(); !errors.Is(err, libs.ErrProfileNotFound) {
+		t.Errorf("CheckProfile() on missing profile = %v, want ErrProfileNotFound", err)
+	}
+}
+
+func TestDBService_CopyProfile(t *testing.T) {
+	db := openProfileDB(t)
+	service := libs.NewDBService(db, "gs")
+
+	if err := service.CopyProfile(libs.DefaultProfile, "work"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readBucket(t, db, "gs/work"); !reflect.DeepEqual(got, readBucket(t, db, "gs")) {
+		t.Errorf("CopyProfile() copied %v, want the aliases of the default profile", got)
+	}
+
+	work := libs.NewDBService(db, "gs", libs.WithProfile("work"))
+	if previous, err := work.PreviousAlias(); err != nil || previous != "" {
+		t.Errorf("PreviousAlias() after copy = %q, %v, want no history", previous, err)
+	}
+
+	if err := service.CopyProfile(libs.DefaultProfile, "work"); !errors.Is(err, libs.ErrProfileExists) {
+		t.Errorf("CopyProfile() onto existing profile = %v, want ErrProfileExists", err)
+	}
+	if err := service.CopyProfile("home", "client"); !errors.Is(err, libs.ErrProfileNotFound) {
+		t.Errorf("CopyProfile() of missing profile = %v, want ErrProfileNotFound", err)
+	}
+}
+
+func TestDBService_RenameProfile(t *testing.T) {
+	db := openProfileDB(t)
+	service := libs.NewDBService(db, "gs")
+	if err := service.CopyProfile(libs.DefaultProfile, "work"); err != nil {
+		t.Fatal(err)
+	}
+	work := libs.NewDBService(db, "gs", libs.WithProfile("work"))
+	for _, alias := range []string{"beta", "alpha"} {
+		if _, err := work.Visit(alias); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := service.RenameProfile("work", "job"); err != nil {
+		t.Fatal(err)
+	}
+	if got := profileNames(t, service); !reflect.DeepEqual(got, map[string]int{"default": 2, "job": 2}) {
+		t.Errorf("Profiles() after rename = %v", got)
+	}
+	job := libs.NewDBService(db, "gs", libs.WithProfile("job"))
+	if previous, err := job.PreviousAlias(); err != nil || previous != "beta" {
+		t.Errorf("PreviousAlias() after rename = %q, %v, want %q", previous, err, "beta")
+	}
+	if previous, err := service.PreviousAlias(); err != nil || previous != "alpha" {
+		t.Errorf("PreviousAlias() of default profile = %q, %v, want %q", previous, err, "alpha")
+	}
+
+	tests := []struct {
+		oldName, newName string
+		want             error
+	}{
+		{libs.DefaultProfile, "home", libs.ErrDefaultProfile},
+		{"job", libs.DefaultProfile, libs.ErrDefaultProfile},
+		{"job", "a/b", libs.ErrInvalidProfile},
+		{"work", "home", libs.ErrProfileNotFound},
+	}
+	for _, tt := range tests {
+		if err := service.RenameProfile(tt.oldName, tt.newName); !errors.Is(err, tt.want) {
+			t.Errorf("RenameProfile(%q, %q) = %v, want %v", tt.oldName, tt.newName, err, tt.want)
+		}
+	}
+}
+
+func TestDBService_DeleteProfile(t *testing.T) {
+	db := openProfileDB(t)
+	service := libs.NewDBService(db, "gs")
+	if err := service.CopyProfile(libs.DefaultProfile, "work"); err != nil {
+		t.Fatal(err)
+	}
+	work := libs.NewDBService(db, "gs", libs.WithProfile("work"))
+	for _, alias := range []string{"beta", "alpha"} {
+		if _, err := work.Visit(alias); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := service.DeleteProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	if got := profileNames(t, service); !reflect.DeepEqual(got, map[string]int{"default": 2}) {
+		t.Errorf("Profiles() after delete = %v", got)
+	}
+	for key := range readBucket(t, db, libs.MetaBucketName) {
+		if key == "history/gs/work/current" || key == "history/gs/work/previous" {
+			t.Errorf("DeleteProfile() kept history key %s", key)
+		}
+	}
+	if previous, err := service.PreviousAlias(); err != nil || previous != "alpha" {
+		t.Errorf("PreviousAlias() of default profile = %q, %v, want %q", previous, err, "alpha")
+	}
+
+	if err := service.DeleteProfile(libs.DefaultProfile); !errors.Is(err, libs.ErrDefaultProfile) {
+		t.Errorf("DeleteProfile(default) = %v, want ErrDefaultProfile", err)
+	}
+	if err := service.DeleteProfile("work"); !errors.Is(err, libs.ErrProfileNotFound) {
+		t.Errorf("DeleteProfile() of missing profile = %v, want ErrProfileNotFound", err)
+	}
+}
+
+func TestParseProfileRule(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		rule    string
+		want    libs.ProfileRule
+		wantErr string
+	}{
+		{rule: "/src/work=work", want: libs.ProfileRule{Dir: "/src/work", Profile: "work"}},
+		{rule: "~/oss/ = oss", want: libs.ProfileRule{Dir: filepath.Join(home, "oss"), Profile: "oss"}},
+		{rule: "/src/a=b=c", want: libs.ProfileRule{Dir: "/src/a=b", Profile: "c"}},
+		{rule: "/src/a=b/c", wantErr: `profile_rules entry "/src/a=b/c": invalid profile name: "b/c"`},
+		{rule: "=work", wantErr: `profile_rules entry "=work": directory must be absolute or start with ~`},
+		{rule: "/src/work=", wantErr: `profile_rules entry "/src/work=": invalid profile name: ""`},
+	}
+
+	for _, tt := range tests {
+		got, err := libs.ParseProfileRule(tt.rule)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("ParseProfileRule(%q) error = %v, want %s", tt.rule, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseProfileRule(%q) = %+v, %v, want %+v", tt.rule, got, err, tt.want)
+		}
+	}
+}
+
+func TestConfig_SelectProfile(t *testing.T) {
+	config := libs.Config{
+		DefaultProfile: "home",
+		ProfileRules:   []string{"/src/work=work", "/src/work/oss=oss", "/=root"},
+	}
+
+	tests := []struct {
+		name     string
+		config   libs.Config
+		dir      string
+		override string
+		want     string
+		wantRule string
+	}{
+		{name: "rule directory itself", config: config, dir: "/src/work", want: "work", wantRule: "/src/work=work"},
+		{name: "below rule directory", config: config, dir: "/src/work/api/cmd", want: "work", wantRule: "/src/work=work"},
+		{name: "deepest rule wins", config: config, dir: "/src/work/oss/gs", want: "oss", wantRule: "/src/work/oss=oss"},
+		{name: "only whole directory names match", config: config, dir: "/src/workshop", want: "root", wantRule: "/=root"},
+		{name: "override wins over rules", config: config, dir: "/src/work", override: "--profile", want: "home"},
+		{name: "default_profile without a match", config: libs.Config{DefaultProfile: "home", ProfileRules: []string{"/src/work=work"}}, dir: "/tmp", want: "home"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.SelectProfile(tt.dir, tt.override)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Profile != tt.want || got.Override != tt.override || got.Dir != tt.dir {
+				t.Errorf("SelectProfile() = %+v, want profile %q", got, tt.want)
+			}
+			rule := ""
+			if got.Rule != nil {
+				rule = got.Rule.String()
+			}
+			if rule != tt.wantRule {
+				t.Errorf("SelectProfile() matched rule %q, want %q", rule, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestMatchProfileRule_Symlinks(t *testing.T) {
+	root := t.TempDir()
+	work := filepath.Join(root, "work")
+	if err := os.MkdirAll(filepath.Join(work, "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(root, "w")
+	if err := os.Symlink(work, link); err != nil {
+		t.Skip(err)
+	}
+
+	rules := []libs.ProfileRule{{Dir: link, Profile: "work"}}
+	if rule, ok := libs.MatchProfileRule(rules, filepath.Join(work, "api")); !ok || rule.Profile != "work" {
+		t.Errorf("MatchProfileRule() through a symlinked rule = %+v, %v, want work", rule, ok)
+	}
+	rules = []libs.ProfileRule{{Dir: work, Profile: "work"}}
+	if rule, ok := libs.MatchProfileRule(rules, filepath.Join(link, "api")); !ok || rule.Profile != "work" {
+		t.Errorf("MatchProfileRule() from a symlinked directory = %+v, %v, want work", rule, ok)
+	}
+}
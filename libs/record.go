@@ -0,0 +1,70 @@
+package libs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RecordVersion is the version of the Record encoding written by this build.
+const RecordVersion = 1
+
+// Record is the value stored for every alias.
+type Record struct {
+	Version   int       `json:"version"`
+	Path      string    `json:"path"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+	LastUsed  time.Time `json:"last_used,omitzero"`
+	UseCount  int       `json:"use_count,omitempty"`
+	// Rank is the decaying usage weight behind the frecency score.
+	Rank float64  `json:"rank,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+	Note string   `json:"note,omitempty"`
+	// Remote and RootCommit identify the repository so that it can be found
+	// again after it moves; see RepoIdentity.
+	Remote     string `json:"remote,omitempty"`
+	RootCommit string `json:"root_commit,omitempty"`
+	// NonGit marks a path added with --allow-non-git that is not inside a Git
+	// repository, so that 'gs doctor' does not report it as a problem.
+	NonGit bool `json:"non_git,omitempty"`
+}
+
+// HasTag reports whether the record carries tag.
+func (r Record) HasTag(tag string) bool {
+	for _, t := range r.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// EncodeRecord serialises r as JSON, stamping the current RecordVersion.
+func EncodeRecord(r Record) ([]byte, error) {
+	r.Version = RecordVersion
+	return json.Marshal(r)
+}
+
+// DecodeRecord parses a stored value. Values written before records existed
+// are bare paths; they are upgraded to a Record with only Path set.
+func DecodeRecord(data []byte) (Record, error) {
+	if !isEncodedRecord(data) {
+		return Record{Version: RecordVersion, Path: string(data)}, nil
+	}
+
+	var r Record
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Record{}, fmt.Errorf("failed to decode record: %w", err)
+	}
+	if r.Version > RecordVersion {
+		return Record{}, fmt.Errorf("record version %d is newer than supported version %d", r.Version, RecordVersion)
+	}
+	r.Version = RecordVersion
+	return r, nil
+}
+
+func isEncodedRecord(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("{"))
+}
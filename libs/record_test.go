@@ -0,0 +1,87 @@
+package libs_test
+
+import (
+	"gs/libs"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeRecord(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		data    string
+		want    libs.Record
+		wantErr bool
+	}{
+		{
+			name: "legacy plain path is upgraded",
+			data: "/src/project",
+			want: libs.Record{Version: libs.RecordVersion, Path: "/src/project"},
+		},
+		{
+			name: "current record",
+			data: `{"version":1,"path":"/src/project","created_at":"2025-01-02T03:04:05Z","use_count":7,"tags":["api"],"note":"main service"}`,
+			want: libs.Record{
+				Version:   libs.RecordVersion,
+				Path:      "/src/project",
+				CreatedAt: created,
+				UseCount:  7,
+				Tags:      []string{"api"},
+				Note:      "main service",
+			},
+		},
+		{
+			name:    "record from a newer version",
+			data:    `{"version":99,"path":"/src/project"}`,
+			wantErr: true,
+		},
+		{
+			name:    "corrupt record",
+			data:    `{"version":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := libs.DecodeRecord([]byte(tt.data))
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeRecord() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeRecord() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeRecord(t *testing.T) {
+	record := libs.Record{
+		Path:      "/src/project",
+		CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Tags:      []string{"api"},
+	}
+
+	data, err := libs.EncodeRecord(record)
+	if err != nil {
+		t.Fatalf("EncodeRecord() error = %v", err)
+	}
+
+	want := `{"version":1,"path":"/src/project","created_at":"2025-01-02T03:04:05Z","tags":["api"]}`
+	if string(data) != want {
+		t.Errorf("EncodeRecord() = %s, want %s", data, want)
+	}
+
+	decoded, err := libs.DecodeRecord(data)
+	if err != nil {
+		t.Fatalf("DecodeRecord() error = %v", err)
+	}
+	record.Version = libs.RecordVersion
+	if !reflect.DeepEqual(decoded, record) {
+		t.Errorf("DecodeRecord(EncodeRecord()) = %+v, want %+v", decoded, record)
+	}
+}
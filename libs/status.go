@@ -0,0 +1,209 @@
+package libs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RepoState tells whether the status of a repository could be read.
+type RepoState string
+
+const (
+	RepoOK      RepoState = "ok"
+	RepoMissing RepoState = "missing"
+	RepoBroken  RepoState = "broken"
+	RepoTimeout RepoState = "timeout"
+)
+
+// StatusOptions controls how StatusService.Status reads repositories.
+type StatusOptions struct {
+	// Parallel is how many repositories are read at once; values below 1
+	// mean 1.
+	Parallel int
+	// Timeout bounds the time spent on each repository; 0 means no limit.
+	Timeout time.Duration
+}
+
+// RepoStatus is a snapshot of one registered repository. Only Alias, Path,
+// State and Err are set unless State is RepoOK.
+type RepoStatus struct {
+	Alias string
+	Path  string
+	State RepoState
+	// Branch is the checked out branch, or the short commit hash when HEAD is
+	// detached.
+	Branch string
+	// Upstream is empty when the branch does not track another one.
+	Upstream   string
+	Ahead      int
+	Behind     int
+	Dirty      int
+	Untracked  int
+	Stashes    int
+	LastCommit time.Time
+	Err        error
+}
+
+type StatusService struct {
+}
+
+func NewStatusService() *StatusService {
+	return &StatusService{}
+}
+
+// Status reads the status of every project and returns one result per
+// project, in the order given. A project that cannot be read is reported
+// through its State and Err and does not affect the others.
+func (s *StatusService) Status(ctx context.Context, projects []Entry, opts StatusOptions) []RepoStatus {
+	results := make([]RepoStatus, len(projects))
+	slots := make(chan struct{}, max(opts.Parallel, 1))
+	var wg sync.WaitGroup
+	for i, project := range projects {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(i int, project Entry) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			repoCtx, cancel := ctx, context.CancelFunc(func() {})
+			if opts.Timeout > 0 {
+				repoCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+			}
+			defer cancel()
+			results[i] = repoStatus(repoCtx, project)
+		}(i, project)
+	}
+	wg.Wait()
+	return results
+}
+
+func repoStatus(ctx context.Context, project Entry) RepoStatus {
+	status := RepoStatus{Alias: project.Alias, Path: project.Path}
+	if !isDir(project.Path) {
+		status.State = RepoMissing
+		status.Err = fmt.Errorf("%s does not exist", project.Path)
+		return status
+	}
+
+	out, err := git(ctx, project.Path, "status", "--porcelain=v2", "--branch", "--show-stash")
+	hasCommits := false
+	if err == nil {
+		hasCommits, err = parsePorcelainStatus(out, &status)
+	}
+	if err == nil && hasCommits {
+		out, err = git(ctx, project.Path, "log", "-1", "--format=%ct")
+		if err == nil {
+			err = parseCommitTime(out, &status)
+		}
+	}
+
+	switch {
+	case err == nil:
+		status.State = RepoOK
+	case ctx.Err() != nil:
+		status = RepoStatus{Alias: project.Alias, Path: project.Path, State: RepoTimeout, Err: ctx.Err()}
+	default:
+		status = RepoStatus{Alias: project.Alias, Path: project.Path, State: RepoBroken, Err: err}
+	}
+	return status
+}
+
+// git runs a read-only git command in dir and returns its standard output.
+func git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// Reading the status must not take the index lock from a concurrent git
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], firstLine(msg))
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// parsePorcelainStatus fills status from the output of
+// 'git status --porcelain=v2 --branch --show-stash' and reports whether the
+// branch has any commits yet.
+func parsePorcelainStatus(out []byte, status *RepoStatus) (bool, error) {
+	var oid, head string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		header, value, _ := strings.Cut(line, " ")
+		switch header {
+		case "#":
+			key, value, _ := strings.Cut(value, " ")
+			switch key {
+			case "branch.oid":
+				oid = value
+			case "branch.head":
+				head = value
+			case "branch.upstream":
+				status.Upstream = value
+			case "branch.ab":
+				if _, err := fmt.Sscanf(value, "+%d -%d", &status.Ahead, &status.Behind); err != nil {
+					return false, fmt.Errorf("unexpected git status line %q", line)
+				}
+			case "stash":
+				n, err := strconv.Atoi(value)
+				if err != nil {
+					return false, fmt.Errorf("unexpected git status line %q", line)
+				}
+				status.Stashes = n
+			}
+		case "1", "2", "u":
+			status.Dirty++
+		case "?":
+			status.Untracked++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+	if oid == "" {
+		return false, errors.New("git status did not report a branch")
+	}
+
+	status.Branch = head
+	if head == "(detached)" {
+		status.Branch = shortHash(oid)
+	}
+	return oid != "(initial)", nil
+}
+
+func parseCommitTime(out []byte, status *RepoStatus) error {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unexpected commit time %q", bytes.TrimSpace(out))
+	}
+	status.LastCommit = time.Unix(seconds, 0)
+	return nil
+}
+
+// shortHash abbreviates a commit hash the way git does by default.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
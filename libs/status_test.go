@@ -0,0 +1,137 @@
+package libs_test
+
+import (
+	"context"
+	"gs/libs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gitRun runs git in dir with a fixed identity and fails the test on error.
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=gs", "-c", "user.email=gs@example.com"}, args...)...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestStatusService_Status(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+
+	root := t.TempDir()
+	upstream := filepath.Join(root, "upstream")
+	clone := filepath.Join(root, "clone")
+	unborn := filepath.Join(root, "unborn")
+	plain := filepath.Join(root, "plain")
+
+	gitRun(t, root, "init", "-q", "-b", "main", upstream)
+	gitRun(t, upstream, "commit", "-q", "--allow-empty", "-m", "first")
+	gitRun(t, root, "clone", "-q", upstream, clone)
+	gitRun(t, upstream, "commit", "-q", "--allow-empty", "-m", "second")
+	gitRun(t, clone, "fetch", "-q")
+	gitRun(t, clone, "commit", "-q", "--allow-empty", "-m", "local")
+	mustWriteFile(t, filepath.Join(clone, "tracked"), "one")
+	gitRun(t, clone, "add", "tracked")
+	gitRun(t, clone, "commit", "-q", "-m", "tracked")
+	mustWriteFile(t, filepath.Join(clone, "tracked"), "two")
+	mustWriteFile(t, filepath.Join(clone, "stashed"), "one")
+	gitRun(t, clone, "stash", "-q", "--include-untracked")
+	mustWriteFile(t, filepath.Join(clone, "untracked"), "one")
+	gitRun(t, root, "init", "-q", "-b", "trunk", unborn)
+	if err := os.Mkdir(plain, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	projects := []libs.Entry{
+		{Alias: "clone", Record: libs.Record{Path: clone}},
+		{Alias: "upstream", Record: libs.Record{Path: upstream}},
+		{Alias: "unborn", Record: libs.Record{Path: unborn}},
+		{Alias: "plain", Record: libs.Record{Path: plain}},
+		{Alias: "gone", Record: libs.Record{Path: filepath.Join(root, "gone")}},
+	}
+	statuses := libs.NewStatusService().Status(context.Background(), projects, libs.StatusOptions{Parallel: 2, Timeout: time.Minute})
+	assert.Len(t, statuses, len(projects))
+
+	cloneStatus := statuses[0]
+	assert.Equal(t, libs.RepoOK, cloneStatus.State)
+	assert.Equal(t, "main", cloneStatus.Branch)
+	assert.Equal(t, "origin/main", cloneStatus.Upstream)
+	assert.Equal(t, 2, cloneStatus.Ahead)
+	assert.Equal(t, 1, cloneStatus.Behind)
+	assert.Equal(t, 0, cloneStatus.Dirty)
+	assert.Equal(t, 1, cloneStatus.Untracked)
+	assert.Equal(t, 1, cloneStatus.Stashes)
+	assert.WithinDuration(t, time.Now(), cloneStatus.LastCommit, time.Minute)
+	assert.NoError(t, cloneStatus.Err)
+
+	upstreamStatus := statuses[1]
+	assert.Equal(t, libs.RepoOK, upstreamStatus.State)
+	assert.Equal(t, "main", upstreamStatus.Branch)
+	assert.Empty(t, upstreamStatus.Upstream)
+
+	unbornStatus := statuses[2]
+	assert.Equal(t, libs.RepoOK, unbornStatus.State)
+	assert.Equal(t, "trunk", unbornStatus.Branch)
+	assert.True(t, unbornStatus.LastCommit.IsZero())
+
+	assert.Equal(t, libs.RepoBroken, statuses[3].State)
+	assert.Error(t, statuses[3].Err)
+	assert.Equal(t, libs.RepoMissing, statuses[4].State)
+	assert.Error(t, statuses[4].Err)
+	for i, status := range statuses {
+		assert.Equal(t, projects[i].Alias, status.Alias)
+	}
+}
+
+func TestStatusService_StatusDirtyAndDetached(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+
+	repo := t.TempDir()
+	gitRun(t, repo, "init", "-q")
+	mustWriteFile(t, filepath.Join(repo, "a"), "one")
+	mustWriteFile(t, filepath.Join(repo, "b"), "one")
+	gitRun(t, repo, "add", "a", "b")
+	gitRun(t, repo, "commit", "-q", "-m", "first")
+	gitRun(t, repo, "checkout", "-q", "--detach")
+	mustWriteFile(t, filepath.Join(repo, "a"), "two")
+	mustWriteFile(t, filepath.Join(repo, "b"), "two")
+	gitRun(t, repo, "add", "b")
+
+	statuses := libs.NewStatusService().Status(context.Background(),
+		[]libs.Entry{{Alias: "repo", Record: libs.Record{Path: repo}}}, libs.StatusOptions{})
+
+	assert.Equal(t, libs.RepoOK, statuses[0].State)
+	assert.Len(t, statuses[0].Branch, 7)
+	assert.Equal(t, 2, statuses[0].Dirty)
+	assert.Equal(t, 0, statuses[0].Untracked)
+}
+
+func TestStatusService_StatusTimeout(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	gitRun(t, repo, "init", "-q")
+
+	// A cancelled context makes every git call fail at once
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	statuses := libs.NewStatusService().Status(ctx,
+		[]libs.Entry{{Alias: "repo", Record: libs.Record{Path: repo}}}, libs.StatusOptions{Timeout: time.Minute})
+
+	assert.Equal(t, libs.RepoTimeout, statuses[0].State)
+	assert.ErrorIs(t, statuses[0].Err, context.Canceled)
+}
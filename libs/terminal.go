@@ -0,0 +1,97 @@
+package libs
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+const (
+	enterAltScreen = "\x1b[?1049h"
+	leaveAltScreen = "\x1b[?1049l"
+)
+
+// TerminalPicker runs the full-screen Picker when both in and out are
+// terminals and falls back to PickNumbered otherwise. It draws on out, which
+// should be stderr so that stdout only carries the selected path.
+type TerminalPicker struct {
+	in       *os.File
+	out      *os.File
+	finder   string
+	previews map[string]string
+}
+
+type TerminalPickerOption func(*TerminalPicker)
+
+// WithFinder sets the external finder PickWith uses when none is given.
+func WithFinder(finder string) TerminalPickerOption {
+	return func(t *TerminalPicker) {
+		t.finder = finder
+	}
+}
+
+// WithFinderPreviews sets the preview command passed to each finder, keyed
+// by finder name.
+func WithFinderPreviews(previews map[string]string) TerminalPickerOption {
+	return func(t *TerminalPicker) {
+		t.previews = previews
+	}
+}
+
+func NewTerminalPicker(in, out *os.File, opts ...TerminalPickerOption) *TerminalPicker {
+	t := &TerminalPicker{in: in, out: out}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+func (t *TerminalPicker) Pick(items []PickerItem) (PickerItem, error) {
+	inFd := int(t.in.Fd())
+	if !term.IsTerminal(inFd) || !term.IsTerminal(int(t.out.Fd())) {
+		return PickNumbered(items, t.in, t.out)
+	}
+
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		return PickerItem{}, err
+	}
+	defer term.Restore(inFd, state)
+
+	io.WriteString(t.out, enterAltScreen)
+	defer io.WriteString(t.out, leaveAltScreen)
+
+	return NewPicker(items).Run(&ttyTerminal{in: t.in, out: t.out})
+}
+
+// PickWith lets the external finder choose among items. An empty finder
+// means the configured one; without that, or for BuiltinFinder, it is the
+// same as Pick. It returns ErrFinderNotFound if the finder is not installed.
+func (t *TerminalPicker) PickWith(finder string, items []PickerItem) (PickerItem, error) {
+	if finder == "" {
+		finder = t.finder
+	}
+	if finder == "" || finder == BuiltinFinder {
+		return t.Pick(items)
+	}
+	return runFinder(finder, t.previews[finder], items, t.out)
+}
+
+// ttyTerminal reads keys from in and draws on out.
+type ttyTerminal struct {
+	in  *os.File
+	out *os.File
+}
+
+func (t *ttyTerminal) Read(p []byte) (int, error) {
+	return t.in.Read(p)
+}
+
+func (t *ttyTerminal) Write(p []byte) (int, error) {
+	return t.out.Write(p)
+}
+
+func (t *ttyTerminal) Size() (int, int, error) {
+	return term.GetSize(int(t.out.Fd()))
+}
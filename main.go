@@ -1,11 +1,16 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"gs/cmd"
 	"gs/libs"
+	"io"
 	"os"
+	"strings"
 
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
@@ -16,31 +21,112 @@ func errorHandler(err error, msg string) {
 	}
 }
 
-func main() {
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
+// parseConfigFlags reads the config flags from the command line before cobra
+// runs, since the database is opened with the config, and binds them to v.
+// Everything else is left for cobra, which reports any mistakes.
+func parseConfigFlags(v *viper.Viper, args []string) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("gs", pflag.ContinueOnError)
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.SetOutput(io.Discard)
+	flags.Usage = func() {}
+	cmd.AddConfigFlags(flags)
+	_ = flags.Parse(args)
 
-	err := viper.ReadInConfig()
+	for _, key := range libs.ConfigKeys {
+		if err := v.BindPFlag(key.Name, flags.Lookup(cmd.ConfigFlagName(key))); err != nil {
+			errorHandler(err, "Config flag error")
+		}
+	}
+	return flags
+}
+
+// profileOverride returns the flag or environment variable that set
+// default_profile, which then takes precedence over profile_rules.
+func profileOverride(flags *pflag.FlagSet) string {
+	key, _ := libs.LookupConfigKey("default_profile")
+	if name := cmd.ConfigFlagName(key); flags.Changed(name) {
+		return "--" + name
+	}
+	envs := []string{key.Env, libs.ConfigEnvPrefix + "_" + strings.ToUpper(key.Name)}
+	for _, env := range envs {
+		if env != "" && os.Getenv(env) != "" {
+			return "$" + env
+		}
+	}
+	return ""
+}
 
+func main() {
+	flags := parseConfigFlags(viper.GetViper(), os.Args[1:])
+	config, err := libs.LoadConfig(viper.GetViper())
 	if err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			errorHandler(err, "Config file not found")
-		} else {
+		if config == nil {
 			errorHandler(err, "Config file error")
 		}
+		// Without a usable config only the commands that can fix it run
+		execute(cmd.NewConfigRepairCommand(config, err))
+		return
+	}
+	settings := config.Config()
+
+	dbPath, moved, err := libs.ResolveDBPath(settings.AppName, settings.DBPath)
+	if err != nil {
+		errorHandler(err, "Database path error")
+	}
+	if moved != nil {
+		fmt.Fprintf(os.Stderr, "moved database from %s to %s (backup at %s)\n", moved.From, moved.To, moved.Backup)
+	}
+	db, err := libs.OpenBoltDB(dbPath, settings.KVBucketName)
+	if err != nil {
+		errorHandler(err, "OpenBoltDB error")
 	}
 
-	bucketName := viper.GetString("kv_bucket_name")
-	db, err := libs.NewBoltDB(bucketName)
+	wd, err := os.Getwd()
+	if err != nil {
+		errorHandler(err, "Working directory error")
+	}
+	profile, err := settings.SelectProfile(wd, profileOverride(flags))
 	if err != nil {
-		errorHandler(err, "NewBoltDB error")
+		errorHandler(err, "Profile selection error")
+	}
+	dbService := libs.NewDBService(db, settings.KVBucketName, libs.WithProfileSelection(profile))
+	fileService := libs.NewFileService(libs.WithScanIgnore(settings.Scan.Ignore))
+
+	migrator := libs.NewMigrator(db, libs.MigrationEnv{
+		KVBucketName:  settings.KVBucketName,
+		NormalizePath: fileService.CanonicalizeStoredPath,
+	})
+
+	picker := libs.NewTerminalPicker(os.Stdin, os.Stderr,
+		libs.WithFinder(settings.Pick.Finder),
+		libs.WithFinderPreviews(settings.Pick.Preview),
+	)
+
+	rootCmd := cmd.NewRootCommand(dbService, fileService, migrator, picker, libs.NewExecService(), libs.NewStatusService(), config)
+	if target, _, err := rootCmd.Find(os.Args[1:]); err != nil || !cmd.ReportsUnknownConfigKeys(target) {
+		warnUnknownConfigKeys(config)
 	}
+	execute(rootCmd)
+}
 
-	dbService := libs.NewDBService(db, bucketName)
-	fileService := libs.NewFileService()
+// warnUnknownConfigKeys prints a warning on stderr for every key in the
+// config file that gs does not know.
+func warnUnknownConfigKeys(config *libs.ConfigService) {
+	unknown, err := config.UnknownKeys()
+	if err != nil {
+		errorHandler(err, "Config file error")
+	}
+	for _, key := range unknown {
+		fmt.Fprintf(os.Stderr, "%s in %s\n", cmd.UnknownConfigKeyWarning(key), config.File())
+	}
+}
 
-	rootCmd := cmd.NewRootCommand(dbService, fileService)
+func execute(rootCmd *cobra.Command) {
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *cmd.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		errorHandler(err, "Execute error")
 	}
 }
@@ -10,6 +10,7 @@
 package mocks
 
 import (
+	libs "gs/libs"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -40,17 +41,18 @@ func (m *MockDBService) EXPECT() *MockDBServiceMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockDBService) Add(alias, path string) error {
+func (m *MockDBService) Add(alias, path string, opts libs.AddOptions) (libs.AddResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", alias, path)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Add", alias, path, opts)
+	ret0, _ := ret[0].(libs.AddResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockDBServiceMockRecorder) Add(alias, path any) *gomock.Call {
+func (mr *MockDBServiceMockRecorder) Add(alias, path, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockDBService)(nil).Add), alias, path)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockDBService)(nil).Add), alias, path, opts)
 }
 
 // MockFileService is a mock of FileService interface.
@@ -92,6 +94,36 @@ func (mr *MockFileServiceMockRecorder) CheckIfPathExists(path any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfPathExists", reflect.TypeOf((*MockFileService)(nil).CheckIfPathExists), path)
 }
 
+// DetectGitRepository mocks base method.
+func (m *MockFileService) DetectGitRepository(path string) (libs.GitRepository, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectGitRepository", path)
+	ret0, _ := ret[0].(libs.GitRepository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectGitRepository indicates an expected call of DetectGitRepository.
+func (mr *MockFileServiceMockRecorder) DetectGitRepository(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectGitRepository", reflect.TypeOf((*MockFileService)(nil).DetectGitRepository), path)
+}
+
+// FindRepoRoot mocks base method.
+func (m *MockFileService) FindRepoRoot(path string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRepoRoot", path)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRepoRoot indicates an expected call of FindRepoRoot.
+func (mr *MockFileServiceMockRecorder) FindRepoRoot(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRepoRoot", reflect.TypeOf((*MockFileService)(nil).FindRepoRoot), path)
+}
+
 // GetCurrentPath mocks base method.
 func (m *MockFileService) GetCurrentPath() (string, error) {
 	m.ctrl.T.Helper()
@@ -107,16 +139,85 @@ func (mr *MockFileServiceMockRecorder) GetCurrentPath() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentPath", reflect.TypeOf((*MockFileService)(nil).GetCurrentPath))
 }
 
-// GetParentFolderName mocks base method.
-func (m *MockFileService) GetParentFolderName(path string) string {
+// GetFolderName mocks base method.
+func (m *MockFileService) GetFolderName(path string) string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetParentFolderName", path)
+	ret := m.ctrl.Call(m, "GetFolderName", path)
 	ret0, _ := ret[0].(string)
 	return ret0
 }
 
-// GetParentFolderName indicates an expected call of GetParentFolderName.
-func (mr *MockFileServiceMockRecorder) GetParentFolderName(path any) *gomock.Call {
+// GetFolderName indicates an expected call of GetFolderName.
+func (mr *MockFileServiceMockRecorder) GetFolderName(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFolderName", reflect.TypeOf((*MockFileService)(nil).GetFolderName), path)
+}
+
+// NormalizePath mocks base method.
+func (m *MockFileService) NormalizePath(path string, resolveSymlinks bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NormalizePath", path, resolveSymlinks)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NormalizePath indicates an expected call of NormalizePath.
+func (mr *MockFileServiceMockRecorder) NormalizePath(path, resolveSymlinks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NormalizePath", reflect.TypeOf((*MockFileService)(nil).NormalizePath), path, resolveSymlinks)
+}
+
+// RepoIdentity mocks base method.
+func (m *MockFileService) RepoIdentity(path string) (libs.RepoIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepoIdentity", path)
+	ret0, _ := ret[0].(libs.RepoIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepoIdentity indicates an expected call of RepoIdentity.
+func (mr *MockFileServiceMockRecorder) RepoIdentity(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepoIdentity", reflect.TypeOf((*MockFileService)(nil).RepoIdentity), path)
+}
+
+// MockPathNormalizer is a mock of PathNormalizer interface.
+type MockPathNormalizer struct {
+	ctrl     *gomock.Controller
+	recorder *MockPathNormalizerMockRecorder
+	isgomock struct{}
+}
+
+// MockPathNormalizerMockRecorder is the mock recorder for MockPathNormalizer.
+type MockPathNormalizerMockRecorder struct {
+	mock *MockPathNormalizer
+}
+
+// NewMockPathNormalizer creates a new mock instance.
+func NewMockPathNormalizer(ctrl *gomock.Controller) *MockPathNormalizer {
+	mock := &MockPathNormalizer{ctrl: ctrl}
+	mock.recorder = &MockPathNormalizerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPathNormalizer) EXPECT() *MockPathNormalizerMockRecorder {
+	return m.recorder
+}
+
+// NormalizePath mocks base method.
+func (m *MockPathNormalizer) NormalizePath(path string, resolveSymlinks bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NormalizePath", path, resolveSymlinks)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NormalizePath indicates an expected call of NormalizePath.
+func (mr *MockPathNormalizerMockRecorder) NormalizePath(path, resolveSymlinks any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParentFolderName", reflect.TypeOf((*MockFileService)(nil).GetParentFolderName), path)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NormalizePath", reflect.TypeOf((*MockPathNormalizer)(nil).NormalizePath), path, resolveSymlinks)
 }
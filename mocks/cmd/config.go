@@ -0,0 +1,341 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: config.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/config.go -package=mocks -source=config.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	io "io"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConfigLocator is a mock of ConfigLocator interface.
+type MockConfigLocator struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigLocatorMockRecorder
+	isgomock struct{}
+}
+
+// MockConfigLocatorMockRecorder is the mock recorder for MockConfigLocator.
+type MockConfigLocatorMockRecorder struct {
+	mock *MockConfigLocator
+}
+
+// NewMockConfigLocator creates a new mock instance.
+func NewMockConfigLocator(ctrl *gomock.Controller) *MockConfigLocator {
+	mock := &MockConfigLocator{ctrl: ctrl}
+	mock.recorder = &MockConfigLocatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigLocator) EXPECT() *MockConfigLocatorMockRecorder {
+	return m.recorder
+}
+
+// File mocks base method.
+func (m *MockConfigLocator) File() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "File")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// File indicates an expected call of File.
+func (mr *MockConfigLocatorMockRecorder) File() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "File", reflect.TypeOf((*MockConfigLocator)(nil).File))
+}
+
+// SearchPaths mocks base method.
+func (m *MockConfigLocator) SearchPaths() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPaths")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SearchPaths indicates an expected call of SearchPaths.
+func (mr *MockConfigLocatorMockRecorder) SearchPaths() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPaths", reflect.TypeOf((*MockConfigLocator)(nil).SearchPaths))
+}
+
+// MockConfigReader is a mock of ConfigReader interface.
+type MockConfigReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockConfigReaderMockRecorder is the mock recorder for MockConfigReader.
+type MockConfigReaderMockRecorder struct {
+	mock *MockConfigReader
+}
+
+// NewMockConfigReader creates a new mock instance.
+func NewMockConfigReader(ctrl *gomock.Controller) *MockConfigReader {
+	mock := &MockConfigReader{ctrl: ctrl}
+	mock.recorder = &MockConfigReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigReader) EXPECT() *MockConfigReaderMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockConfigReader) Get(key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockConfigReaderMockRecorder) Get(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockConfigReader)(nil).Get), key)
+}
+
+// Keys mocks base method.
+func (m *MockConfigReader) Keys() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockConfigReaderMockRecorder) Keys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockConfigReader)(nil).Keys))
+}
+
+// UnknownKeys mocks base method.
+func (m *MockConfigReader) UnknownKeys() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnknownKeys")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnknownKeys indicates an expected call of UnknownKeys.
+func (mr *MockConfigReaderMockRecorder) UnknownKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnknownKeys", reflect.TypeOf((*MockConfigReader)(nil).UnknownKeys))
+}
+
+// MockConfigWriter is a mock of ConfigWriter interface.
+type MockConfigWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigWriterMockRecorder
+	isgomock struct{}
+}
+
+// MockConfigWriterMockRecorder is the mock recorder for MockConfigWriter.
+type MockConfigWriterMockRecorder struct {
+	mock *MockConfigWriter
+}
+
+// NewMockConfigWriter creates a new mock instance.
+func NewMockConfigWriter(ctrl *gomock.Controller) *MockConfigWriter {
+	mock := &MockConfigWriter{ctrl: ctrl}
+	mock.recorder = &MockConfigWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigWriter) EXPECT() *MockConfigWriterMockRecorder {
+	return m.recorder
+}
+
+// Edit mocks base method.
+func (m *MockConfigWriter) Edit(stdin io.Reader, stdout, stderr io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", stdin, stdout, stderr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockConfigWriterMockRecorder) Edit(stdin, stdout, stderr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockConfigWriter)(nil).Edit), stdin, stdout, stderr)
+}
+
+// Set mocks base method.
+func (m *MockConfigWriter) Set(key, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockConfigWriterMockRecorder) Set(key, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockConfigWriter)(nil).Set), key, value)
+}
+
+// WritePath mocks base method.
+func (m *MockConfigWriter) WritePath() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WritePath")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// WritePath indicates an expected call of WritePath.
+func (mr *MockConfigWriterMockRecorder) WritePath() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WritePath", reflect.TypeOf((*MockConfigWriter)(nil).WritePath))
+}
+
+// MockConfigManager is a mock of ConfigManager interface.
+type MockConfigManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockConfigManagerMockRecorder is the mock recorder for MockConfigManager.
+type MockConfigManagerMockRecorder struct {
+	mock *MockConfigManager
+}
+
+// NewMockConfigManager creates a new mock instance.
+func NewMockConfigManager(ctrl *gomock.Controller) *MockConfigManager {
+	mock := &MockConfigManager{ctrl: ctrl}
+	mock.recorder = &MockConfigManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigManager) EXPECT() *MockConfigManagerMockRecorder {
+	return m.recorder
+}
+
+// Edit mocks base method.
+func (m *MockConfigManager) Edit(stdin io.Reader, stdout, stderr io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", stdin, stdout, stderr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockConfigManagerMockRecorder) Edit(stdin, stdout, stderr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockConfigManager)(nil).Edit), stdin, stdout, stderr)
+}
+
+// File mocks base method.
+func (m *MockConfigManager) File() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "File")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// File indicates an expected call of File.
+func (mr *MockConfigManagerMockRecorder) File() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "File", reflect.TypeOf((*MockConfigManager)(nil).File))
+}
+
+// Get mocks base method.
+func (m *MockConfigManager) Get(key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockConfigManagerMockRecorder) Get(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockConfigManager)(nil).Get), key)
+}
+
+// Keys mocks base method.
+func (m *MockConfigManager) Keys() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockConfigManagerMockRecorder) Keys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockConfigManager)(nil).Keys))
+}
+
+// SearchPaths mocks base method.
+func (m *MockConfigManager) SearchPaths() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPaths")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SearchPaths indicates an expected call of SearchPaths.
+func (mr *MockConfigManagerMockRecorder) SearchPaths() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPaths", reflect.TypeOf((*MockConfigManager)(nil).SearchPaths))
+}
+
+// Set mocks base method.
+func (m *MockConfigManager) Set(key, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockConfigManagerMockRecorder) Set(key, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockConfigManager)(nil).Set), key, value)
+}
+
+// UnknownKeys mocks base method.
+func (m *MockConfigManager) UnknownKeys() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnknownKeys")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnknownKeys indicates an expected call of UnknownKeys.
+func (mr *MockConfigManagerMockRecorder) UnknownKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnknownKeys", reflect.TypeOf((*MockConfigManager)(nil).UnknownKeys))
+}
+
+// WritePath mocks base method.
+func (m *MockConfigManager) WritePath() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WritePath")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// WritePath indicates an expected call of WritePath.
+func (mr *MockConfigManagerMockRecorder) WritePath() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WritePath", reflect.TypeOf((*MockConfigManager)(nil).WritePath))
+}
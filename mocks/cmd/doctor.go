@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: doctor.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/doctor.go -package=mocks -source=doctor.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	libs "gs/libs"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHealthChecker is a mock of HealthChecker interface.
+type MockHealthChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthCheckerMockRecorder
+	isgomock struct{}
+}
+
+// MockHealthCheckerMockRecorder is the mock recorder for MockHealthChecker.
+type MockHealthCheckerMockRecorder struct {
+	mock *MockHealthChecker
+}
+
+// NewMockHealthChecker creates a new mock instance.
+func NewMockHealthChecker(ctrl *gomock.Controller) *MockHealthChecker {
+	mock := &MockHealthChecker{ctrl: ctrl}
+	mock.recorder = &MockHealthCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthChecker) EXPECT() *MockHealthCheckerMockRecorder {
+	return m.recorder
+}
+
+// CheckIfPathExists mocks base method.
+func (m *MockHealthChecker) CheckIfPathExists(path string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIfPathExists", path)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIfPathExists indicates an expected call of CheckIfPathExists.
+func (mr *MockHealthCheckerMockRecorder) CheckIfPathExists(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfPathExists", reflect.TypeOf((*MockHealthChecker)(nil).CheckIfPathExists), path)
+}
+
+// DetectGitRepository mocks base method.
+func (m *MockHealthChecker) DetectGitRepository(path string) (libs.GitRepository, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectGitRepository", path)
+	ret0, _ := ret[0].(libs.GitRepository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectGitRepository indicates an expected call of DetectGitRepository.
+func (mr *MockHealthCheckerMockRecorder) DetectGitRepository(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectGitRepository", reflect.TypeOf((*MockHealthChecker)(nil).DetectGitRepository), path)
+}
@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: exec.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/exec.go -package=mocks -source=exec.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	libs "gs/libs"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAliasIterator is a mock of AliasIterator interface.
+type MockAliasIterator struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasIteratorMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasIteratorMockRecorder is the mock recorder for MockAliasIterator.
+type MockAliasIteratorMockRecorder struct {
+	mock *MockAliasIterator
+}
+
+// NewMockAliasIterator creates a new mock instance.
+func NewMockAliasIterator(ctrl *gomock.Controller) *MockAliasIterator {
+	mock := &MockAliasIterator{ctrl: ctrl}
+	mock.recorder = &MockAliasIteratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasIterator) EXPECT() *MockAliasIteratorMockRecorder {
+	return m.recorder
+}
+
+// ForEach mocks base method.
+func (m *MockAliasIterator) ForEach(fn func(libs.Entry) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEach", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEach indicates an expected call of ForEach.
+func (mr *MockAliasIteratorMockRecorder) ForEach(fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEach", reflect.TypeOf((*MockAliasIterator)(nil).ForEach), fn)
+}
+
+// MockProjectSelector is a mock of ProjectSelector interface.
+type MockProjectSelector struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectSelectorMockRecorder
+	isgomock struct{}
+}
+
+// MockProjectSelectorMockRecorder is the mock recorder for MockProjectSelector.
+type MockProjectSelectorMockRecorder struct {
+	mock *MockProjectSelector
+}
+
+// NewMockProjectSelector creates a new mock instance.
+func NewMockProjectSelector(ctrl *gomock.Controller) *MockProjectSelector {
+	mock := &MockProjectSelector{ctrl: ctrl}
+	mock.recorder = &MockProjectSelectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjectSelector) EXPECT() *MockProjectSelectorMockRecorder {
+	return m.recorder
+}
+
+// ForEach mocks base method.
+func (m *MockProjectSelector) ForEach(fn func(libs.Entry) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEach", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEach indicates an expected call of ForEach.
+func (mr *MockProjectSelectorMockRecorder) ForEach(fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEach", reflect.TypeOf((*MockProjectSelector)(nil).ForEach), fn)
+}
+
+// Release mocks base method.
+func (m *MockProjectSelector) Release() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockProjectSelectorMockRecorder) Release() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockProjectSelector)(nil).Release))
+}
+
+// MockProjectRunner is a mock of ProjectRunner interface.
+type MockProjectRunner struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectRunnerMockRecorder
+	isgomock struct{}
+}
+
+// MockProjectRunnerMockRecorder is the mock recorder for MockProjectRunner.
+type MockProjectRunnerMockRecorder struct {
+	mock *MockProjectRunner
+}
+
+// NewMockProjectRunner creates a new mock instance.
+func NewMockProjectRunner(ctrl *gomock.Controller) *MockProjectRunner {
+	mock := &MockProjectRunner{ctrl: ctrl}
+	mock.recorder = &MockProjectRunnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjectRunner) EXPECT() *MockProjectRunnerMockRecorder {
+	return m.recorder
+}
+
+// Run mocks base method.
+func (m *MockProjectRunner) Run(ctx context.Context, projects []libs.Entry, command []string, opts libs.ExecOptions) []libs.ExecResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx, projects, command, opts)
+	ret0, _ := ret[0].([]libs.ExecResult)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockProjectRunnerMockRecorder) Run(ctx, projects, command, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockProjectRunner)(nil).Run), ctx, projects, command, opts)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: list.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/list.go -package=mocks -source=list.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	libs "gs/libs"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAliasLister is a mock of AliasLister interface.
+type MockAliasLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasListerMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasListerMockRecorder is the mock recorder for MockAliasLister.
+type MockAliasListerMockRecorder struct {
+	mock *MockAliasLister
+}
+
+// NewMockAliasLister creates a new mock instance.
+func NewMockAliasLister(ctrl *gomock.Controller) *MockAliasLister {
+	mock := &MockAliasLister{ctrl: ctrl}
+	mock.recorder = &MockAliasListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasLister) EXPECT() *MockAliasListerMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockAliasLister) List() ([]libs.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]libs.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAliasListerMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAliasLister)(nil).List))
+}
@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: mv.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/mv.go -package=mocks -source=mv.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAliasMover is a mock of AliasMover interface.
+type MockAliasMover struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasMoverMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasMoverMockRecorder is the mock recorder for MockAliasMover.
+type MockAliasMoverMockRecorder struct {
+	mock *MockAliasMover
+}
+
+// NewMockAliasMover creates a new mock instance.
+func NewMockAliasMover(ctrl *gomock.Controller) *MockAliasMover {
+	mock := &MockAliasMover{ctrl: ctrl}
+	mock.recorder = &MockAliasMoverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasMover) EXPECT() *MockAliasMoverMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockAliasMover) Get(alias string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", alias)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAliasMoverMockRecorder) Get(alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAliasMover)(nil).Get), alias)
+}
+
+// Move mocks base method.
+func (m *MockAliasMover) Move(alias, path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", alias, path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockAliasMoverMockRecorder) Move(alias, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockAliasMover)(nil).Move), alias, path)
+}
@@ -0,0 +1,261 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pick.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/pick.go -package=mocks -source=pick.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	libs "gs/libs"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProjectPicker is a mock of ProjectPicker interface.
+type MockProjectPicker struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectPickerMockRecorder
+	isgomock struct{}
+}
+
+// MockProjectPickerMockRecorder is the mock recorder for MockProjectPicker.
+type MockProjectPickerMockRecorder struct {
+	mock *MockProjectPicker
+}
+
+// NewMockProjectPicker creates a new mock instance.
+func NewMockProjectPicker(ctrl *gomock.Controller) *MockProjectPicker {
+	mock := &MockProjectPicker{ctrl: ctrl}
+	mock.recorder = &MockProjectPickerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjectPicker) EXPECT() *MockProjectPickerMockRecorder {
+	return m.recorder
+}
+
+// Pick mocks base method.
+func (m *MockProjectPicker) Pick(items []libs.PickerItem) (libs.PickerItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pick", items)
+	ret0, _ := ret[0].(libs.PickerItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pick indicates an expected call of Pick.
+func (mr *MockProjectPickerMockRecorder) Pick(items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pick", reflect.TypeOf((*MockProjectPicker)(nil).Pick), items)
+}
+
+// PickWith mocks base method.
+func (m *MockProjectPicker) PickWith(finder string, items []libs.PickerItem) (libs.PickerItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PickWith", finder, items)
+	ret0, _ := ret[0].(libs.PickerItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PickWith indicates an expected call of PickWith.
+func (mr *MockProjectPickerMockRecorder) PickWith(finder, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PickWith", reflect.TypeOf((*MockProjectPicker)(nil).PickWith), finder, items)
+}
+
+// MockBranchReader is a mock of BranchReader interface.
+type MockBranchReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockBranchReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockBranchReaderMockRecorder is the mock recorder for MockBranchReader.
+type MockBranchReaderMockRecorder struct {
+	mock *MockBranchReader
+}
+
+// NewMockBranchReader creates a new mock instance.
+func NewMockBranchReader(ctrl *gomock.Controller) *MockBranchReader {
+	mock := &MockBranchReader{ctrl: ctrl}
+	mock.recorder = &MockBranchReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBranchReader) EXPECT() *MockBranchReaderMockRecorder {
+	return m.recorder
+}
+
+// GitBranch mocks base method.
+func (m *MockBranchReader) GitBranch(path string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GitBranch", path)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GitBranch indicates an expected call of GitBranch.
+func (mr *MockBranchReaderMockRecorder) GitBranch(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GitBranch", reflect.TypeOf((*MockBranchReader)(nil).GitBranch), path)
+}
+
+// MockPickSource is a mock of PickSource interface.
+type MockPickSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockPickSourceMockRecorder
+	isgomock struct{}
+}
+
+// MockPickSourceMockRecorder is the mock recorder for MockPickSource.
+type MockPickSourceMockRecorder struct {
+	mock *MockPickSource
+}
+
+// NewMockPickSource creates a new mock instance.
+func NewMockPickSource(ctrl *gomock.Controller) *MockPickSource {
+	mock := &MockPickSource{ctrl: ctrl}
+	mock.recorder = &MockPickSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPickSource) EXPECT() *MockPickSourceMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockPickSource) List() ([]libs.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]libs.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPickSourceMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPickSource)(nil).List))
+}
+
+// Release mocks base method.
+func (m *MockPickSource) Release() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockPickSourceMockRecorder) Release() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockPickSource)(nil).Release))
+}
+
+// MockAliasPicker is a mock of AliasPicker interface.
+type MockAliasPicker struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasPickerMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasPickerMockRecorder is the mock recorder for MockAliasPicker.
+type MockAliasPickerMockRecorder struct {
+	mock *MockAliasPicker
+}
+
+// NewMockAliasPicker creates a new mock instance.
+func NewMockAliasPicker(ctrl *gomock.Controller) *MockAliasPicker {
+	mock := &MockAliasPicker{ctrl: ctrl}
+	mock.recorder = &MockAliasPickerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasPicker) EXPECT() *MockAliasPickerMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockAliasPicker) List() ([]libs.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]libs.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAliasPickerMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAliasPicker)(nil).List))
+}
+
+// Match mocks base method.
+func (m *MockAliasPicker) Match(query string) (libs.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Match", query)
+	ret0, _ := ret[0].(libs.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Match indicates an expected call of Match.
+func (mr *MockAliasPickerMockRecorder) Match(query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Match", reflect.TypeOf((*MockAliasPicker)(nil).Match), query)
+}
+
+// PreviousAlias mocks base method.
+func (m *MockAliasPicker) PreviousAlias() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviousAlias")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviousAlias indicates an expected call of PreviousAlias.
+func (mr *MockAliasPickerMockRecorder) PreviousAlias() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviousAlias", reflect.TypeOf((*MockAliasPicker)(nil).PreviousAlias))
+}
+
+// Release mocks base method.
+func (m *MockAliasPicker) Release() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockAliasPickerMockRecorder) Release() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockAliasPicker)(nil).Release))
+}
+
+// Visit mocks base method.
+func (m *MockAliasPicker) Visit(alias string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Visit", alias)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Visit indicates an expected call of Visit.
+func (mr *MockAliasPickerMockRecorder) Visit(alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Visit", reflect.TypeOf((*MockAliasPicker)(nil).Visit), alias)
+}
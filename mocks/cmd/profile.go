@@ -0,0 +1,192 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: profile.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/profile.go -package=mocks -source=profile.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	libs "gs/libs"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProfileChecker is a mock of ProfileChecker interface.
+type MockProfileChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileCheckerMockRecorder
+	isgomock struct{}
+}
+
+// MockProfileCheckerMockRecorder is the mock recorder for MockProfileChecker.
+type MockProfileCheckerMockRecorder struct {
+	mock *MockProfileChecker
+}
+
+// NewMockProfileChecker creates a new mock instance.
+func NewMockProfileChecker(ctrl *gomock.Controller) *MockProfileChecker {
+	mock := &MockProfileChecker{ctrl: ctrl}
+	mock.recorder = &MockProfileCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfileChecker) EXPECT() *MockProfileCheckerMockRecorder {
+	return m.recorder
+}
+
+// CheckProfile mocks base method.
+func (m *MockProfileChecker) CheckProfile() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckProfile")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckProfile indicates an expected call of CheckProfile.
+func (mr *MockProfileCheckerMockRecorder) CheckProfile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckProfile", reflect.TypeOf((*MockProfileChecker)(nil).CheckProfile))
+}
+
+// MockProfileManager is a mock of ProfileManager interface.
+type MockProfileManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockProfileManagerMockRecorder is the mock recorder for MockProfileManager.
+type MockProfileManagerMockRecorder struct {
+	mock *MockProfileManager
+}
+
+// NewMockProfileManager creates a new mock instance.
+func NewMockProfileManager(ctrl *gomock.Controller) *MockProfileManager {
+	mock := &MockProfileManager{ctrl: ctrl}
+	mock.recorder = &MockProfileManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfileManager) EXPECT() *MockProfileManagerMockRecorder {
+	return m.recorder
+}
+
+// CopyProfile mocks base method.
+func (m *MockProfileManager) CopyProfile(from, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyProfile", from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyProfile indicates an expected call of CopyProfile.
+func (mr *MockProfileManagerMockRecorder) CopyProfile(from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyProfile", reflect.TypeOf((*MockProfileManager)(nil).CopyProfile), from, to)
+}
+
+// CreateProfile mocks base method.
+func (m *MockProfileManager) CreateProfile(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProfile", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProfile indicates an expected call of CreateProfile.
+func (mr *MockProfileManagerMockRecorder) CreateProfile(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProfile", reflect.TypeOf((*MockProfileManager)(nil).CreateProfile), name)
+}
+
+// DeleteProfile mocks base method.
+func (m *MockProfileManager) DeleteProfile(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProfile", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProfile indicates an expected call of DeleteProfile.
+func (mr *MockProfileManagerMockRecorder) DeleteProfile(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProfile", reflect.TypeOf((*MockProfileManager)(nil).DeleteProfile), name)
+}
+
+// Profile mocks base method.
+func (m *MockProfileManager) Profile() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Profile")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Profile indicates an expected call of Profile.
+func (mr *MockProfileManagerMockRecorder) Profile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Profile", reflect.TypeOf((*MockProfileManager)(nil).Profile))
+}
+
+// ProfileSelection mocks base method.
+func (m *MockProfileManager) ProfileSelection() libs.ProfileSelection {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProfileSelection")
+	ret0, _ := ret[0].(libs.ProfileSelection)
+	return ret0
+}
+
+// ProfileSelection indicates an expected call of ProfileSelection.
+func (mr *MockProfileManagerMockRecorder) ProfileSelection() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProfileSelection", reflect.TypeOf((*MockProfileManager)(nil).ProfileSelection))
+}
+
+// Profiles mocks base method.
+func (m *MockProfileManager) Profiles() ([]libs.ProfileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Profiles")
+	ret0, _ := ret[0].([]libs.ProfileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Profiles indicates an expected call of Profiles.
+func (mr *MockProfileManagerMockRecorder) Profiles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Profiles", reflect.TypeOf((*MockProfileManager)(nil).Profiles))
+}
+
+// Release mocks base method.
+func (m *MockProfileManager) Release() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockProfileManagerMockRecorder) Release() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockProfileManager)(nil).Release))
+}
+
+// RenameProfile mocks base method.
+func (m *MockProfileManager) RenameProfile(oldName, newName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameProfile", oldName, newName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameProfile indicates an expected call of RenameProfile.
+func (mr *MockProfileManagerMockRecorder) RenameProfile(oldName, newName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameProfile", reflect.TypeOf((*MockProfileManager)(nil).RenameProfile), oldName, newName)
+}
@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: prune.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/prune.go -package=mocks -source=prune.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	libs "gs/libs"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAliasPruner is a mock of AliasPruner interface.
+type MockAliasPruner struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasPrunerMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasPrunerMockRecorder is the mock recorder for MockAliasPruner.
+type MockAliasPrunerMockRecorder struct {
+	mock *MockAliasPruner
+}
+
+// NewMockAliasPruner creates a new mock instance.
+func NewMockAliasPruner(ctrl *gomock.Controller) *MockAliasPruner {
+	mock := &MockAliasPruner{ctrl: ctrl}
+	mock.recorder = &MockAliasPrunerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasPruner) EXPECT() *MockAliasPrunerMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockAliasPruner) List() ([]libs.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]libs.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAliasPrunerMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAliasPruner)(nil).List))
+}
+
+// Release mocks base method.
+func (m *MockAliasPruner) Release() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockAliasPrunerMockRecorder) Release() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockAliasPruner)(nil).Release))
+}
+
+// Remove mocks base method.
+func (m *MockAliasPruner) Remove(aliases ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range aliases {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Remove", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockAliasPrunerMockRecorder) Remove(aliases ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockAliasPruner)(nil).Remove), aliases...)
+}
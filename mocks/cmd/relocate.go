@@ -0,0 +1,168 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: relocate.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/relocate.go -package=mocks -source=relocate.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	libs "gs/libs"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepoFinder is a mock of RepoFinder interface.
+type MockRepoFinder struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoFinderMockRecorder
+	isgomock struct{}
+}
+
+// MockRepoFinderMockRecorder is the mock recorder for MockRepoFinder.
+type MockRepoFinderMockRecorder struct {
+	mock *MockRepoFinder
+}
+
+// NewMockRepoFinder creates a new mock instance.
+func NewMockRepoFinder(ctrl *gomock.Controller) *MockRepoFinder {
+	mock := &MockRepoFinder{ctrl: ctrl}
+	mock.recorder = &MockRepoFinderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoFinder) EXPECT() *MockRepoFinderMockRecorder {
+	return m.recorder
+}
+
+// CheckIfPathExists mocks base method.
+func (m *MockRepoFinder) CheckIfPathExists(path string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIfPathExists", path)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIfPathExists indicates an expected call of CheckIfPathExists.
+func (mr *MockRepoFinderMockRecorder) CheckIfPathExists(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfPathExists", reflect.TypeOf((*MockRepoFinder)(nil).CheckIfPathExists), path)
+}
+
+// FindRepositories mocks base method.
+func (m *MockRepoFinder) FindRepositories(root string, maxDepth int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRepositories", root, maxDepth)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRepositories indicates an expected call of FindRepositories.
+func (mr *MockRepoFinderMockRecorder) FindRepositories(root, maxDepth any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRepositories", reflect.TypeOf((*MockRepoFinder)(nil).FindRepositories), root, maxDepth)
+}
+
+// NormalizePath mocks base method.
+func (m *MockRepoFinder) NormalizePath(path string, resolveSymlinks bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NormalizePath", path, resolveSymlinks)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NormalizePath indicates an expected call of NormalizePath.
+func (mr *MockRepoFinderMockRecorder) NormalizePath(path, resolveSymlinks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NormalizePath", reflect.TypeOf((*MockRepoFinder)(nil).NormalizePath), path, resolveSymlinks)
+}
+
+// RepoIdentity mocks base method.
+func (m *MockRepoFinder) RepoIdentity(path string) (libs.RepoIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepoIdentity", path)
+	ret0, _ := ret[0].(libs.RepoIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepoIdentity indicates an expected call of RepoIdentity.
+func (mr *MockRepoFinderMockRecorder) RepoIdentity(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepoIdentity", reflect.TypeOf((*MockRepoFinder)(nil).RepoIdentity), path)
+}
+
+// MockAliasRelocator is a mock of AliasRelocator interface.
+type MockAliasRelocator struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasRelocatorMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasRelocatorMockRecorder is the mock recorder for MockAliasRelocator.
+type MockAliasRelocatorMockRecorder struct {
+	mock *MockAliasRelocator
+}
+
+// NewMockAliasRelocator creates a new mock instance.
+func NewMockAliasRelocator(ctrl *gomock.Controller) *MockAliasRelocator {
+	mock := &MockAliasRelocator{ctrl: ctrl}
+	mock.recorder = &MockAliasRelocatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasRelocator) EXPECT() *MockAliasRelocatorMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockAliasRelocator) List() ([]libs.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]libs.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAliasRelocatorMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAliasRelocator)(nil).List))
+}
+
+// Release mocks base method.
+func (m *MockAliasRelocator) Release() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockAliasRelocatorMockRecorder) Release() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockAliasRelocator)(nil).Release))
+}
+
+// Relocate mocks base method.
+func (m *MockAliasRelocator) Relocate(paths map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Relocate", paths)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Relocate indicates an expected call of Relocate.
+func (mr *MockAliasRelocatorMockRecorder) Relocate(paths any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Relocate", reflect.TypeOf((*MockAliasRelocator)(nil).Relocate), paths)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: remove.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/remove.go -package=mocks -source=remove.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAliasRemover is a mock of AliasRemover interface.
+type MockAliasRemover struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasRemoverMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasRemoverMockRecorder is the mock recorder for MockAliasRemover.
+type MockAliasRemoverMockRecorder struct {
+	mock *MockAliasRemover
+}
+
+// NewMockAliasRemover creates a new mock instance.
+func NewMockAliasRemover(ctrl *gomock.Controller) *MockAliasRemover {
+	mock := &MockAliasRemover{ctrl: ctrl}
+	mock.recorder = &MockAliasRemoverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasRemover) EXPECT() *MockAliasRemoverMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockAliasRemover) Get(alias string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", alias)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAliasRemoverMockRecorder) Get(alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAliasRemover)(nil).Get), alias)
+}
+
+// Release mocks base method.
+func (m *MockAliasRemover) Release() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockAliasRemoverMockRecorder) Release() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockAliasRemover)(nil).Release))
+}
+
+// Remove mocks base method.
+func (m *MockAliasRemover) Remove(aliases ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range aliases {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Remove", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockAliasRemoverMockRecorder) Remove(aliases ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockAliasRemover)(nil).Remove), aliases...)
+}
@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: rename.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/rename.go -package=mocks -source=rename.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAliasRenamer is a mock of AliasRenamer interface.
+type MockAliasRenamer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasRenamerMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasRenamerMockRecorder is the mock recorder for MockAliasRenamer.
+type MockAliasRenamerMockRecorder struct {
+	mock *MockAliasRenamer
+}
+
+// NewMockAliasRenamer creates a new mock instance.
+func NewMockAliasRenamer(ctrl *gomock.Controller) *MockAliasRenamer {
+	mock := &MockAliasRenamer{ctrl: ctrl}
+	mock.recorder = &MockAliasRenamerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasRenamer) EXPECT() *MockAliasRenamerMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockAliasRenamer) Get(alias string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", alias)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAliasRenamerMockRecorder) Get(alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAliasRenamer)(nil).Get), alias)
+}
+
+// Rename mocks base method.
+func (m *MockAliasRenamer) Rename(oldAlias, newAlias string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", oldAlias, newAlias)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockAliasRenamerMockRecorder) Rename(oldAlias, newAlias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockAliasRenamer)(nil).Rename), oldAlias, newAlias)
+}
@@ -0,0 +1,595 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: root.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/root.go -package=mocks -source=root.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	libs "gs/libs"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDBReleaser is a mock of DBReleaser interface.
+type MockDBReleaser struct {
+	ctrl     *gomock.Controller
+	recorder *MockDBReleaserMockRecorder
+	isgomock struct{}
+}
+
+// MockDBReleaserMockRecorder is the mock recorder for MockDBReleaser.
+type MockDBReleaserMockRecorder struct {
+	mock *MockDBReleaser
+}
+
+// NewMockDBReleaser creates a new mock instance.
+func NewMockDBReleaser(ctrl *gomock.Controller) *MockDBReleaser {
+	mock := &MockDBReleaser{ctrl: ctrl}
+	mock.recorder = &MockDBReleaserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDBReleaser) EXPECT() *MockDBReleaserMockRecorder {
+	return m.recorder
+}
+
+// Release mocks base method.
+func (m *MockDBReleaser) Release() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockDBReleaserMockRecorder) Release() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockDBReleaser)(nil).Release))
+}
+
+// MockRootDBService is a mock of RootDBService interface.
+type MockRootDBService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRootDBServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockRootDBServiceMockRecorder is the mock recorder for MockRootDBService.
+type MockRootDBServiceMockRecorder struct {
+	mock *MockRootDBService
+}
+
+// NewMockRootDBService creates a new mock instance.
+func NewMockRootDBService(ctrl *gomock.Controller) *MockRootDBService {
+	mock := &MockRootDBService{ctrl: ctrl}
+	mock.recorder = &MockRootDBServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRootDBService) EXPECT() *MockRootDBServiceMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockRootDBService) Add(alias, path string, opts libs.AddOptions) (libs.AddResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", alias, path, opts)
+	ret0, _ := ret[0].(libs.AddResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockRootDBServiceMockRecorder) Add(alias, path, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockRootDBService)(nil).Add), alias, path, opts)
+}
+
+// AddAll mocks base method.
+func (m *MockRootDBService) AddAll(entries []libs.Entry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAll", entries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAll indicates an expected call of AddAll.
+func (mr *MockRootDBServiceMockRecorder) AddAll(entries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAll", reflect.TypeOf((*MockRootDBService)(nil).AddAll), entries)
+}
+
+// CheckProfile mocks base method.
+func (m *MockRootDBService) CheckProfile() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckProfile")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckProfile indicates an expected call of CheckProfile.
+func (mr *MockRootDBServiceMockRecorder) CheckProfile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckProfile", reflect.TypeOf((*MockRootDBService)(nil).CheckProfile))
+}
+
+// CopyProfile mocks base method.
+func (m *MockRootDBService) CopyProfile(from, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyProfile", from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyProfile indicates an expected call of CopyProfile.
+func (mr *MockRootDBServiceMockRecorder) CopyProfile(from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyProfile", reflect.TypeOf((*MockRootDBService)(nil).CopyProfile), from, to)
+}
+
+// CreateProfile mocks base method.
+func (m *MockRootDBService) CreateProfile(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProfile", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProfile indicates an expected call of CreateProfile.
+func (mr *MockRootDBServiceMockRecorder) CreateProfile(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProfile", reflect.TypeOf((*MockRootDBService)(nil).CreateProfile), name)
+}
+
+// DeleteProfile mocks base method.
+func (m *MockRootDBService) DeleteProfile(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProfile", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProfile indicates an expected call of DeleteProfile.
+func (mr *MockRootDBServiceMockRecorder) DeleteProfile(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProfile", reflect.TypeOf((*MockRootDBService)(nil).DeleteProfile), name)
+}
+
+// ForEach mocks base method.
+func (m *MockRootDBService) ForEach(fn func(libs.Entry) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEach", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEach indicates an expected call of ForEach.
+func (mr *MockRootDBServiceMockRecorder) ForEach(fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEach", reflect.TypeOf((*MockRootDBService)(nil).ForEach), fn)
+}
+
+// Get mocks base method.
+func (m *MockRootDBService) Get(alias string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", alias)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRootDBServiceMockRecorder) Get(alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRootDBService)(nil).Get), alias)
+}
+
+// List mocks base method.
+func (m *MockRootDBService) List() ([]libs.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]libs.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRootDBServiceMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRootDBService)(nil).List))
+}
+
+// Match mocks base method.
+func (m *MockRootDBService) Match(query string) (libs.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Match", query)
+	ret0, _ := ret[0].(libs.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Match indicates an expected call of Match.
+func (mr *MockRootDBServiceMockRecorder) Match(query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Match", reflect.TypeOf((*MockRootDBService)(nil).Match), query)
+}
+
+// Move mocks base method.
+func (m *MockRootDBService) Move(alias, path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", alias, path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockRootDBServiceMockRecorder) Move(alias, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockRootDBService)(nil).Move), alias, path)
+}
+
+// PreviousAlias mocks base method.
+func (m *MockRootDBService) PreviousAlias() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviousAlias")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviousAlias indicates an expected call of PreviousAlias.
+func (mr *MockRootDBServiceMockRecorder) PreviousAlias() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviousAlias", reflect.TypeOf((*MockRootDBService)(nil).PreviousAlias))
+}
+
+// Profile mocks base method.
+func (m *MockRootDBService) Profile() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Profile")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Profile indicates an expected call of Profile.
+func (mr *MockRootDBServiceMockRecorder) Profile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Profile", reflect.TypeOf((*MockRootDBService)(nil).Profile))
+}
+
+// ProfileSelection mocks base method.
+func (m *MockRootDBService) ProfileSelection() libs.ProfileSelection {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProfileSelection")
+	ret0, _ := ret[0].(libs.ProfileSelection)
+	return ret0
+}
+
+// ProfileSelection indicates an expected call of ProfileSelection.
+func (mr *MockRootDBServiceMockRecorder) ProfileSelection() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProfileSelection", reflect.TypeOf((*MockRootDBService)(nil).ProfileSelection))
+}
+
+// Profiles mocks base method.
+func (m *MockRootDBService) Profiles() ([]libs.ProfileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Profiles")
+	ret0, _ := ret[0].([]libs.ProfileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Profiles indicates an expected call of Profiles.
+func (mr *MockRootDBServiceMockRecorder) Profiles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Profiles", reflect.TypeOf((*MockRootDBService)(nil).Profiles))
+}
+
+// Release mocks base method.
+func (m *MockRootDBService) Release() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockRootDBServiceMockRecorder) Release() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockRootDBService)(nil).Release))
+}
+
+// Relocate mocks base method.
+func (m *MockRootDBService) Relocate(paths map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Relocate", paths)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Relocate indicates an expected call of Relocate.
+func (mr *MockRootDBServiceMockRecorder) Relocate(paths any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Relocate", reflect.TypeOf((*MockRootDBService)(nil).Relocate), paths)
+}
+
+// Remove mocks base method.
+func (m *MockRootDBService) Remove(aliases ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range aliases {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Remove", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockRootDBServiceMockRecorder) Remove(aliases ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockRootDBService)(nil).Remove), aliases...)
+}
+
+// Rename mocks base method.
+func (m *MockRootDBService) Rename(oldAlias, newAlias string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", oldAlias, newAlias)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockRootDBServiceMockRecorder) Rename(oldAlias, newAlias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockRootDBService)(nil).Rename), oldAlias, newAlias)
+}
+
+// RenameProfile mocks base method.
+func (m *MockRootDBService) RenameProfile(oldName, newName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameProfile", oldName, newName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameProfile indicates an expected call of RenameProfile.
+func (mr *MockRootDBServiceMockRecorder) RenameProfile(oldName, newName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameProfile", reflect.TypeOf((*MockRootDBService)(nil).RenameProfile), oldName, newName)
+}
+
+// Tag mocks base method.
+func (m *MockRootDBService) Tag(tag string, aliases ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{tag}
+	for _, a := range aliases {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Tag", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Tag indicates an expected call of Tag.
+func (mr *MockRootDBServiceMockRecorder) Tag(tag any, aliases ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{tag}, aliases...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tag", reflect.TypeOf((*MockRootDBService)(nil).Tag), varargs...)
+}
+
+// Top mocks base method.
+func (m *MockRootDBService) Top(limit int) ([]libs.ScoredEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Top", limit)
+	ret0, _ := ret[0].([]libs.ScoredEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Top indicates an expected call of Top.
+func (mr *MockRootDBServiceMockRecorder) Top(limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Top", reflect.TypeOf((*MockRootDBService)(nil).Top), limit)
+}
+
+// Untag mocks base method.
+func (m *MockRootDBService) Untag(tag string, aliases ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{tag}
+	for _, a := range aliases {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Untag", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Untag indicates an expected call of Untag.
+func (mr *MockRootDBServiceMockRecorder) Untag(tag any, aliases ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{tag}, aliases...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Untag", reflect.TypeOf((*MockRootDBService)(nil).Untag), varargs...)
+}
+
+// Visit mocks base method.
+func (m *MockRootDBService) Visit(alias string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Visit", alias)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Visit indicates an expected call of Visit.
+func (mr *MockRootDBServiceMockRecorder) Visit(alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Visit", reflect.TypeOf((*MockRootDBService)(nil).Visit), alias)
+}
+
+// MockRootFileService is a mock of RootFileService interface.
+type MockRootFileService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRootFileServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockRootFileServiceMockRecorder is the mock recorder for MockRootFileService.
+type MockRootFileServiceMockRecorder struct {
+	mock *MockRootFileService
+}
+
+// NewMockRootFileService creates a new mock instance.
+func NewMockRootFileService(ctrl *gomock.Controller) *MockRootFileService {
+	mock := &MockRootFileService{ctrl: ctrl}
+	mock.recorder = &MockRootFileServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRootFileService) EXPECT() *MockRootFileServiceMockRecorder {
+	return m.recorder
+}
+
+// CheckIfPathExists mocks base method.
+func (m *MockRootFileService) CheckIfPathExists(path string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIfPathExists", path)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIfPathExists indicates an expected call of CheckIfPathExists.
+func (mr *MockRootFileServiceMockRecorder) CheckIfPathExists(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfPathExists", reflect.TypeOf((*MockRootFileService)(nil).CheckIfPathExists), path)
+}
+
+// DetectGitRepository mocks base method.
+func (m *MockRootFileService) DetectGitRepository(path string) (libs.GitRepository, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectGitRepository", path)
+	ret0, _ := ret[0].(libs.GitRepository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectGitRepository indicates an expected call of DetectGitRepository.
+func (mr *MockRootFileServiceMockRecorder) DetectGitRepository(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectGitRepository", reflect.TypeOf((*MockRootFileService)(nil).DetectGitRepository), path)
+}
+
+// FindRepoRoot mocks base method.
+func (m *MockRootFileService) FindRepoRoot(path string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRepoRoot", path)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRepoRoot indicates an expected call of FindRepoRoot.
+func (mr *MockRootFileServiceMockRecorder) FindRepoRoot(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRepoRoot", reflect.TypeOf((*MockRootFileService)(nil).FindRepoRoot), path)
+}
+
+// FindRepositories mocks base method.
+func (m *MockRootFileService) FindRepositories(root string, maxDepth int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRepositories", root, maxDepth)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRepositories indicates an expected call of FindRepositories.
+func (mr *MockRootFileServiceMockRecorder) FindRepositories(root, maxDepth any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRepositories", reflect.TypeOf((*MockRootFileService)(nil).FindRepositories), root, maxDepth)
+}
+
+// GetCurrentPath mocks base method.
+func (m *MockRootFileService) GetCurrentPath() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentPath")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentPath indicates an expected call of GetCurrentPath.
+func (mr *MockRootFileServiceMockRecorder) GetCurrentPath() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentPath", reflect.TypeOf((*MockRootFileService)(nil).GetCurrentPath))
+}
+
+// GetFolderName mocks base method.
+func (m *MockRootFileService) GetFolderName(path string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFolderName", path)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetFolderName indicates an expected call of GetFolderName.
+func (mr *MockRootFileServiceMockRecorder) GetFolderName(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFolderName", reflect.TypeOf((*MockRootFileService)(nil).GetFolderName), path)
+}
+
+// GitBranch mocks base method.
+func (m *MockRootFileService) GitBranch(path string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GitBranch", path)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GitBranch indicates an expected call of GitBranch.
+func (mr *MockRootFileServiceMockRecorder) GitBranch(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GitBranch", reflect.TypeOf((*MockRootFileService)(nil).GitBranch), path)
+}
+
+// NormalizePath mocks base method.
+func (m *MockRootFileService) NormalizePath(path string, resolveSymlinks bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NormalizePath", path, resolveSymlinks)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NormalizePath indicates an expected call of NormalizePath.
+func (mr *MockRootFileServiceMockRecorder) NormalizePath(path, resolveSymlinks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NormalizePath", reflect.TypeOf((*MockRootFileService)(nil).NormalizePath), path, resolveSymlinks)
+}
+
+// RepoIdentity mocks base method.
+func (m *MockRootFileService) RepoIdentity(path string) (libs.RepoIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepoIdentity", path)
+	ret0, _ := ret[0].(libs.RepoIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepoIdentity indicates an expected call of RepoIdentity.
+func (mr *MockRootFileServiceMockRecorder) RepoIdentity(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepoIdentity", reflect.TypeOf((*MockRootFileService)(nil).RepoIdentity), path)
+}
@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: scan.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/scan.go -package=mocks -source=scan.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	libs "gs/libs"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepoScanner is a mock of RepoScanner interface.
+type MockRepoScanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoScannerMockRecorder
+	isgomock struct{}
+}
+
+// MockRepoScannerMockRecorder is the mock recorder for MockRepoScanner.
+type MockRepoScannerMockRecorder struct {
+	mock *MockRepoScanner
+}
+
+// NewMockRepoScanner creates a new mock instance.
+func NewMockRepoScanner(ctrl *gomock.Controller) *MockRepoScanner {
+	mock := &MockRepoScanner{ctrl: ctrl}
+	mock.recorder = &MockRepoScannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoScanner) EXPECT() *MockRepoScannerMockRecorder {
+	return m.recorder
+}
+
+// FindRepositories mocks base method.
+func (m *MockRepoScanner) FindRepositories(root string, maxDepth int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRepositories", root, maxDepth)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRepositories indicates an expected call of FindRepositories.
+func (mr *MockRepoScannerMockRecorder) FindRepositories(root, maxDepth any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRepositories", reflect.TypeOf((*MockRepoScanner)(nil).FindRepositories), root, maxDepth)
+}
+
+// NormalizePath mocks base method.
+func (m *MockRepoScanner) NormalizePath(path string, resolveSymlinks bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NormalizePath", path, resolveSymlinks)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NormalizePath indicates an expected call of NormalizePath.
+func (mr *MockRepoScannerMockRecorder) NormalizePath(path, resolveSymlinks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NormalizePath", reflect.TypeOf((*MockRepoScanner)(nil).NormalizePath), path, resolveSymlinks)
+}
+
+// RepoIdentity mocks base method.
+func (m *MockRepoScanner) RepoIdentity(path string) (libs.RepoIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepoIdentity", path)
+	ret0, _ := ret[0].(libs.RepoIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepoIdentity indicates an expected call of RepoIdentity.
+func (mr *MockRepoScannerMockRecorder) RepoIdentity(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepoIdentity", reflect.TypeOf((*MockRepoScanner)(nil).RepoIdentity), path)
+}
+
+// MockAliasBulkAdder is a mock of AliasBulkAdder interface.
+type MockAliasBulkAdder struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasBulkAdderMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasBulkAdderMockRecorder is the mock recorder for MockAliasBulkAdder.
+type MockAliasBulkAdderMockRecorder struct {
+	mock *MockAliasBulkAdder
+}
+
+// NewMockAliasBulkAdder creates a new mock instance.
+func NewMockAliasBulkAdder(ctrl *gomock.Controller) *MockAliasBulkAdder {
+	mock := &MockAliasBulkAdder{ctrl: ctrl}
+	mock.recorder = &MockAliasBulkAdderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasBulkAdder) EXPECT() *MockAliasBulkAdderMockRecorder {
+	return m.recorder
+}
+
+// AddAll mocks base method.
+func (m *MockAliasBulkAdder) AddAll(entries []libs.Entry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAll", entries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAll indicates an expected call of AddAll.
+func (mr *MockAliasBulkAdderMockRecorder) AddAll(entries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAll", reflect.TypeOf((*MockAliasBulkAdder)(nil).AddAll), entries)
+}
+
+// List mocks base method.
+func (m *MockAliasBulkAdder) List() ([]libs.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]libs.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAliasBulkAdderMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAliasBulkAdder)(nil).List))
+}
+
+// Release mocks base method.
+func (m *MockAliasBulkAdder) Release() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockAliasBulkAdderMockRecorder) Release() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockAliasBulkAdder)(nil).Release))
+}
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: status.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/status.go -package=mocks -source=status.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	libs "gs/libs"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStatusReader is a mock of StatusReader interface.
+type MockStatusReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatusReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockStatusReaderMockRecorder is the mock recorder for MockStatusReader.
+type MockStatusReaderMockRecorder struct {
+	mock *MockStatusReader
+}
+
+// NewMockStatusReader creates a new mock instance.
+func NewMockStatusReader(ctrl *gomock.Controller) *MockStatusReader {
+	mock := &MockStatusReader{ctrl: ctrl}
+	mock.recorder = &MockStatusReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatusReader) EXPECT() *MockStatusReaderMockRecorder {
+	return m.recorder
+}
+
+// Status mocks base method.
+func (m *MockStatusReader) Status(ctx context.Context, projects []libs.Entry, opts libs.StatusOptions) []libs.RepoStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", ctx, projects, opts)
+	ret0, _ := ret[0].([]libs.RepoStatus)
+	return ret0
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockStatusReaderMockRecorder) Status(ctx, projects, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockStatusReader)(nil).Status), ctx, projects, opts)
+}
+
+// MockStatusSource is a mock of StatusSource interface.
+type MockStatusSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatusSourceMockRecorder
+	isgomock struct{}
+}
+
+// MockStatusSourceMockRecorder is the mock recorder for MockStatusSource.
+type MockStatusSourceMockRecorder struct {
+	mock *MockStatusSource
+}
+
+// NewMockStatusSource creates a new mock instance.
+func NewMockStatusSource(ctrl *gomock.Controller) *MockStatusSource {
+	mock := &MockStatusSource{ctrl: ctrl}
+	mock.recorder = &MockStatusSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatusSource) EXPECT() *MockStatusSourceMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockStatusSource) List() ([]libs.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]libs.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockStatusSourceMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockStatusSource)(nil).List))
+}
+
+// Release mocks base method.
+func (m *MockStatusSource) Release() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockStatusSourceMockRecorder) Release() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockStatusSource)(nil).Release))
+}
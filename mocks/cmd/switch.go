@@ -0,0 +1,218 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: switch.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/switch.go -package=mocks -source=switch.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	libs "gs/libs"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAliasResolver is a mock of AliasResolver interface.
+type MockAliasResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasResolverMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasResolverMockRecorder is the mock recorder for MockAliasResolver.
+type MockAliasResolverMockRecorder struct {
+	mock *MockAliasResolver
+}
+
+// NewMockAliasResolver creates a new mock instance.
+func NewMockAliasResolver(ctrl *gomock.Controller) *MockAliasResolver {
+	mock := &MockAliasResolver{ctrl: ctrl}
+	mock.recorder = &MockAliasResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasResolver) EXPECT() *MockAliasResolverMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockAliasResolver) Get(alias string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", alias)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAliasResolverMockRecorder) Get(alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAliasResolver)(nil).Get), alias)
+}
+
+// MockAliasVisitor is a mock of AliasVisitor interface.
+type MockAliasVisitor struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasVisitorMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasVisitorMockRecorder is the mock recorder for MockAliasVisitor.
+type MockAliasVisitorMockRecorder struct {
+	mock *MockAliasVisitor
+}
+
+// NewMockAliasVisitor creates a new mock instance.
+func NewMockAliasVisitor(ctrl *gomock.Controller) *MockAliasVisitor {
+	mock := &MockAliasVisitor{ctrl: ctrl}
+	mock.recorder = &MockAliasVisitorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasVisitor) EXPECT() *MockAliasVisitorMockRecorder {
+	return m.recorder
+}
+
+// PreviousAlias mocks base method.
+func (m *MockAliasVisitor) PreviousAlias() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviousAlias")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviousAlias indicates an expected call of PreviousAlias.
+func (mr *MockAliasVisitorMockRecorder) PreviousAlias() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviousAlias", reflect.TypeOf((*MockAliasVisitor)(nil).PreviousAlias))
+}
+
+// Visit mocks base method.
+func (m *MockAliasVisitor) Visit(alias string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Visit", alias)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Visit indicates an expected call of Visit.
+func (mr *MockAliasVisitorMockRecorder) Visit(alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Visit", reflect.TypeOf((*MockAliasVisitor)(nil).Visit), alias)
+}
+
+// MockAliasMatcher is a mock of AliasMatcher interface.
+type MockAliasMatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasMatcherMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasMatcherMockRecorder is the mock recorder for MockAliasMatcher.
+type MockAliasMatcherMockRecorder struct {
+	mock *MockAliasMatcher
+}
+
+// NewMockAliasMatcher creates a new mock instance.
+func NewMockAliasMatcher(ctrl *gomock.Controller) *MockAliasMatcher {
+	mock := &MockAliasMatcher{ctrl: ctrl}
+	mock.recorder = &MockAliasMatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasMatcher) EXPECT() *MockAliasMatcherMockRecorder {
+	return m.recorder
+}
+
+// Match mocks base method.
+func (m *MockAliasMatcher) Match(query string) (libs.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Match", query)
+	ret0, _ := ret[0].(libs.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Match indicates an expected call of Match.
+func (mr *MockAliasMatcherMockRecorder) Match(query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Match", reflect.TypeOf((*MockAliasMatcher)(nil).Match), query)
+}
+
+// MockAliasSwitcher is a mock of AliasSwitcher interface.
+type MockAliasSwitcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasSwitcherMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasSwitcherMockRecorder is the mock recorder for MockAliasSwitcher.
+type MockAliasSwitcherMockRecorder struct {
+	mock *MockAliasSwitcher
+}
+
+// NewMockAliasSwitcher creates a new mock instance.
+func NewMockAliasSwitcher(ctrl *gomock.Controller) *MockAliasSwitcher {
+	mock := &MockAliasSwitcher{ctrl: ctrl}
+	mock.recorder = &MockAliasSwitcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasSwitcher) EXPECT() *MockAliasSwitcherMockRecorder {
+	return m.recorder
+}
+
+// Match mocks base method.
+func (m *MockAliasSwitcher) Match(query string) (libs.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Match", query)
+	ret0, _ := ret[0].(libs.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Match indicates an expected call of Match.
+func (mr *MockAliasSwitcherMockRecorder) Match(query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Match", reflect.TypeOf((*MockAliasSwitcher)(nil).Match), query)
+}
+
+// PreviousAlias mocks base method.
+func (m *MockAliasSwitcher) PreviousAlias() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviousAlias")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviousAlias indicates an expected call of PreviousAlias.
+func (mr *MockAliasSwitcherMockRecorder) PreviousAlias() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviousAlias", reflect.TypeOf((*MockAliasSwitcher)(nil).PreviousAlias))
+}
+
+// Visit mocks base method.
+func (m *MockAliasSwitcher) Visit(alias string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Visit", alias)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Visit indicates an expected call of Visit.
+func (mr *MockAliasSwitcherMockRecorder) Visit(alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Visit", reflect.TypeOf((*MockAliasSwitcher)(nil).Visit), alias)
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tag.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/tag.go -package=mocks -source=tag.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	libs "gs/libs"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAliasTagger is a mock of AliasTagger interface.
+type MockAliasTagger struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasTaggerMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasTaggerMockRecorder is the mock recorder for MockAliasTagger.
+type MockAliasTaggerMockRecorder struct {
+	mock *MockAliasTagger
+}
+
+// NewMockAliasTagger creates a new mock instance.
+func NewMockAliasTagger(ctrl *gomock.Controller) *MockAliasTagger {
+	mock := &MockAliasTagger{ctrl: ctrl}
+	mock.recorder = &MockAliasTaggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasTagger) EXPECT() *MockAliasTaggerMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockAliasTagger) List() ([]libs.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]libs.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAliasTaggerMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAliasTagger)(nil).List))
+}
+
+// Tag mocks base method.
+func (m *MockAliasTagger) Tag(tag string, aliases ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{tag}
+	for _, a := range aliases {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Tag", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Tag indicates an expected call of Tag.
+func (mr *MockAliasTaggerMockRecorder) Tag(tag any, aliases ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{tag}, aliases...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tag", reflect.TypeOf((*MockAliasTagger)(nil).Tag), varargs...)
+}
+
+// Untag mocks base method.
+func (m *MockAliasTagger) Untag(tag string, aliases ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{tag}
+	for _, a := range aliases {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Untag", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Untag indicates an expected call of Untag.
+func (mr *MockAliasTaggerMockRecorder) Untag(tag any, aliases ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{tag}, aliases...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Untag", reflect.TypeOf((*MockAliasTagger)(nil).Untag), varargs...)
+}
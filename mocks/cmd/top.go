@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: top.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/cmd/top.go -package=mocks -source=top.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	libs "gs/libs"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAliasRanker is a mock of AliasRanker interface.
+type MockAliasRanker struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasRankerMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasRankerMockRecorder is the mock recorder for MockAliasRanker.
+type MockAliasRankerMockRecorder struct {
+	mock *MockAliasRanker
+}
+
+// NewMockAliasRanker creates a new mock instance.
+func NewMockAliasRanker(ctrl *gomock.Controller) *MockAliasRanker {
+	mock := &MockAliasRanker{ctrl: ctrl}
+	mock.recorder = &MockAliasRankerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliasRanker) EXPECT() *MockAliasRankerMockRecorder {
+	return m.recorder
+}
+
+// Top mocks base method.
+func (m *MockAliasRanker) Top(limit int) ([]libs.ScoredEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Top", limit)
+	ret0, _ := ret[0].([]libs.ScoredEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Top indicates an expected call of Top.
+func (mr *MockAliasRankerMockRecorder) Top(limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Top", reflect.TypeOf((*MockAliasRanker)(nil).Top), limit)
+}
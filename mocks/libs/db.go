@@ -120,6 +120,49 @@ func (mr *MockTxMockRecorder) Bucket(name any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bucket", reflect.TypeOf((*MockTx)(nil).Bucket), name)
 }
 
+// CreateBucket mocks base method.
+func (m *MockTx) CreateBucket(name []byte) (libs.Bucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBucket", name)
+	ret0, _ := ret[0].(libs.Bucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBucket indicates an expected call of CreateBucket.
+func (mr *MockTxMockRecorder) CreateBucket(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBucket", reflect.TypeOf((*MockTx)(nil).CreateBucket), name)
+}
+
+// DeleteBucket mocks base method.
+func (m *MockTx) DeleteBucket(name []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBucket", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBucket indicates an expected call of DeleteBucket.
+func (mr *MockTxMockRecorder) DeleteBucket(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBucket", reflect.TypeOf((*MockTx)(nil).DeleteBucket), name)
+}
+
+// ForEachBucket mocks base method.
+func (m *MockTx) ForEachBucket(fn func([]byte) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEachBucket", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEachBucket indicates an expected call of ForEachBucket.
+func (mr *MockTxMockRecorder) ForEachBucket(fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEachBucket", reflect.TypeOf((*MockTx)(nil).ForEachBucket), fn)
+}
+
 // MockBucket is a mock of Bucket interface.
 type MockBucket struct {
 	ctrl     *gomock.Controller
@@ -144,6 +187,34 @@ func (m *MockBucket) EXPECT() *MockBucketMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockBucket) Delete(key []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBucketMockRecorder) Delete(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBucket)(nil).Delete), key)
+}
+
+// ForEach mocks base method.
+func (m *MockBucket) ForEach(fn func([]byte, []byte) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEach", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEach indicates an expected call of ForEach.
+func (mr *MockBucketMockRecorder) ForEach(fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEach", reflect.TypeOf((*MockBucket)(nil).ForEach), fn)
+}
+
 // Get mocks base method.
 func (m *MockBucket) Get(key []byte) []byte {
 	m.ctrl.T.Helper()